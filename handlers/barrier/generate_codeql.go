@@ -0,0 +1,381 @@
+package barrier
+
+import (
+	"github.com/gagliardetto/codemill/x"
+	. "github.com/gagliardetto/cqlgen/jen"
+	"github.com/gagliardetto/feparser"
+	. "github.com/gagliardetto/utilz"
+)
+
+func (han *Handler) GenerateCodeQL(impAdder x.ImportAdder, mdl *x.XModel, rootModuleGroup *Group) error {
+	if err := mdl.Validate(); err != nil {
+		return err
+	}
+	if err := han.Validate(mdl); err != nil {
+		return err
+	}
+
+	// Assuming the validation has already been done:
+	self := mdl.Methods[0]
+
+	if len(self.Selectors) == 0 {
+		Infof("No selectors found for %q method.", self.Name)
+		return nil
+	}
+
+	families := GetQueryFamilies(mdl)
+	{
+		// Add imports:
+		for _, family := range families {
+			impAdder.Import(QueryFamilies[family])
+		}
+	}
+
+	className := mdl.Name
+	allPathVersions := mdl.ListAllPathVersions()
+
+	b2fe, b2tm, b2itm, err := x.GroupFuncSelectors(self)
+	if err != nil {
+		Fatalf("Error while GroupFuncSelectors: %s", err)
+	}
+	funcModelsClassName := feparser.NewCodeQlName(className, "FunctionModels")
+	methodModelsClassName := feparser.NewCodeQlName(className, "MethodModels")
+	modelsClassNames := make([]string, 0)
+	{
+		addedCount := 0
+		tmp := DoGroup(func(tempFuncsModel *Group) {
+			tempFuncsModel.Doc("Models functions whose outputs are barriers.")
+			tempFuncsModel.Private().Class().Id(funcModelsClassName).Extends().Id("Function").BlockFunc(
+				func(funcModelsClassGroup *Group) {
+					funcModelsClassGroup.Id("FunctionInput").Id("inp").Semicolon().Line()
+					funcModelsClassGroup.Id("FunctionOutput").Id("out").Semicolon().Line()
+
+					funcModelsClassGroup.Id(funcModelsClassName).Call().BlockFunc(
+						func(funcModelsSelfMethodGroup *Group) {
+							{
+								funcModelsSelfMethodGroup.DoGroup(
+									func(groupCase *Group) {
+										for _, pathVersion := range allPathVersions {
+											cont, ok := b2fe[pathVersion]
+											if ok {
+												pathCodez := make([]Code, 0)
+												for _, funcQual := range cont {
+													if !x.HasValidEnabledFlow(funcQual) {
+														continue
+													}
+
+													fn, codeElements := GetFuncQualifierCodeElements(funcQual)
+													thing := fn.(*feparser.FEFunc)
+													pathCodez = append(pathCodez,
+														ParensFunc(
+															func(par *Group) {
+																par.Commentf("signature: %s", thing.Signature)
+																par.This().Dot("hasQualifiedName").Call(x.CqlFormatPackagePath(funcQual.Path), Lit(thing.Name))
+																par.And()
+
+																joined := Join(
+																	Or(),
+																	codeElements...,
+																)
+																if len(codeElements) > 1 {
+																	par.Parens(
+																		joined,
+																	)
+																} else {
+																	par.Add(joined)
+																}
+															},
+														),
+													)
+												}
+
+												if len(pathCodez) > 0 {
+													if addedCount > 0 {
+														groupCase.Or()
+													}
+													groupCase.Commentf("Barrier models for package: %s", pathVersion).Parens(
+														Join(
+															Or(),
+															pathCodez...,
+														),
+													)
+													addedCount++
+												}
+											}
+										}
+									})
+							}
+						})
+
+					funcModelsClassGroup.Doc("Holds if the taint flowing from `input` to `output` is stopped at `output`.")
+					funcModelsClassGroup.Predicate().Id("hasBarrierFlow").Call(Id("FunctionInput").Id("input"), Id("FunctionOutput").Id("output")).BlockFunc(
+						func(overrideBlockGroup *Group) {
+							overrideBlockGroup.Id("input").Eq().Id("inp").And().Id("output").Eq().Id("out")
+						})
+				})
+		})
+		if addedCount > 0 {
+			rootModuleGroup.Add(tmp)
+			modelsClassNames = append(modelsClassNames, funcModelsClassName)
+		}
+	}
+
+	{
+		addedCount := 0
+		tmp := DoGroup(func(tempMethodsModel *Group) {
+			tempMethodsModel.Doc("Models methods whose outputs are barriers.")
+			tempMethodsModel.Private().Class().Id(methodModelsClassName).Extends().Id("Method").BlockFunc(
+				func(methodModelsClassGroup *Group) {
+					methodModelsClassGroup.Id("FunctionInput").Id("inp").Semicolon().Line()
+					methodModelsClassGroup.Id("FunctionOutput").Id("out").Semicolon().Line()
+
+					methodModelsClassGroup.Id(methodModelsClassName).Call().BlockFunc(
+						func(methodModelsSelfMethodGroup *Group) {
+							{
+								methodModelsSelfMethodGroup.DoGroup(
+									func(groupCase *Group) {
+										for _, pathVersion := range allPathVersions {
+											pathCodez := make([]Code, 0)
+											{
+												b2tm.IterValid(pathVersion,
+													func(receiverTypeID string, methodQualifiers x.FuncQualifierSlice) {
+														codez := DoGroup(func(mtdGroup *Group) {
+															qual := methodQualifiers[0]
+															source := x.GetCachedSource(qual.Path, qual.Version)
+															if source == nil {
+																Fatalf("Source not found: %s@%s", qual.Path, qual.Version)
+															}
+															// Find receiver type:
+															typ := x.FindTypeByID(source, receiverTypeID)
+															if typ == nil {
+																Fatalf("Type not found: %q", receiverTypeID)
+															}
+
+															mtdGroup.Commentf("Receiver type: %s", typ.TypeString)
+
+															methodIndex := 0
+															mtdGroup.ParensFunc(
+																func(parMethods *Group) {
+																	for _, methodQual := range methodQualifiers {
+																		if !methodQual.Flows.Enabled || x.AllBlocksEmpty(methodQual.Flows.Blocks...) {
+																			continue
+																		}
+																		if methodIndex > 0 {
+																			parMethods.Or()
+																		}
+																		methodIndex++
+
+																		fn, codeElements := GetFuncQualifierCodeElements(methodQual)
+																		thing := fn.(*feparser.FETypeMethod)
+
+																		parMethods.ParensFunc(
+																			func(par *Group) {
+																				par.Commentf("signature: %s", thing.Func.Signature)
+																				par.This().Dot("hasQualifiedName").Call(x.CqlFormatPackagePath(methodQual.Path), Lit(thing.Receiver.TypeName), Lit(thing.Func.Name))
+																				par.And()
+
+																				joined := Join(
+																					Or(),
+																					codeElements...,
+																				)
+																				if len(codeElements) > 1 {
+																					par.Parens(
+																						joined,
+																					)
+																				} else {
+																					par.Add(joined)
+																				}
+																			},
+																		)
+
+																	}
+																},
+															)
+
+														})
+														pathCodez = append(pathCodez, codez)
+													})
+											}
+
+											b2itm.IterValid(pathVersion,
+												func(receiverTypeID string, methodQualifiers x.FuncQualifierSlice) {
+													codez := DoGroup(func(mtdGroup *Group) {
+														qual := methodQualifiers[0]
+														source := x.GetCachedSource(qual.Path, qual.Version)
+														if source == nil {
+															Fatalf("Source not found: %s@%s", qual.Path, qual.Version)
+														}
+														// Find receiver type:
+														typ := x.FindTypeByID(source, receiverTypeID)
+														if typ == nil {
+															Fatalf("Type not found: %q", receiverTypeID)
+														}
+														mtdGroup.Commentf("Receiver interface: %s", typ.TypeString)
+
+														methodIndex := 0
+														mtdGroup.ParensFunc(
+															func(parMethods *Group) {
+																for _, methodQual := range methodQualifiers {
+																	if !methodQual.Flows.Enabled || x.AllBlocksEmpty(methodQual.Flows.Blocks...) {
+																		continue
+																	}
+																	if methodIndex > 0 {
+																		parMethods.Or()
+																	}
+																	methodIndex++
+
+																	fn, codeElements := GetFuncQualifierCodeElements(methodQual)
+																	thing := fn.(*feparser.FEInterfaceMethod)
+
+																	parMethods.ParensFunc(
+																		func(par *Group) {
+																			par.Commentf("signature: %s", thing.Func.Signature)
+																			par.This().Dot("implements").Call(x.CqlFormatPackagePath(methodQual.Path), Lit(thing.Receiver.TypeName), Lit(thing.Func.Name))
+																			par.And()
+
+																			joined := Join(
+																				Or(),
+																				codeElements...,
+																			)
+																			if len(codeElements) > 1 {
+																				par.Parens(
+																					joined,
+																				)
+																			} else {
+																				par.Add(joined)
+																			}
+																		},
+																	)
+
+																}
+															},
+														)
+
+													})
+													pathCodez = append(pathCodez, codez)
+												})
+
+											if len(pathCodez) > 0 {
+												if addedCount > 0 {
+													groupCase.Or()
+												}
+												groupCase.Commentf("Barrier models for package: %s", pathVersion).Parens(
+													Join(
+														Or(),
+														pathCodez...,
+													),
+												)
+												addedCount++
+											}
+										}
+									})
+							}
+						})
+
+					methodModelsClassGroup.Doc("Holds if the taint flowing from `input` to `output` is stopped at `output`.")
+					methodModelsClassGroup.Predicate().Id("hasBarrierFlow").Call(Id("FunctionInput").Id("input"), Id("FunctionOutput").Id("output")).BlockFunc(
+						func(overrideBlockGroup *Group) {
+							overrideBlockGroup.Id("input").Eq().Id("inp").And().Id("output").Eq().Id("out")
+						})
+				})
+		})
+		if addedCount > 0 {
+			rootModuleGroup.Add(tmp)
+			modelsClassNames = append(modelsClassNames, methodModelsClassName)
+		}
+	}
+
+	if len(modelsClassNames) > 0 {
+		barrierClassName := feparser.NewCodeQlName(className, "Barrier")
+
+		sanitizers := make([]Code, 0)
+		for _, family := range families {
+			sanitizers = append(sanitizers, Qual(family, "Sanitizer"))
+		}
+
+		tmp := DoGroup(func(tempBarrier *Group) {
+			tempBarrier.Doc("Models the outputs of the calls to the barrier functions and methods that pass the corresponding input.")
+			tempBarrier.Private().Class().Id(barrierClassName).Extends().List(sanitizers...).BlockFunc(
+				func(barrierClassGroup *Group) {
+					barrierClassGroup.Id(barrierClassName).Call().BlockFunc(
+						func(barrierSelfMethodGroup *Group) {
+							for i, modelsClassName := range modelsClassNames {
+								if i > 0 {
+									barrierSelfMethodGroup.Or()
+								}
+								barrierSelfMethodGroup.Exists(
+									List(
+										Id(modelsClassName).Id("fn"),
+										Id("DataFlow::CallNode").Id("call"),
+										Id("FunctionInput").Id("input"),
+										Id("FunctionOutput").Id("output"),
+										Id("DataFlow::Node").Id("inputNode"),
+									),
+									DoGroup(func(st *Group) {
+										st.Id("call").Eq().Id("fn").Dot("getACall").Call()
+										st.And()
+										st.Id("fn").Dot("hasBarrierFlow").Call(Id("input"), Id("output"))
+										st.And()
+										st.Comment("The output is a barrier only if the call passes the input:")
+										st.Id("inputNode").Eq().Id("input").Dot("getNode").Call(Id("call"))
+										st.And()
+										st.This().Eq().Id("output").Dot("getNode").Call(Id("call"))
+									}),
+									nil,
+								)
+							}
+						})
+				})
+		})
+		rootModuleGroup.Add(tmp)
+	}
+
+	return nil
+}
+
+func GetFuncQualifierCodeElements(qual *x.FuncQualifier) (x.FuncInterface, []Code) {
+
+	source := x.GetCachedSource(qual.Path, qual.Version)
+	if source == nil {
+		Fatalf("Source not found: %s@%s", qual.Path, qual.Version)
+	}
+	// Find the func/type-method/interface-method:
+	fn := x.FindFuncByID(source, qual.ID)
+	if fn == nil {
+		Fatalf("Func not found: %q", qual.ID)
+	}
+
+	codeElements := make([]Code, 0)
+
+	for _, block := range qual.Flows.Blocks {
+		inpCodeElements := make([]Code, 0)
+		{
+			receiver, parameterIndexes, resultIndexes := x.PosToRelativeIndexes(fn, block.Inp)
+			inpCodeElements = x.GenFunctionInputOutput("inp", fn, receiver, parameterIndexes, resultIndexes)
+		}
+
+		outCodeElements := make([]Code, 0)
+		{
+			receiver, parameterIndexes, resultIndexes := x.PosToRelativeIndexes(fn, block.Out)
+			outCodeElements = x.GenFunctionInputOutput("out", fn, receiver, parameterIndexes, resultIndexes)
+		}
+
+		codeElements = append(codeElements,
+			Parens(
+				Join(
+					Or(),
+					inpCodeElements...,
+				),
+			).
+				And().
+				Parens(
+					Join(
+						Or(),
+						outCodeElements...,
+					),
+				),
+		)
+	}
+
+	return fn, codeElements
+}
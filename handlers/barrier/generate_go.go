@@ -0,0 +1,578 @@
+package barrier
+
+import (
+	"go/types"
+	"os"
+	"path/filepath"
+
+	. "github.com/dave/jennifer/jen"
+	"github.com/gagliardetto/codebox/gogentools"
+	"github.com/gagliardetto/codemill/x"
+	"github.com/gagliardetto/feparser"
+	. "github.com/gagliardetto/utilz"
+)
+
+var (
+	GenerateBoilerplate bool
+)
+
+const (
+	// NOTE: hardcoded inside TestQueryContent const.
+	InlineExpectationsTestTag = "$taintSink" // Must start with a $ sign.
+)
+
+func Tag() Code {
+	return Comment(InlineExpectationsTestTag)
+}
+
+const (
+	// NOTE: the barriers are checked against all the supported QueryFamilies.
+	TestQueryContent = `
+import go
+import TestUtilities.InlineExpectationsTest
+import semmle.go.security.Xss
+import semmle.go.security.SqlInjectionCustomizations
+import semmle.go.security.LogInjectionCustomizations
+import semmle.go.security.CommandInjectionCustomizations
+import semmle.go.security.TaintedPathCustomizations
+
+class Configuration extends TaintTracking::Configuration {
+  Configuration() { this = "test-configuration" }
+
+  override predicate isSource(DataFlow::Node source) {
+    exists(Function fn | fn.hasQualifiedName(_, "source") | source = fn.getACall().getResult())
+  }
+
+  override predicate isSink(DataFlow::Node sink) {
+    exists(Function fn | fn.hasQualifiedName(_, "sink") | sink = fn.getACall().getAnArgument())
+  }
+
+  override predicate isAdditionalTaintStep(DataFlow::Node pred, DataFlow::Node succ) {
+    // Without the barrier, the taint would flow through any call:
+    exists(DataFlow::CallNode call | not call.getTarget().hasQualifiedName(_, ["source", "sink"]) |
+      pred = [call.getAnArgument(), call.getReceiver()] and
+      (
+        succ = call.getAResult() or
+        succ.(DataFlow::PostUpdateNode).getPreUpdateNode() = [call.getAnArgument(), call.getReceiver()]
+      )
+    )
+  }
+
+  override predicate isSanitizer(DataFlow::Node node) {
+    node instanceof SharedXss::Sanitizer or
+    node instanceof SqlInjection::Sanitizer or
+    node instanceof LogInjection::Sanitizer or
+    node instanceof CommandInjection::Sanitizer or
+    node instanceof TaintedPath::Sanitizer
+  }
+}
+
+class BarrierTest extends InlineExpectationsTest {
+  BarrierTest() { this = "BarrierTest" }
+
+  override string getARelevantTag() { result = "taintSink" }
+
+  override predicate hasActualResult(string file, int line, string element, string tag, string value) {
+    tag = "taintSink" and
+    exists(DataFlow::Node sink | any(Configuration c).hasFlow(_, sink) |
+      element = sink.toString() and
+      value = "" and
+      sink.hasLocationInfo(file, line, _, _, _)
+    )
+  }
+}
+`
+)
+
+func NewTestFile(includeBoilerplace bool) *File {
+	file := NewFile("main")
+	// Set a prefix to avoid collision between variable names and packages:
+	file.PackagePrefix = "cql"
+	// Add comment to file:
+	file.HeaderComment("Code generated by https://github.com/gagliardetto. DO NOT EDIT.")
+
+	if includeBoilerplace {
+		file.PackageComment("//go:generate depstubber --vendor --auto")
+		{
+			// main function:
+			file.Func().Id("main").Params().Block()
+		}
+		{
+			// sink function:
+			code := Func().
+				Id("sink").
+				Params(Id("v").Op("...").Interface()).
+				Block()
+			file.Add(code.Line())
+		}
+		{
+			// The `source` function returns a new tainted thing:
+			code := Func().
+				Id("source").
+				Params().
+				Interface().
+				Block(Return(Nil()))
+			file.Add(code.Line())
+		}
+	}
+	return file
+}
+
+var (
+	IncludeCommentsInGeneratedGo bool
+)
+
+func (han *Handler) GenerateGo(parentDir string, mdl *x.XModel) error {
+	if err := mdl.Validate(); err != nil {
+		return err
+	}
+	if err := han.Validate(mdl); err != nil {
+		return err
+	}
+
+	// Check if there are multiple versions of a same package:
+	mods := mdl.ListModules()
+	if x.HasMultiversion(mods) {
+		Ln(RedBG("Has multiversion"))
+	}
+	// If there are no multiple versions of the same module,
+	// that means we can save all the code to one file.
+	allInOneFile := !x.HasMultiversion(mods)
+
+	// Create the directory for the tests for this model:
+	outDir := filepath.Join(parentDir, feparser.NewCodeQlName(mdl.Name))
+	MustCreateFolderIfNotExists(outDir, os.ModePerm)
+
+	// Assuming the validation has already been done:
+	self := mdl.Methods[0]
+
+	if len(self.Selectors) == 0 {
+		Infof("No selectors found for %q method.", self.Name)
+		return nil
+	}
+
+	allPathVersions := mdl.ListAllPathVersions()
+
+	file := NewTestFile(GenerateBoilerplate)
+
+	b2fe, b2tm, b2itm, err := x.GroupFuncSelectors(self)
+	if err != nil {
+		Fatalf("Error while GroupFuncSelectors: %s", err)
+	}
+
+	for _, pathVersion := range allPathVersions {
+		if !allInOneFile {
+			// Reset file:
+			file = NewTestFile(GenerateBoilerplate)
+		}
+		codez := make([]Code, 0)
+
+		{
+			cont, ok := b2fe[pathVersion]
+			if ok {
+				addedCount := 0
+				code := BlockFunc(
+					func(groupCase *Group) {
+
+						for _, qual := range cont {
+							fn := x.GetFuncByQualifier(qual)
+							thing := fn.(*feparser.FEFunc)
+
+							x.AddImportsFromFunc(file, thing)
+
+							{
+								if !x.HasValidEnabledFlow(qual) {
+									continue
+								}
+								groupCase.Comment(thing.Signature)
+
+								blocksOfCases := generateGoTestBlock(
+									file,
+									thing,
+									qual,
+								)
+								if len(blocksOfCases) == 1 {
+									groupCase.Add(blocksOfCases...)
+								} else {
+									groupCase.Block(blocksOfCases...)
+								}
+								addedCount++
+							}
+
+						}
+					})
+				if addedCount > 0 {
+					codez = append(codez,
+						Comment("Barrier via function call.").
+							Line().
+							Add(code),
+					)
+				}
+			}
+		}
+		{
+			codezTypeMethods := make([]Code, 0)
+			b2tm.IterValid(pathVersion,
+				func(receiverTypeID string, methodQualifiers x.FuncQualifierSlice) {
+
+					firstQual := methodQualifiers[0]
+					// Find receiver type:
+					typ := x.FindType(firstQual.Path, firstQual.Version, receiverTypeID)
+					if typ == nil {
+						Fatalf("Type not found: %q", receiverTypeID)
+					}
+
+					gogentools.ImportPackage(file, typ.PkgPath, typ.PkgName)
+
+					code := BlockFunc(
+						func(groupCase *Group) {
+
+							for _, qual := range methodQualifiers {
+								fn := x.GetFuncByQualifier(qual)
+								thing := fn.(*feparser.FETypeMethod)
+								x.AddImportsFromFunc(file, fn)
+
+								{
+									if !x.HasValidEnabledFlow(qual) {
+										continue
+									}
+									groupCase.Comment(thing.Func.Signature)
+
+									blocksOfCases := generateGoTestBlock(
+										file,
+										thing,
+										qual,
+									)
+									if len(blocksOfCases) == 1 {
+										groupCase.Add(blocksOfCases...)
+									} else {
+										groupCase.Block(blocksOfCases...)
+									}
+								}
+
+							}
+						})
+					codezTypeMethods = append(codezTypeMethods,
+						Commentf("Barrier via method calls on %s.", typ.QualifiedName).
+							Line().
+							Add(code),
+					)
+				})
+			if len(codezTypeMethods) > 0 {
+				codez = append(codez,
+					Comment("Barrier via method calls.").
+						Line().
+						Block(codezTypeMethods...),
+				)
+			}
+		}
+
+		{
+			codezIfaceMethods := make([]Code, 0)
+			b2itm.IterValid(pathVersion,
+				func(receiverTypeID string, methodQualifiers x.FuncQualifierSlice) {
+					firstQual := methodQualifiers[0]
+					// Find receiver type:
+					typ := x.FindType(firstQual.Path, firstQual.Version, receiverTypeID)
+					if typ == nil {
+						Fatalf("Type not found: %q", receiverTypeID)
+					}
+
+					gogentools.ImportPackage(file, typ.PkgPath, typ.PkgName)
+
+					code := BlockFunc(
+						func(groupCase *Group) {
+
+							for _, qual := range methodQualifiers {
+								fn := x.GetFuncByQualifier(qual)
+								thing := fn.(*feparser.FEInterfaceMethod)
+								x.AddImportsFromFunc(file, fn)
+
+								{
+									if !x.HasValidEnabledFlow(qual) {
+										continue
+									}
+									groupCase.Comment(thing.Func.Signature)
+
+									converted := feparser.FEIToFET(thing)
+
+									blocksOfCases := generateGoTestBlock(
+										file,
+										converted,
+										qual,
+									)
+									if len(blocksOfCases) == 1 {
+										groupCase.Add(blocksOfCases...)
+									} else {
+										groupCase.Block(blocksOfCases...)
+									}
+								}
+							}
+						})
+					codezIfaceMethods = append(codezIfaceMethods,
+						Commentf("Barrier via method calls on %s interface.", typ.QualifiedName).
+							Line().
+							Add(code),
+					)
+				})
+
+			if len(codezIfaceMethods) > 0 {
+				codez = append(codez,
+					Comment("Barrier via interface method calls.").
+						Line().
+						Block(codezIfaceMethods...),
+				)
+			}
+		}
+
+		{
+			file.Commentf("Package %s", pathVersion)
+			file.Func().Id(mdl.Name + "_" + feparser.FormatCodeQlName(pathVersion)).Params().Block(codez...)
+		}
+
+		if !allInOneFile {
+			pkgDstDirpath := filepath.Join(outDir, feparser.FormatID(mdl.Name, "For", feparser.FormatCodeQlName(pathVersion)))
+			MustCreateFolderIfNotExists(pkgDstDirpath, os.ModePerm)
+
+			assetFileName := feparser.FormatID(mdl.Name, "For", feparser.FormatCodeQlName(pathVersion)) + ".go"
+			if err := x.SaveGoFile(pkgDstDirpath, assetFileName, file); err != nil {
+				Fatalf("Error while saving go file: %s", err)
+			}
+
+			if err := x.WriteGoModFile(pkgDstDirpath, pathVersion); err != nil {
+				Fatalf("Error while saving go.mod file: %s", err)
+			}
+			if err := x.WriteCodeQLTestQuery(pkgDstDirpath, mdl.Name, TestQueryContent); err != nil {
+				Fatalf("Error while saving <name>.ql file: %s", err)
+			}
+			if err := x.WriteEmptyCodeQLDotExpectedFile(pkgDstDirpath, mdl.Name); err != nil {
+				Fatalf("Error while saving <name>.expected file: %s", err)
+			}
+		}
+	}
+
+	if allInOneFile {
+		pkgDstDirpath := outDir
+		MustCreateFolderIfNotExists(pkgDstDirpath, os.ModePerm)
+
+		assetFileName := feparser.FormatID(mdl.Name) + ".go"
+		if err := x.SaveGoFile(pkgDstDirpath, assetFileName, file); err != nil {
+			Fatalf("Error while saving go file: %s", err)
+		}
+
+		if err := x.WriteGoModFile(pkgDstDirpath, allPathVersions...); err != nil {
+			Fatalf("Error while saving go.mod file: %s", err)
+		}
+		if err := x.WriteCodeQLTestQuery(pkgDstDirpath, mdl.Name, TestQueryContent); err != nil {
+			Fatalf("Error while saving <name>.ql file: %s", err)
+		}
+		if err := x.WriteEmptyCodeQLDotExpectedFile(pkgDstDirpath, mdl.Name); err != nil {
+			Fatalf("Error while saving <name>.expected file: %s", err)
+		}
+	}
+	return nil
+}
+
+// Comments adds comments to a Group (if enabled), and returns the group.
+func Comments(group *Group, comments ...string) *Group {
+	if IncludeCommentsInGeneratedGo {
+		for _, comment := range comments {
+			group.Line().Comment(comment)
+		}
+	}
+	return group
+}
+
+func newStatement() *Statement {
+	return &Statement{}
+}
+
+// for each block, generate a golang test block for each inp and out combination.
+func generateGoTestBlock(
+	file *File,
+	fn x.FuncInterface,
+	qual *x.FuncQualifier,
+) []Code {
+	childBlocks := make([]Code, 0)
+	for blockIndex, block := range qual.Flows.Blocks {
+		for inpIndex, inpOk := range block.Inp {
+			if !inpOk {
+				continue
+			}
+			for outIndex, outOk := range block.Out {
+				if !outOk {
+					continue
+				}
+				childBlock := generate(
+					file,
+					fn,
+					inpIndex,
+					outIndex,
+				)
+				{
+					if childBlock != nil {
+						childBlocks = append(childBlocks, childBlock)
+					} else {
+						Warnf(Sf("NOTHING GENERATED; block %v, inp %v, out %v", blockIndex, inpIndex, outIndex))
+					}
+				}
+			}
+		}
+	}
+
+	return childBlocks
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+func generate(file *File, fn x.FuncInterface, inpIndex int, outIndex int) *Statement {
+
+	inpElem, _, inpRelIndex, err := fn.GetRelativeElement(inpIndex)
+	if err != nil {
+		panic(err)
+	}
+	outElem, _, outRelIndex, err := fn.GetRelativeElement(outIndex)
+	if err != nil {
+		panic(err)
+	}
+
+	Receiver := feparser.ElementReceiver
+	Parameter := feparser.ElementParameter
+	Result := feparser.ElementResult
+
+	if inpElem == Result {
+		Warnf("Result as input is not supported: %s", fn.GetFunc().Signature)
+		return nil
+	}
+	if inpIndex == outIndex {
+		return nil
+	}
+
+	fe := fn.GetFunc()
+	hasReceiver := fn.GetReceiver() != nil
+
+	if hasReceiver {
+		rece := fn.GetReceiver()
+		switch {
+		case inpElem == Receiver:
+			rece.VarName = gogentools.NewNameWithPrefix(feparser.NewLowerTitleName("from", rece.TypeName))
+		case outElem == Receiver:
+			rece.VarName = gogentools.NewNameWithPrefix(feparser.NewLowerTitleName("into", rece.TypeName))
+		default:
+			rece.VarName = "rece"
+		}
+	}
+	if inpElem == Parameter {
+		in := fe.Parameters[inpRelIndex]
+		in.VarName = gogentools.NewNameWithPrefix(feparser.NewLowerTitleName("from", in.TypeName))
+	}
+	var inpVarName string
+	switch inpElem {
+	case Receiver:
+		inpVarName = fn.GetReceiver().VarName
+	case Parameter:
+		inpVarName = fe.Parameters[inpRelIndex].VarName
+	}
+	var outVarName string
+	switch outElem {
+	case Receiver:
+		outVarName = fn.GetReceiver().VarName
+	case Parameter:
+		out := fe.Parameters[outRelIndex]
+		out.VarName = gogentools.NewNameWithPrefix(feparser.NewLowerTitleName("into", out.TypeName))
+		outVarName = out.VarName
+	case Result:
+		out := fe.Results[outRelIndex]
+		out.VarName = gogentools.NewNameWithPrefix(feparser.NewLowerTitleName("into", out.TypeName))
+		outVarName = out.VarName
+	}
+
+	code := BlockFunc(
+		func(groupCase *Group) {
+
+			if hasReceiver {
+				rece := fn.GetReceiver()
+				if inpElem == Receiver {
+					Comments(groupCase, Sf("Assume that `sourceCQL` has the underlying type of `%s`:", rece.VarName))
+					ComposeTypeAssertion(file, groupCase, rece.VarName, rece.GetOriginal(), rece.Is.Variadic)
+				} else {
+					Comments(groupCase, "Declare medium object/interface:")
+					gogentools.ComposeVarDeclaration(file, groupCase, rece.VarName, rece.GetOriginal(), rece.Is.Variadic)
+				}
+			}
+			if inpElem == Parameter {
+				in := fe.Parameters[inpRelIndex]
+				Comments(groupCase, Sf("Assume that `sourceCQL` has the underlying type of `%s`:", in.VarName))
+				ComposeTypeAssertion(file, groupCase, in.VarName, in.GetOriginal().GetType(), in.GetOriginal().IsVariadic())
+			}
+			if outElem == Parameter {
+				out := fe.Parameters[outRelIndex]
+				Comments(groupCase, Sf("Declare `%s` variable:", out.VarName))
+				gogentools.ComposeVarDeclaration(file, groupCase, out.VarName, out.GetOriginal().GetType(), out.GetOriginal().IsVariadic())
+			}
+
+			gogentools.ImportPackage(file, fe.PkgPath, fe.PkgName)
+
+			var callee *Statement
+			if hasReceiver {
+				callee = Id(fn.GetReceiver().VarName).Dot(fe.Name)
+			} else {
+				callee = Qual(fe.PkgPath, fe.Name)
+			}
+			callee.CallFunc(
+				func(call *Group) {
+
+					tpFun := fe.GetOriginal().GetType().(*types.Signature)
+
+					zeroVals := gogentools.ScanTupleOfZeroValues(file, tpFun.Params(), fe.GetOriginal().IsVariadic())
+
+					for i, zero := range zeroVals {
+						isConsidered := (inpElem == Parameter && i == inpRelIndex) || (outElem == Parameter && i == outRelIndex)
+						if isConsidered {
+							call.Id(fe.Parameters[i].VarName)
+						} else {
+							call.Add(zero)
+						}
+					}
+
+				},
+			)
+
+			if outElem == Result {
+				groupCase.ListFunc(func(resGroup *Group) {
+					for i, v := range fe.Results {
+						if i == outRelIndex {
+							resGroup.Id(v.VarName)
+						} else {
+							resGroup.Id("_")
+						}
+					}
+				}).Op(":=").Add(callee)
+			} else {
+				groupCase.Add(callee)
+			}
+
+			Comments(groupCase, Sf("The input `%s` is still tainted:", inpVarName))
+			groupCase.Id("sink").Call(Id(inpVarName)).Add(Tag())
+
+			Comments(groupCase, Sf("The output `%s` is NOT tainted:", outVarName))
+			groupCase.Id("sink").Call(Id(outVarName))
+		})
+	return code
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// declare `name := source(1).(Type)`
+func ComposeTypeAssertion(file *File, group *Group, varName string, typ types.Type, isVariadic bool) {
+	assertContent := newStatement()
+	if isVariadic {
+		if slice, ok := typ.(*types.Slice); ok {
+			gogentools.ComposeTypeDeclaration(file, assertContent, slice.Elem())
+		} else {
+			gogentools.ComposeTypeDeclaration(file, assertContent, typ)
+		}
+	} else {
+		gogentools.ComposeTypeDeclaration(file, assertContent, typ)
+	}
+	group.Id(varName).Op(":=").Id("source").Call().Assert(assertContent)
+}
@@ -0,0 +1,94 @@
+package barrier
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/gagliardetto/codemill/x"
+)
+
+// NOTE:
+// - Like the tainttracking.Handler, this handler uses the func flow blocks;
+//   the output of each block is a barrier (i.e. the taint stops there)
+//   for the calls that pass the input of the block.
+
+const (
+	Kind x.ModelKind = "Barrier"
+)
+
+type Handler struct{}
+
+const (
+	MethodSelf = "Self"
+)
+
+const (
+	OptionQueryFamilies = "QueryFamilies" // Comma-separated list of query families, e.g. `SharedXss,SqlInjection`.
+)
+
+// QueryFamilies maps each supported query family
+// to the library that defines its `Sanitizer` class.
+var QueryFamilies = map[string]string{
+	"SharedXss":        "semmle.go.security.Xss",
+	"SqlInjection":     "semmle.go.security.SqlInjectionCustomizations",
+	"LogInjection":     "semmle.go.security.LogInjectionCustomizations",
+	"CommandInjection": "semmle.go.security.CommandInjectionCustomizations",
+	"TaintedPath":      "semmle.go.security.TaintedPathCustomizations",
+}
+
+//
+func (han *Handler) ScavengeMethods() []*x.XMethod {
+	return []*x.XMethod{
+		{
+			Name:      MethodSelf,
+			Selectors: []*x.XSelector{},
+		},
+	}
+}
+
+//
+func (han *Handler) ScavengeOptions() map[string]string {
+	return map[string]string{
+		OptionQueryFamilies: "",
+	}
+}
+func (han *Handler) Validate(mdl *x.XModel) error {
+	if len(mdl.Methods) != 1 {
+		return fmt.Errorf("wrong number of methods; expected 1, got %v", len(mdl.Methods))
+	}
+	if mdl.Methods[0].Name != MethodSelf {
+		return fmt.Errorf("First method is not called %s", MethodSelf)
+	}
+	families := GetQueryFamilies(mdl)
+	if len(families) == 0 {
+		return fmt.Errorf("option %s is not set", OptionQueryFamilies)
+	}
+	for _, family := range families {
+		if _, ok := QueryFamilies[family]; !ok {
+			return fmt.Errorf("unknown query family %q; supported: %s", family, strings.Join(listQueryFamilies(), ", "))
+		}
+	}
+	return nil
+}
+
+// GetQueryFamilies returns the query families set in the model option.
+func GetQueryFamilies(mdl *x.XModel) []string {
+	families := make([]string, 0)
+	for _, family := range strings.Split(mdl.GetOption(OptionQueryFamilies), ",") {
+		family = strings.TrimSpace(family)
+		if family != "" {
+			families = append(families, family)
+		}
+	}
+	return families
+}
+
+func listQueryFamilies() []string {
+	list := make([]string, 0)
+	for family := range QueryFamilies {
+		list = append(list, family)
+	}
+	sort.Strings(list)
+	return list
+}
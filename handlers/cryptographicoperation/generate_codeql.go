@@ -0,0 +1,109 @@
+package cryptographicoperation
+
+import (
+	"github.com/gagliardetto/codemill/x"
+	. "github.com/gagliardetto/cqlgen/jen"
+	"github.com/gagliardetto/feparser"
+	. "github.com/gagliardetto/utilz"
+)
+
+func (han *Handler) GenerateCodeQL(impAdder x.ImportAdder, mdl *x.XModel, rootModuleGroup *Group) error {
+	if err := mdl.Validate(); err != nil {
+		return err
+	}
+	if err := han.Validate(mdl); err != nil {
+		return err
+	}
+
+	// Assuming the validation has already been done:
+	mtdInputAlgoFromFuncName := mdl.Methods.ByName(MethodInputAlgoFromFuncName)
+	mtdInputAlgoFromSelector := mdl.Methods.ByName(MethodInputAlgoFromSelector)
+
+	{
+		// Add imports:
+		//impAdder.Import("DataFlow::PathGraph")
+	}
+
+	className := mdl.Name
+	allPathVersions := mdl.ListAllPathVersions()
+
+	cases := make([]Code, 0)
+	if len(mtdInputAlgoFromFuncName.Selectors) == 0 {
+		Infof("No selectors found for %q method.", mtdInputAlgoFromFuncName.Name)
+	} else {
+		code, count := x.CqlCallTargets(allPathVersions, mtdInputAlgoFromFuncName, "this", "Cryptographic operation models",
+			bindInputAndAlgorithm(func(fn x.FuncInterface, qual *x.FuncQualifier) string {
+				// NOTE: validated in Validate.
+				return x.GuessCryptoAlgorithmFromName(fn.GetFunc().Name)
+			}),
+		)
+		if count > 0 {
+			cases = append(cases, Parens(code))
+		}
+	}
+	if len(mtdInputAlgoFromSelector.Selectors) == 0 {
+		Infof("No selectors found for %q method.", mtdInputAlgoFromSelector.Name)
+	} else {
+		code, count := x.CqlCallTargets(allPathVersions, mtdInputAlgoFromSelector, "this", "Cryptographic operation models",
+			bindInputAndAlgorithm(func(fn x.FuncInterface, qual *x.FuncQualifier) string {
+				return qual.Algorithm
+			}),
+		)
+		if count > 0 {
+			cases = append(cases, Parens(code))
+		}
+	}
+	if len(cases) == 0 {
+		return nil
+	}
+
+	{
+		funcModelsClassName := feparser.NewCodeQlName(className)
+
+		tmp := DoGroup(func(tempFuncsModel *Group) {
+			tempFuncsModel.Doc("Models cryptographic operations.")
+			tempFuncsModel.Private().Class().Id(funcModelsClassName).Extends().List(
+				Id("CryptographicOperation::Range"),
+				Id("DataFlow::CallNode"),
+			).BlockFunc(
+				func(funcModelsClassGroup *Group) {
+					funcModelsClassGroup.String().Id("package").Semicolon().Line()
+					funcModelsClassGroup.String().Id("algorithmName").Semicolon().Line()
+					funcModelsClassGroup.Id("DataFlow::Node").Id("inputNode").Semicolon().Line()
+
+					funcModelsClassGroup.Id(funcModelsClassName).Call().Block(
+						Join(
+							Or(),
+							cases...,
+						),
+					)
+
+					funcModelsClassGroup.Override().Id("CryptographicAlgorithm").Id("getAlgorithm").Call().BlockFunc(
+						func(overrideBlockGroup *Group) {
+							overrideBlockGroup.Id("result").Dot("matchesName").Call(Id("algorithmName"))
+						})
+
+					funcModelsClassGroup.Override().Id("DataFlow::Node").Id("getInput").Call().BlockFunc(
+						func(overrideBlockGroup *Group) {
+							overrideBlockGroup.Id("result").Eq().Id("inputNode")
+						})
+				})
+		})
+		rootModuleGroup.Add(tmp)
+	}
+
+	return nil
+}
+
+// bindInputAndAlgorithm returns a gen func (see x.CqlCallTargets) that binds
+// the inputNode to the selected argument, and the algorithmName to the algorithm of the func.
+func bindInputAndAlgorithm(algorithmFor func(fn x.FuncInterface, qual *x.FuncQualifier) string) func(fn x.FuncInterface, qual *x.FuncQualifier) Code {
+	bindInput := x.CqlBindArgument("this", "inputNode")
+	return func(fn x.FuncInterface, qual *x.FuncQualifier) Code {
+		return DoGroup(func(gr *Group) {
+			gr.Add(bindInput(fn, qual))
+			gr.And()
+			gr.Id("algorithmName").Eq().Lit(algorithmFor(fn, qual))
+		})
+	}
+}
@@ -0,0 +1,503 @@
+package cryptographicoperation
+
+import (
+	"go/types"
+	"os"
+	"path/filepath"
+
+	. "github.com/dave/jennifer/jen"
+	"github.com/gagliardetto/codebox/gogentools"
+	"github.com/gagliardetto/codemill/x"
+	"github.com/gagliardetto/feparser"
+	. "github.com/gagliardetto/utilz"
+)
+
+var (
+	GenerateBoilerplate bool
+)
+
+const (
+	// NOTE: hardcoded inside TestQueryContent const.
+	InlineExpectationsTestTagAlgorithm = "$cryptoAlgorithm" // Must start with a $ sign.
+	InlineExpectationsTestTagInput     = "$cryptoInput"     // Must start with a $ sign.
+)
+
+// Tag composes a comment with the algorithm tag, and the provided tags,
+// each one followed by the name of the tagged variable.
+func Tag(algorithm string, params ...taggedParam) Code {
+	tg := InlineExpectationsTestTagAlgorithm + "=" + algorithm
+	for _, param := range params {
+		tg += " " + param.Tag + "=" + param.VarName
+	}
+	return Comment(tg)
+}
+
+const (
+	TestQueryContent = `
+import go
+import TestUtilities.InlineExpectationsTest
+
+class CryptographicOperationTest extends InlineExpectationsTest {
+  CryptographicOperationTest() { this = "CryptographicOperationTest" }
+
+  override string getARelevantTag() { result = ["cryptoAlgorithm", "cryptoInput"] }
+
+  override predicate hasActualResult(string file, int line, string element, string tag, string value) {
+    exists(CryptographicOperation op | op.hasLocationInfo(file, line, _, _, _) |
+      tag = "cryptoAlgorithm" and
+      element = op.toString() and
+      value = op.getAlgorithm().getName()
+      or
+      tag = "cryptoInput" and
+      element = op.getInput().toString() and
+      value = op.getInput().toString()
+    )
+  }
+}
+`
+)
+
+func NewTestFile(includeBoilerplace bool) *File {
+	file := NewFile("main")
+	// Set a prefix to avoid collision between variable names and packages:
+	file.PackagePrefix = "cql"
+	// Add comment to file:
+	file.HeaderComment("Code generated by https://github.com/gagliardetto. DO NOT EDIT.")
+
+	if includeBoilerplace {
+		file.PackageComment("//go:generate depstubber --vendor --auto")
+		{
+			// main function:
+			file.Func().Id("main").Params().Block()
+		}
+		{
+			// The `source` function returns new input data:
+			code := Func().
+				Id("source").
+				Params().
+				Interface().
+				Block(Return(Nil()))
+			file.Add(code.Line())
+		}
+	}
+	return file
+}
+
+var (
+	IncludeCommentsInGeneratedGo bool
+)
+
+func (han *Handler) GenerateGo(parentDir string, mdl *x.XModel) error {
+	if err := mdl.Validate(); err != nil {
+		return err
+	}
+	if err := han.Validate(mdl); err != nil {
+		return err
+	}
+
+	// Check if there are multiple versions of a same package:
+	mods := mdl.ListModules()
+	if x.HasMultiversion(mods) {
+		Ln(RedBG("Has multiversion"))
+	}
+	// If there are no multiple versions of the same module,
+	// that means we can save all the code to one file.
+	allInOneFile := !x.HasMultiversion(mods)
+
+	// Create the directory for the tests for this model:
+	outDir := filepath.Join(parentDir, feparser.NewCodeQlName(mdl.Name))
+	MustCreateFolderIfNotExists(outDir, os.ModePerm)
+
+	// Assuming the validation has already been done:
+	mtdInputAlgoFromFuncName := mdl.Methods.ByName(MethodInputAlgoFromFuncName)
+	mtdInputAlgoFromSelector := mdl.Methods.ByName(MethodInputAlgoFromSelector)
+
+	if len(mtdInputAlgoFromFuncName.Selectors) == 0 && len(mtdInputAlgoFromSelector.Selectors) == 0 {
+		Infof("No selectors found for %q and %q methods.", mtdInputAlgoFromFuncName.Name, mtdInputAlgoFromSelector.Name)
+		return nil
+	}
+
+	allPathVersions := mdl.ListAllPathVersions()
+
+	file := NewTestFile(GenerateBoilerplate)
+
+	b2feFromFuncName, b2tmFromFuncName, b2itmFromFuncName, err := x.GroupFuncSelectors(mtdInputAlgoFromFuncName)
+	if err != nil {
+		Fatalf("Error while GroupFuncSelectors: %s", err)
+	}
+	b2feFromSelector, b2tmFromSelector, b2itmFromSelector, err := x.GroupFuncSelectors(mtdInputAlgoFromSelector)
+	if err != nil {
+		Fatalf("Error while GroupFuncSelectors: %s", err)
+	}
+
+	for _, pathVersion := range allPathVersions {
+		if !allInOneFile {
+			// Reset file:
+			file = NewTestFile(GenerateBoilerplate)
+		}
+		codez := make([]Code, 0)
+
+		// Algorithm inferred from the func name:
+		codez = append(codez,
+			generateGoTestCodez(file, pathVersion, "Cryptographic operation (algorithm inferred from name)", b2feFromFuncName, b2tmFromFuncName, b2itmFromFuncName,
+				func(file *File, fn x.FuncInterface, qual *x.FuncQualifier, receiverTypeID string) []Code {
+					return generateGoTestBlock(
+						file,
+						fn,
+						x.GuessCryptoAlgorithmFromName(fn.GetFunc().Name),
+						taggedParam{Index: x.MustPosToRelativeParamIndexes(fn, qual.Pos)[0], Prefix: "input", Tag: InlineExpectationsTestTagInput},
+					)
+				},
+			)...,
+		)
+		// Algorithm set on the selector:
+		codez = append(codez,
+			generateGoTestCodez(file, pathVersion, "Cryptographic operation (algorithm set on selector)", b2feFromSelector, b2tmFromSelector, b2itmFromSelector,
+				func(file *File, fn x.FuncInterface, qual *x.FuncQualifier, receiverTypeID string) []Code {
+					return generateGoTestBlock(
+						file,
+						fn,
+						qual.Algorithm,
+						taggedParam{Index: x.MustPosToRelativeParamIndexes(fn, qual.Pos)[0], Prefix: "input", Tag: InlineExpectationsTestTagInput},
+					)
+				},
+			)...,
+		)
+
+		{
+			file.Commentf("Package %s", pathVersion)
+			file.Func().Id(mdl.Name + "_" + feparser.FormatCodeQlName(pathVersion)).Params().Block(codez...)
+		}
+
+		if !allInOneFile {
+			pkgDstDirpath := filepath.Join(outDir, feparser.FormatID(mdl.Name, "For", feparser.FormatCodeQlName(pathVersion)))
+			MustCreateFolderIfNotExists(pkgDstDirpath, os.ModePerm)
+
+			assetFileName := feparser.FormatID(mdl.Name, "For", feparser.FormatCodeQlName(pathVersion)) + ".go"
+			if err := x.SaveGoFile(pkgDstDirpath, assetFileName, file); err != nil {
+				Fatalf("Error while saving go file: %s", err)
+			}
+
+			if err := x.WriteGoModFile(pkgDstDirpath, pathVersion); err != nil {
+				Fatalf("Error while saving go.mod file: %s", err)
+			}
+			if err := x.WriteCodeQLTestQuery(pkgDstDirpath, mdl.Name, TestQueryContent); err != nil {
+				Fatalf("Error while saving <name>.ql file: %s", err)
+			}
+			if err := x.WriteEmptyCodeQLDotExpectedFile(pkgDstDirpath, mdl.Name); err != nil {
+				Fatalf("Error while saving <name>.expected file: %s", err)
+			}
+		}
+	}
+
+	if allInOneFile {
+		pkgDstDirpath := outDir
+		MustCreateFolderIfNotExists(pkgDstDirpath, os.ModePerm)
+
+		assetFileName := feparser.FormatID(mdl.Name) + ".go"
+		if err := x.SaveGoFile(pkgDstDirpath, assetFileName, file); err != nil {
+			Fatalf("Error while saving go file: %s", err)
+		}
+
+		if err := x.WriteGoModFile(pkgDstDirpath, allPathVersions...); err != nil {
+			Fatalf("Error while saving go.mod file: %s", err)
+		}
+		if err := x.WriteCodeQLTestQuery(pkgDstDirpath, mdl.Name, TestQueryContent); err != nil {
+			Fatalf("Error while saving <name>.ql file: %s", err)
+		}
+		if err := x.WriteEmptyCodeQLDotExpectedFile(pkgDstDirpath, mdl.Name); err != nil {
+			Fatalf("Error while saving <name>.expected file: %s", err)
+		}
+	}
+	return nil
+}
+
+// Comments adds comments to a Group (if enabled), and returns the group.
+func Comments(group *Group, comments ...string) *Group {
+	if IncludeCommentsInGeneratedGo {
+		for _, comment := range comments {
+			group.Line().Comment(comment)
+		}
+	}
+	return group
+}
+
+func newStatement() *Statement {
+	return &Statement{}
+}
+
+// generateGoTestCodez generates the test code blocks for all the funcs and methods
+// selected for the provided pathVersion, using gen to generate the single test blocks.
+func generateGoTestCodez(
+	file *File,
+	pathVersion string,
+	what string,
+	b2fe x.BasicToFEFuncs,
+	b2tm x.BasicToTypeIDToMethods,
+	b2itm x.BasicToInterfaceIDToMethods,
+	gen func(file *File, fn x.FuncInterface, qual *x.FuncQualifier, receiverTypeID string) []Code,
+) []Code {
+	codez := make([]Code, 0)
+
+	{
+		cont, ok := b2fe[pathVersion]
+		if ok && x.HasValidPos(cont...) {
+			addedCount := 0
+			code := BlockFunc(
+				func(groupCase *Group) {
+
+					for _, qual := range cont {
+						fn := x.GetFuncByQualifier(qual)
+						thing := fn.(*feparser.FEFunc)
+
+						x.AddImportsFromFunc(file, thing)
+
+						{
+							if AllFalse(qual.Pos...) {
+								continue
+							}
+							groupCase.Comment(thing.Signature)
+
+							blocksOfCases := gen(file, thing, qual, "")
+							if len(blocksOfCases) == 1 {
+								groupCase.Add(blocksOfCases...)
+							} else {
+								groupCase.Block(blocksOfCases...)
+							}
+							addedCount++
+						}
+
+					}
+				})
+			if addedCount > 0 {
+				codez = append(codez,
+					Commentf("%s via function call.", what).
+						Line().
+						Add(code),
+				)
+			}
+		}
+	}
+	{
+		codezTypeMethods := make([]Code, 0)
+		b2tm.IterValid(pathVersion,
+			func(receiverTypeID string, methodQualifiers x.FuncQualifierSlice) {
+
+				firstQual := methodQualifiers[0]
+				// Find receiver type:
+				typ := x.FindType(firstQual.Path, firstQual.Version, receiverTypeID)
+				if typ == nil {
+					Fatalf("Type not found: %q", receiverTypeID)
+				}
+
+				gogentools.ImportPackage(file, typ.PkgPath, typ.PkgName)
+
+				code := BlockFunc(
+					func(groupCase *Group) {
+
+						for _, qual := range methodQualifiers {
+							fn := x.GetFuncByQualifier(qual)
+							thing := fn.(*feparser.FETypeMethod)
+							x.AddImportsFromFunc(file, fn)
+
+							{
+								if AllFalse(qual.Pos...) {
+									continue
+								}
+								groupCase.Comment(thing.Func.Signature)
+
+								blocksOfCases := gen(file, thing, qual, receiverTypeID)
+								if len(blocksOfCases) == 1 {
+									groupCase.Add(blocksOfCases...)
+								} else {
+									groupCase.Block(blocksOfCases...)
+								}
+							}
+
+						}
+					})
+				codezTypeMethods = append(codezTypeMethods,
+					Commentf("%s via method calls on %s.", what, typ.QualifiedName).
+						Line().
+						Add(code),
+				)
+			})
+		if len(codezTypeMethods) > 0 {
+			codez = append(codez,
+				Commentf("%s via method calls.", what).
+					Line().
+					Block(codezTypeMethods...),
+			)
+		}
+	}
+
+	{
+		codezIfaceMethods := make([]Code, 0)
+		b2itm.IterValid(pathVersion,
+			func(receiverTypeID string, methodQualifiers x.FuncQualifierSlice) {
+				firstQual := methodQualifiers[0]
+				// Find receiver type:
+				typ := x.FindType(firstQual.Path, firstQual.Version, receiverTypeID)
+				if typ == nil {
+					Fatalf("Type not found: %q", receiverTypeID)
+				}
+
+				gogentools.ImportPackage(file, typ.PkgPath, typ.PkgName)
+
+				code := BlockFunc(
+					func(groupCase *Group) {
+
+						for _, qual := range methodQualifiers {
+							fn := x.GetFuncByQualifier(qual)
+							thing := fn.(*feparser.FEInterfaceMethod)
+							x.AddImportsFromFunc(file, fn)
+
+							{
+								if AllFalse(qual.Pos...) {
+									continue
+								}
+								groupCase.Comment(thing.Func.Signature)
+
+								converted := feparser.FEIToFET(thing)
+
+								blocksOfCases := gen(file, converted, qual, receiverTypeID)
+								if len(blocksOfCases) == 1 {
+									groupCase.Add(blocksOfCases...)
+								} else {
+									groupCase.Block(blocksOfCases...)
+								}
+							}
+						}
+					})
+				codezIfaceMethods = append(codezIfaceMethods,
+					Commentf("%s via method calls on %s interface.", what, typ.QualifiedName).
+						Line().
+						Add(code),
+				)
+			})
+
+		if len(codezIfaceMethods) > 0 {
+			codez = append(codez,
+				Commentf("%s via interface method calls.", what).
+					Line().
+					Block(codezIfaceMethods...),
+			)
+		}
+	}
+
+	return codez
+}
+
+// taggedParam is a parameter that is passed from `source()` and tagged in the test.
+type taggedParam struct {
+	Index   int
+	Prefix  string
+	Tag     string
+	VarName string
+}
+
+func generateGoTestBlock(
+	file *File,
+	fn x.FuncInterface,
+	algorithm string,
+	params ...taggedParam,
+) []Code {
+	childBlocks := make([]Code, 0)
+
+	childBlock := generate(
+		file,
+		fn,
+		algorithm,
+		params,
+	)
+	{
+		if childBlock != nil {
+			childBlocks = append(childBlocks, childBlock)
+		} else {
+			Warnf(Sf("NOTHING GENERATED; %s", fn.GetFunc().Signature))
+		}
+	}
+
+	return childBlocks
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+func generate(file *File, fn x.FuncInterface, algorithm string, params []taggedParam) *Statement {
+
+	for i := range params {
+		in := fn.GetFunc().Parameters[params[i].Index]
+		in.VarName = gogentools.NewNameWithPrefix(feparser.NewLowerTitleName(params[i].Prefix, in.TypeName))
+		params[i].VarName = in.VarName
+	}
+	isConsidered := func(index int) bool {
+		for _, param := range params {
+			if param.Index == index {
+				return true
+			}
+		}
+		return false
+	}
+
+	hasReceiver := fn.GetReceiver() != nil
+
+	code := BlockFunc(
+		func(groupCase *Group) {
+
+			for _, param := range params {
+				in := fn.GetFunc().Parameters[param.Index]
+
+				ComposeTypeAssertion(file, groupCase, in.VarName, in.GetOriginal().GetType(), in.GetOriginal().IsVariadic())
+			}
+
+			if hasReceiver {
+				Comments(groupCase, "Declare medium object/interface:")
+				groupCase.Var().Id("rece").Qual(fn.GetReceiver().PkgPath, fn.GetReceiver().TypeName)
+			}
+
+			gogentools.ImportPackage(file, fn.GetFunc().PkgPath, fn.GetFunc().PkgName)
+
+			var after *Statement
+			if hasReceiver {
+				after = groupCase.Id("rece").Dot(fn.GetFunc().Name)
+			} else {
+				after = groupCase.Qual(fn.GetFunc().PkgPath, fn.GetFunc().Name)
+			}
+
+			after.CallFunc(
+				func(call *Group) {
+
+					tpFun := fn.GetFunc().GetOriginal().GetType().(*types.Signature)
+
+					zeroVals := gogentools.ScanTupleOfZeroValues(file, tpFun.Params(), fn.GetFunc().GetOriginal().IsVariadic())
+
+					for i, zero := range zeroVals {
+						if isConsidered(i) {
+							call.Id(fn.GetFunc().Parameters[i].VarName)
+						} else {
+							call.Add(zero)
+						}
+					}
+
+				},
+			).Add(Tag(algorithm, params...))
+
+		})
+	return code
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// declare `name := source(1).(Type)`
+func ComposeTypeAssertion(file *File, group *Group, varName string, typ types.Type, isVariadic bool) {
+	assertContent := newStatement()
+	if isVariadic {
+		if slice, ok := typ.(*types.Slice); ok {
+			gogentools.ComposeTypeDeclaration(file, assertContent, slice.Elem())
+		} else {
+			gogentools.ComposeTypeDeclaration(file, assertContent, typ)
+		}
+	} else {
+		gogentools.ComposeTypeDeclaration(file, assertContent, typ)
+	}
+	group.Id(varName).Op(":=").Id("source").Call().Assert(assertContent)
+}
@@ -0,0 +1,72 @@
+package cryptographicoperation
+
+import (
+	"fmt"
+
+	"github.com/gagliardetto/codemill/x"
+)
+
+// NOTE:
+// - The input must be exactly one parameter.
+// - Funcs whose algorithm cannot be inferred from the name
+//   must be added to MethodInputAlgoFromSelector, with the algorithm set on the selector.
+
+const (
+	Kind x.ModelKind = "CryptographicOperation"
+)
+
+type Handler struct{}
+
+const (
+	MethodInputAlgoFromFuncName = "{algo:Inferred, inp:Param} <- $inp" // The input data; the algorithm will be inferred from the function name.
+	MethodInputAlgoFromSelector = "{algo:Selector, inp:Param} <- $inp" // The input data; the algorithm is the one set on the selector.
+)
+
+//
+func (han *Handler) ScavengeMethods() []*x.XMethod {
+	return x.ScavengeMethods(
+		MethodInputAlgoFromFuncName, // "Select the input parameter; the algorithm will be inferred from the function name.",
+		MethodInputAlgoFromSelector, // "Select the input parameter, and set the algorithm on the selector (e.g. `MD5`, `SHA1`, `AES`).",
+	)
+}
+func (han *Handler) Validate(mdl *x.XModel) error {
+	defaultMthNum := len(han.ScavengeMethods())
+	if len(mdl.Methods) != defaultMthNum {
+		return fmt.Errorf("wrong number of methods; expected %v, got %v", defaultMthNum, len(mdl.Methods))
+	}
+	{
+		for i, must := range han.ScavengeMethods() {
+			if mdl.Methods[i].Name != must.Name {
+				return fmt.Errorf("#%v method is not called %s", i, must.Name)
+			}
+		}
+	}
+	{
+		// The input must be exactly one parameter:
+		for _, mtd := range mdl.Methods {
+			if err := x.ValidateParams(mtd, true); err != nil {
+				return err
+			}
+		}
+	}
+	{
+		// The algorithm must be inferable from the func name:
+		for _, sel := range mdl.Methods.ByName(MethodInputAlgoFromFuncName).Selectors {
+			qual := sel.GetFuncQualifier()
+			if x.GuessCryptoAlgorithmFromName(qual.Name) == "" {
+				return fmt.Errorf("%s: cannot infer the algorithm from the name; please move it to the %s method", qual.ID, MethodInputAlgoFromSelector)
+			}
+		}
+		// The algorithm must be set on the selector:
+		for _, sel := range mdl.Methods.ByName(MethodInputAlgoFromSelector).Selectors {
+			qual := sel.GetFuncQualifier()
+			if qual.Algorithm == "" {
+				return fmt.Errorf("%s: the algorithm is not set", qual.ID)
+			}
+			if !x.IsKnownCryptoAlgorithm(qual.Algorithm) {
+				return fmt.Errorf("%s: unknown algorithm %q", qual.ID, qual.Algorithm)
+			}
+		}
+	}
+	return nil
+}
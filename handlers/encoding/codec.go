@@ -0,0 +1,70 @@
+package encoding
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gagliardetto/codemill/x"
+	. "github.com/gagliardetto/utilz"
+)
+
+// NOTE:
+// - Each func that you add to the input method must also be added to the output method (and vice versa).
+// - The input and output can be the receiver, a parameter, or a result.
+
+const (
+	OptionFormat = "Format" // The encoding format, e.g. `json`, `yaml`, `msgpack`.
+)
+
+// Codec generates the models and the tests of the encoding model kinds
+// (marshaling and unmarshaling), which select an input and an output element.
+type Codec struct {
+	Name       string // e.g. "Marshaling"; used in the generated comments.
+	Participle string // e.g. "marshaled"; used in the generated comments.
+	Class      string // CodeQL class whose Range is extended, e.g. "MarshalingFunction".
+
+	TestTagFormat    string // Tag of the format inline expectations; must start with a $ sign.
+	TestQueryContent string // Content of the <name>.ql test query.
+
+	GenerateBoilerplate bool
+	IncludeComments     bool
+}
+
+func (codec *Codec) lowerName() string {
+	return strings.ToLower(codec.Name)
+}
+
+// ScavengeOptions returns the options of the encoding model kinds.
+func ScavengeOptions() map[string]string {
+	return map[string]string{
+		OptionFormat: "",
+	}
+}
+
+// Validate validates the options and the coupled input and output methods of the model.
+func Validate(mdl *x.XModel, mtdInput *x.XMethod, mtdOutput *x.XMethod) error {
+	if mdl.GetOption(OptionFormat) == "" {
+		return fmt.Errorf("option %s is not set", OptionFormat)
+	}
+	{
+		// Each input selector must have a corresponding output selector (and vice versa):
+		if err := x.ValidateCoupled(mtdInput, mtdOutput); err != nil {
+			return err
+		}
+		if err := x.ValidateCoupled(mtdOutput, mtdInput); err != nil {
+			return err
+		}
+	}
+	{
+		// Both the input and the output must select at least one element:
+		for _, mtd := range []*x.XMethod{mtdInput, mtdOutput} {
+			for _, sel := range mtd.Selectors {
+				qual := sel.GetFuncQualifier()
+				if AllFalse(qual.Pos...) {
+					return fmt.Errorf("%s: no element selected in %s", qual.ID, mtd.Name)
+				}
+			}
+		}
+	}
+	return nil
+}
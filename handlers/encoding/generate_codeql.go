@@ -0,0 +1,286 @@
+package encoding
+
+import (
+	"github.com/gagliardetto/codemill/x"
+	. "github.com/gagliardetto/cqlgen/jen"
+	"github.com/gagliardetto/feparser"
+	. "github.com/gagliardetto/utilz"
+)
+
+// GenerateCodeQL adds to the module group the classes that model
+// the funcs and methods selected in the input and output methods.
+func (codec *Codec) GenerateCodeQL(mdl *x.XModel, mtdInput *x.XMethod, mtdOutput *x.XMethod, rootModuleGroup *Group) error {
+	if len(mtdInput.Selectors) == 0 {
+		Infof("No selectors found for %q method.", mtdInput.Name)
+		return nil
+	}
+
+	className := mdl.Name
+	allPathVersions := mdl.ListAllPathVersions()
+	format := mdl.GetOption(OptionFormat)
+
+	b2fe, b2tm, b2itm, err := x.GroupFuncSelectors(mtdInput)
+	if err != nil {
+		Fatalf("Error while GroupFuncSelectors: %s", err)
+	}
+	b2feOut, b2tmOut, b2itmOut, err := x.GroupFuncSelectors(mtdOutput)
+	if err != nil {
+		Fatalf("Error while GroupFuncSelectors: %s", err)
+	}
+	{
+		addedCount := 0
+		funcModelsClassName := feparser.NewCodeQlName(className, "FunctionModels")
+		tmp := DoGroup(func(tempFuncsModel *Group) {
+			tempFuncsModel.Doc(Sf("Models %s functions.", codec.lowerName()))
+			tempFuncsModel.Private().Class().Id(funcModelsClassName).Extends().Qual(codec.Class, "Range").BlockFunc(
+				func(funcModelsClassGroup *Group) {
+					funcModelsClassGroup.Id("FunctionInput").Id("inp").Semicolon().Line()
+					funcModelsClassGroup.Id("FunctionOutput").Id("outp").Semicolon().Line()
+
+					funcModelsClassGroup.Id(funcModelsClassName).Call().BlockFunc(
+						func(funcModelsSelfMethodGroup *Group) {
+							{
+								funcModelsSelfMethodGroup.DoGroup(
+									func(groupCase *Group) {
+										for _, pathVersion := range allPathVersions {
+											cont, ok := b2fe[pathVersion]
+											if ok {
+												pathCodez := make([]Code, 0)
+												for _, funcQual := range cont {
+													if AllFalse(funcQual.Pos...) {
+														continue
+													}
+
+													outQual := b2feOut[pathVersion].ByBasicQualifier(funcQual.BasicQualifier)
+													fn, code := getFuncQualifierCodeElements(funcQual, outQual)
+													thing := fn.(*feparser.FEFunc)
+													pathCodez = append(pathCodez,
+														ParensFunc(
+															func(par *Group) {
+																par.Commentf("signature: %s", thing.Signature)
+																par.This().Dot("hasQualifiedName").Call(x.CqlFormatPackagePath(funcQual.Path), Lit(thing.Name))
+																par.And()
+																par.Add(code)
+															},
+														),
+													)
+												}
+
+												if len(pathCodez) > 0 {
+													if addedCount > 0 {
+														groupCase.Or()
+													}
+													groupCase.Commentf("%s models for package: %s", codec.Name, pathVersion).Parens(
+														Join(
+															Or(),
+															pathCodez...,
+														),
+													)
+													addedCount++
+												}
+											}
+										}
+									})
+							}
+						})
+
+					addOverrides(funcModelsClassGroup, format)
+				})
+		})
+		if addedCount > 0 {
+			rootModuleGroup.Add(tmp)
+		}
+	}
+
+	{
+		addedCount := 0
+		methodModelsClassName := feparser.NewCodeQlName(className, "MethodModels")
+		tmp := DoGroup(func(tempMethodsModel *Group) {
+			tempMethodsModel.Doc(Sf("Models %s methods.", codec.lowerName()))
+			tempMethodsModel.Private().Class().Id(methodModelsClassName).Extends().List(Qual(codec.Class, "Range"), Id("Method")).BlockFunc(
+				func(methodModelsClassGroup *Group) {
+					methodModelsClassGroup.Id("FunctionInput").Id("inp").Semicolon().Line()
+					methodModelsClassGroup.Id("FunctionOutput").Id("outp").Semicolon().Line()
+
+					methodModelsClassGroup.Id(methodModelsClassName).Call().BlockFunc(
+						func(methodModelsSelfMethodGroup *Group) {
+							{
+								methodModelsSelfMethodGroup.DoGroup(
+									func(groupCase *Group) {
+										for _, pathVersion := range allPathVersions {
+											pathCodez := make([]Code, 0)
+											{
+												b2tm.IterValid(pathVersion,
+													func(receiverTypeID string, methodQualifiers x.FuncQualifierSlice) {
+														codez := DoGroup(func(mtdGroup *Group) {
+															qual := methodQualifiers[0]
+															source := x.GetCachedSource(qual.Path, qual.Version)
+															if source == nil {
+																Fatalf("Source not found: %s@%s", qual.Path, qual.Version)
+															}
+															// Find receiver type:
+															typ := x.FindTypeByID(source, receiverTypeID)
+															if typ == nil {
+																Fatalf("Type not found: %q", receiverTypeID)
+															}
+
+															mtdGroup.Commentf("Receiver type: %s", typ.TypeString)
+
+															methodIndex := 0
+															mtdGroup.ParensFunc(
+																func(parMethods *Group) {
+																	for _, methodQual := range methodQualifiers {
+																		if AllFalse(methodQual.Pos...) {
+																			continue
+																		}
+																		if methodIndex > 0 {
+																			parMethods.Or()
+																		}
+																		methodIndex++
+
+																		outQual := b2tmOut[pathVersion][receiverTypeID].ByBasicQualifier(methodQual.BasicQualifier)
+																		fn, code := getFuncQualifierCodeElements(methodQual, outQual)
+																		thing := fn.(*feparser.FETypeMethod)
+
+																		parMethods.ParensFunc(
+																			func(par *Group) {
+																				par.Commentf("signature: %s", thing.Func.Signature)
+																				par.This().Dot("hasQualifiedName").Call(x.CqlFormatPackagePath(methodQual.Path), Lit(thing.Receiver.TypeName), Lit(thing.Func.Name))
+																				par.And()
+																				par.Add(code)
+																			},
+																		)
+
+																	}
+																},
+															)
+
+														})
+														pathCodez = append(pathCodez, codez)
+													})
+											}
+
+											b2itm.IterValid(pathVersion,
+												func(receiverTypeID string, methodQualifiers x.FuncQualifierSlice) {
+													codez := DoGroup(func(mtdGroup *Group) {
+														qual := methodQualifiers[0]
+														source := x.GetCachedSource(qual.Path, qual.Version)
+														if source == nil {
+															Fatalf("Source not found: %s@%s", qual.Path, qual.Version)
+														}
+														// Find receiver type:
+														typ := x.FindTypeByID(source, receiverTypeID)
+														if typ == nil {
+															Fatalf("Type not found: %q", receiverTypeID)
+														}
+														mtdGroup.Commentf("Receiver interface: %s", typ.TypeString)
+
+														methodIndex := 0
+														mtdGroup.ParensFunc(
+															func(parMethods *Group) {
+																for _, methodQual := range methodQualifiers {
+																	if AllFalse(methodQual.Pos...) {
+																		continue
+																	}
+																	if methodIndex > 0 {
+																		parMethods.Or()
+																	}
+																	methodIndex++
+
+																	outQual := b2itmOut[pathVersion][receiverTypeID].ByBasicQualifier(methodQual.BasicQualifier)
+																	fn, code := getFuncQualifierCodeElements(methodQual, outQual)
+																	thing := fn.(*feparser.FEInterfaceMethod)
+
+																	parMethods.ParensFunc(
+																		func(par *Group) {
+																			par.Commentf("signature: %s", thing.Func.Signature)
+																			par.This().Dot("implements").Call(x.CqlFormatPackagePath(methodQual.Path), Lit(thing.Receiver.TypeName), Lit(thing.Func.Name))
+																			par.And()
+																			par.Add(code)
+																		},
+																	)
+
+																}
+															},
+														)
+
+													})
+													pathCodez = append(pathCodez, codez)
+												})
+
+											if len(pathCodez) > 0 {
+												if addedCount > 0 {
+													groupCase.Or()
+												}
+												groupCase.Commentf("%s models for package: %s", codec.Name, pathVersion).Parens(
+													Join(
+														Or(),
+														pathCodez...,
+													),
+												)
+												addedCount++
+											}
+										}
+									})
+							}
+						})
+
+					addOverrides(methodModelsClassGroup, format)
+				})
+		})
+		if addedCount > 0 {
+			rootModuleGroup.Add(tmp)
+		}
+	}
+
+	return nil
+}
+
+func addOverrides(classGroup *Group, format string) {
+	classGroup.Override().Id("FunctionInput").Id("getAnInput").Call().BlockFunc(
+		func(overrideBlockGroup *Group) {
+			overrideBlockGroup.Id("result").Eq().Id("inp")
+		})
+
+	classGroup.Override().Id("FunctionOutput").Id("getOutput").Call().BlockFunc(
+		func(overrideBlockGroup *Group) {
+			overrideBlockGroup.Id("result").Eq().Id("outp")
+		})
+
+	classGroup.Override().Id("string").Id("getFormat").Call().BlockFunc(
+		func(overrideBlockGroup *Group) {
+			overrideBlockGroup.Id("result").Eq().Lit(format)
+		})
+}
+
+func getFuncQualifierCodeElements(inpQual *x.FuncQualifier, outQual *x.FuncQualifier) (x.FuncInterface, Code) {
+	fn := x.GetFuncByQualifier(inpQual)
+
+	inpCodeElements := make([]Code, 0)
+	{
+		receiver, parameterIndexes, resultIndexes := x.PosToRelativeIndexes(fn, inpQual.Pos)
+		inpCodeElements = x.GenFunctionInputOutput("inp", fn, receiver, parameterIndexes, resultIndexes)
+	}
+
+	outCodeElements := make([]Code, 0)
+	{
+		receiver, parameterIndexes, resultIndexes := x.PosToRelativeIndexes(fn, outQual.Pos)
+		outCodeElements = x.GenFunctionInputOutput("outp", fn, receiver, parameterIndexes, resultIndexes)
+	}
+
+	code := Parens(
+		Join(
+			Or(),
+			inpCodeElements...,
+		),
+	).
+		And().
+		Parens(
+			Join(
+				Or(),
+				outCodeElements...,
+			),
+		)
+
+	return fn, code
+}
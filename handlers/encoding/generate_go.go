@@ -0,0 +1,494 @@
+package encoding
+
+import (
+	"go/types"
+	"os"
+	"path/filepath"
+
+	. "github.com/dave/jennifer/jen"
+	"github.com/gagliardetto/codebox/gogentools"
+	"github.com/gagliardetto/codemill/x"
+	"github.com/gagliardetto/feparser"
+	. "github.com/gagliardetto/utilz"
+)
+
+const (
+	// NOTE: hardcoded inside the TestQueryContent of the handlers.
+	InlineExpectationsTestTagSink = "$taintSink" // Must start with a $ sign.
+)
+
+func (codec *Codec) tagFormat(format string) Code {
+	return Comment(codec.TestTagFormat + "=" + format)
+}
+
+func tagSink() Code {
+	return Comment(InlineExpectationsTestTagSink)
+}
+
+func (codec *Codec) newTestFile() *File {
+	file := NewFile("main")
+	// Set a prefix to avoid collision between variable names and packages:
+	file.PackagePrefix = "cql"
+	// Add comment to file:
+	file.HeaderComment("Code generated by https://github.com/gagliardetto. DO NOT EDIT.")
+
+	if codec.GenerateBoilerplate {
+		file.PackageComment("//go:generate depstubber --vendor --auto")
+		{
+			// main function:
+			file.Func().Id("main").Params().Block()
+		}
+		{
+			// sink function:
+			code := Func().
+				Id("sink").
+				Params(Id("v").Op("...").Interface()).
+				Block()
+			file.Add(code.Line())
+		}
+		{
+			// The `source` function returns a new tainted thing:
+			code := Func().
+				Id("source").
+				Params().
+				Interface().
+				Block(Return(Nil()))
+			file.Add(code.Line())
+		}
+	}
+	return file
+}
+
+// GenerateGo generates the Go tests for the funcs and methods
+// selected in the input and output methods.
+func (codec *Codec) GenerateGo(parentDir string, mdl *x.XModel, mtdInput *x.XMethod, mtdOutput *x.XMethod) error {
+	// Check if there are multiple versions of a same package:
+	mods := mdl.ListModules()
+	if x.HasMultiversion(mods) {
+		Ln(RedBG("Has multiversion"))
+	}
+	// If there are no multiple versions of the same module,
+	// that means we can save all the code to one file.
+	allInOneFile := !x.HasMultiversion(mods)
+
+	// Create the directory for the tests for this model:
+	outDir := filepath.Join(parentDir, feparser.NewCodeQlName(mdl.Name))
+	MustCreateFolderIfNotExists(outDir, os.ModePerm)
+
+	if len(mtdInput.Selectors) == 0 {
+		Infof("No selectors found for %q method.", mtdInput.Name)
+		return nil
+	}
+
+	allPathVersions := mdl.ListAllPathVersions()
+	format := mdl.GetOption(OptionFormat)
+
+	file := codec.newTestFile()
+
+	b2fe, b2tm, b2itm, err := x.GroupFuncSelectors(mtdInput)
+	if err != nil {
+		Fatalf("Error while GroupFuncSelectors: %s", err)
+	}
+	b2feOut, b2tmOut, b2itmOut, err := x.GroupFuncSelectors(mtdOutput)
+	if err != nil {
+		Fatalf("Error while GroupFuncSelectors: %s", err)
+	}
+
+	for _, pathVersion := range allPathVersions {
+		if !allInOneFile {
+			// Reset file:
+			file = codec.newTestFile()
+		}
+		codez := make([]Code, 0)
+
+		{
+			cont, ok := b2fe[pathVersion]
+			if ok && x.HasValidPos(cont...) {
+				addedCount := 0
+				code := BlockFunc(
+					func(groupCase *Group) {
+
+						for _, inpQual := range cont {
+							fn := x.GetFuncByQualifier(inpQual)
+							thing := fn.(*feparser.FEFunc)
+
+							x.AddImportsFromFunc(file, thing)
+
+							{
+								if AllFalse(inpQual.Pos...) {
+									continue
+								}
+								groupCase.Comment(thing.Signature)
+
+								outQual := b2feOut[pathVersion].ByBasicQualifier(inpQual.BasicQualifier)
+
+								blocksOfCases := codec.generateGoTestBlock(
+									file,
+									thing,
+									inpQual,
+									outQual,
+									format,
+								)
+								if len(blocksOfCases) == 1 {
+									groupCase.Add(blocksOfCases...)
+								} else {
+									groupCase.Block(blocksOfCases...)
+								}
+								addedCount++
+							}
+
+						}
+					})
+				if addedCount > 0 {
+					codez = append(codez,
+						Commentf("%s via function call.", codec.Name).
+							Line().
+							Add(code),
+					)
+				}
+			}
+		}
+		{
+			codezTypeMethods := make([]Code, 0)
+			b2tm.IterValid(pathVersion,
+				func(receiverTypeID string, methodQualifiers x.FuncQualifierSlice) {
+
+					qual := methodQualifiers[0]
+					// Find receiver type:
+					typ := x.FindType(qual.Path, qual.Version, receiverTypeID)
+					if typ == nil {
+						Fatalf("Type not found: %q", receiverTypeID)
+					}
+
+					gogentools.ImportPackage(file, typ.PkgPath, typ.PkgName)
+
+					code := BlockFunc(
+						func(groupCase *Group) {
+
+							for _, inpQual := range methodQualifiers {
+								fn := x.GetFuncByQualifier(inpQual)
+								thing := fn.(*feparser.FETypeMethod)
+								x.AddImportsFromFunc(file, fn)
+
+								{
+									if AllFalse(inpQual.Pos...) {
+										continue
+									}
+									groupCase.Comment(thing.Func.Signature)
+
+									outQual := b2tmOut[pathVersion][receiverTypeID].ByBasicQualifier(inpQual.BasicQualifier)
+
+									blocksOfCases := codec.generateGoTestBlock(
+										file,
+										thing,
+										inpQual,
+										outQual,
+										format,
+									)
+									if len(blocksOfCases) == 1 {
+										groupCase.Add(blocksOfCases...)
+									} else {
+										groupCase.Block(blocksOfCases...)
+									}
+								}
+
+							}
+						})
+					codezTypeMethods = append(codezTypeMethods,
+						Commentf("%s via method calls on %s.", codec.Name, typ.QualifiedName).
+							Line().
+							Add(code),
+					)
+				})
+			if len(codezTypeMethods) > 0 {
+				codez = append(codez,
+					Commentf("%s via method calls.", codec.Name).
+						Line().
+						Block(codezTypeMethods...),
+				)
+			}
+		}
+
+		{
+			codezIfaceMethods := make([]Code, 0)
+			b2itm.IterValid(pathVersion,
+				func(receiverTypeID string, methodQualifiers x.FuncQualifierSlice) {
+					qual := methodQualifiers[0]
+					// Find receiver type:
+					typ := x.FindType(qual.Path, qual.Version, receiverTypeID)
+					if typ == nil {
+						Fatalf("Type not found: %q", receiverTypeID)
+					}
+
+					gogentools.ImportPackage(file, typ.PkgPath, typ.PkgName)
+
+					code := BlockFunc(
+						func(groupCase *Group) {
+
+							for _, inpQual := range methodQualifiers {
+								fn := x.GetFuncByQualifier(inpQual)
+								thing := fn.(*feparser.FEInterfaceMethod)
+								x.AddImportsFromFunc(file, fn)
+
+								{
+									if AllFalse(inpQual.Pos...) {
+										continue
+									}
+									groupCase.Comment(thing.Func.Signature)
+
+									converted := feparser.FEIToFET(thing)
+
+									outQual := b2itmOut[pathVersion][receiverTypeID].ByBasicQualifier(inpQual.BasicQualifier)
+
+									blocksOfCases := codec.generateGoTestBlock(
+										file,
+										converted,
+										inpQual,
+										outQual,
+										format,
+									)
+									if len(blocksOfCases) == 1 {
+										groupCase.Add(blocksOfCases...)
+									} else {
+										groupCase.Block(blocksOfCases...)
+									}
+								}
+							}
+						})
+					codezIfaceMethods = append(codezIfaceMethods,
+						Commentf("%s via method calls on %s interface.", codec.Name, typ.QualifiedName).
+							Line().
+							Add(code),
+					)
+				})
+
+			if len(codezIfaceMethods) > 0 {
+				codez = append(codez,
+					Commentf("%s via interface method calls.", codec.Name).
+						Line().
+						Block(codezIfaceMethods...),
+				)
+			}
+		}
+
+		{
+			file.Commentf("Package %s", pathVersion)
+			file.Func().Id(mdl.Name + "_" + feparser.FormatCodeQlName(pathVersion)).Params().Block(codez...)
+		}
+
+		if !allInOneFile {
+			pkgDstDirpath := filepath.Join(outDir, feparser.FormatID(mdl.Name, "For", feparser.FormatCodeQlName(pathVersion)))
+			MustCreateFolderIfNotExists(pkgDstDirpath, os.ModePerm)
+
+			assetFileName := feparser.FormatID(mdl.Name, "For", feparser.FormatCodeQlName(pathVersion)) + ".go"
+			if err := x.SaveGoFile(pkgDstDirpath, assetFileName, file); err != nil {
+				Fatalf("Error while saving go file: %s", err)
+			}
+
+			if err := x.WriteGoModFile(pkgDstDirpath, pathVersion); err != nil {
+				Fatalf("Error while saving go.mod file: %s", err)
+			}
+			if err := x.WriteCodeQLTestQuery(pkgDstDirpath, mdl.Name, codec.TestQueryContent); err != nil {
+				Fatalf("Error while saving <name>.ql file: %s", err)
+			}
+			if err := x.WriteEmptyCodeQLDotExpectedFile(pkgDstDirpath, mdl.Name); err != nil {
+				Fatalf("Error while saving <name>.expected file: %s", err)
+			}
+		}
+	}
+
+	if allInOneFile {
+		pkgDstDirpath := outDir
+		MustCreateFolderIfNotExists(pkgDstDirpath, os.ModePerm)
+
+		assetFileName := feparser.FormatID(mdl.Name) + ".go"
+		if err := x.SaveGoFile(pkgDstDirpath, assetFileName, file); err != nil {
+			Fatalf("Error while saving go file: %s", err)
+		}
+
+		if err := x.WriteGoModFile(pkgDstDirpath, allPathVersions...); err != nil {
+			Fatalf("Error while saving go.mod file: %s", err)
+		}
+		if err := x.WriteCodeQLTestQuery(pkgDstDirpath, mdl.Name, codec.TestQueryContent); err != nil {
+			Fatalf("Error while saving <name>.ql file: %s", err)
+		}
+		if err := x.WriteEmptyCodeQLDotExpectedFile(pkgDstDirpath, mdl.Name); err != nil {
+			Fatalf("Error while saving <name>.expected file: %s", err)
+		}
+	}
+	return nil
+}
+
+// comments adds comments to a Group (if enabled), and returns the group.
+func (codec *Codec) comments(group *Group, comments ...string) *Group {
+	if codec.IncludeComments {
+		for _, comment := range comments {
+			group.Line().Comment(comment)
+		}
+	}
+	return group
+}
+
+// for each combination of inp and out, generate a golang test block.
+func (codec *Codec) generateGoTestBlock(
+	file *File,
+	fn x.FuncInterface,
+	inpQual *x.FuncQualifier,
+	outQual *x.FuncQualifier,
+	format string,
+) []Code {
+	childBlocks := make([]Code, 0)
+	for inpIndex, inpOk := range inpQual.Pos {
+		if !inpOk {
+			continue
+		}
+		for outIndex, outOk := range outQual.Pos {
+			if !outOk {
+				continue
+			}
+			childBlock := codec.generate(
+				file,
+				fn,
+				inpIndex,
+				outIndex,
+				format,
+			)
+			{
+				if childBlock != nil {
+					childBlocks = append(childBlocks, childBlock)
+				} else {
+					Warnf(Sf("NOTHING GENERATED; inp %v, out %v", inpIndex, outIndex))
+				}
+			}
+		}
+	}
+
+	return childBlocks
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+func (codec *Codec) generate(file *File, fn x.FuncInterface, inpIndex int, outIndex int, format string) *Statement {
+
+	inpElem, _, inpRelIndex, err := fn.GetRelativeElement(inpIndex)
+	if err != nil {
+		panic(err)
+	}
+	outElem, _, outRelIndex, err := fn.GetRelativeElement(outIndex)
+	if err != nil {
+		panic(err)
+	}
+
+	Receiver := feparser.ElementReceiver
+	Parameter := feparser.ElementParameter
+	Result := feparser.ElementResult
+
+	if inpElem == Result {
+		Warnf("Result as input is not supported: %s", fn.GetFunc().Signature)
+		return nil
+	}
+	if inpIndex == outIndex {
+		return nil
+	}
+
+	fe := fn.GetFunc()
+	hasReceiver := fn.GetReceiver() != nil
+
+	if hasReceiver {
+		rece := fn.GetReceiver()
+		switch {
+		case inpElem == Receiver:
+			rece.VarName = gogentools.NewNameWithPrefix(feparser.NewLowerTitleName("from", rece.TypeName))
+		case outElem == Receiver:
+			rece.VarName = gogentools.NewNameWithPrefix(feparser.NewLowerTitleName("into", rece.TypeName))
+		default:
+			rece.VarName = "rece"
+		}
+	}
+	if inpElem == Parameter {
+		in := fe.Parameters[inpRelIndex]
+		in.VarName = gogentools.NewNameWithPrefix(feparser.NewLowerTitleName("from", in.TypeName))
+	}
+	var outVarName string
+	switch outElem {
+	case Receiver:
+		outVarName = fn.GetReceiver().VarName
+	case Parameter:
+		out := fe.Parameters[outRelIndex]
+		out.VarName = gogentools.NewNameWithPrefix(feparser.NewLowerTitleName("into", out.TypeName))
+		outVarName = out.VarName
+	case Result:
+		out := fe.Results[outRelIndex]
+		out.VarName = gogentools.NewNameWithPrefix(feparser.NewLowerTitleName("into", out.TypeName))
+		outVarName = out.VarName
+	}
+
+	code := BlockFunc(
+		func(groupCase *Group) {
+
+			if hasReceiver {
+				rece := fn.GetReceiver()
+				if inpElem == Receiver {
+					codec.comments(groupCase, Sf("Assume that `sourceCQL` has the underlying type of `%s`:", rece.VarName))
+					x.ComposeSourceTypeAssertion(file, groupCase, rece.VarName, rece.GetOriginal(), rece.Is.Variadic)
+				} else {
+					codec.comments(groupCase, "Declare medium object/interface:")
+					gogentools.ComposeVarDeclaration(file, groupCase, rece.VarName, rece.GetOriginal(), rece.Is.Variadic)
+				}
+			}
+			if inpElem == Parameter {
+				in := fe.Parameters[inpRelIndex]
+				codec.comments(groupCase, Sf("Assume that `sourceCQL` has the underlying type of `%s`:", in.VarName))
+				x.ComposeSourceTypeAssertion(file, groupCase, in.VarName, in.GetOriginal().GetType(), in.GetOriginal().IsVariadic())
+			}
+			if outElem == Parameter {
+				out := fe.Parameters[outRelIndex]
+				codec.comments(groupCase, Sf("Declare `%s` variable:", out.VarName))
+				gogentools.ComposeVarDeclaration(file, groupCase, out.VarName, out.GetOriginal().GetType(), out.GetOriginal().IsVariadic())
+			}
+
+			gogentools.ImportPackage(file, fe.PkgPath, fe.PkgName)
+
+			var callee *Statement
+			if hasReceiver {
+				callee = Id(fn.GetReceiver().VarName).Dot(fe.Name)
+			} else {
+				callee = Qual(fe.PkgPath, fe.Name)
+			}
+			callee.CallFunc(
+				func(call *Group) {
+
+					tpFun := fe.GetOriginal().GetType().(*types.Signature)
+
+					zeroVals := gogentools.ScanTupleOfZeroValues(file, tpFun.Params(), fe.GetOriginal().IsVariadic())
+
+					for i, zero := range zeroVals {
+						isConsidered := (inpElem == Parameter && i == inpRelIndex) || (outElem == Parameter && i == outRelIndex)
+						if isConsidered {
+							call.Id(fe.Parameters[i].VarName)
+						} else {
+							call.Add(zero)
+						}
+					}
+
+				},
+			)
+
+			if outElem == Result {
+				groupCase.ListFunc(func(resGroup *Group) {
+					for i, v := range fe.Results {
+						if i == outRelIndex {
+							resGroup.Id(v.VarName)
+						} else {
+							resGroup.Id("_")
+						}
+					}
+				}).Op(":=").Add(callee).Add(codec.tagFormat(format))
+			} else {
+				groupCase.Add(callee).Add(codec.tagFormat(format))
+			}
+
+			codec.comments(groupCase, Sf("Return the %s `%s`:", codec.Participle, outVarName))
+			groupCase.Id("sink").Call(Id(outVarName)).Add(tagSink())
+		})
+	return code
+}
@@ -0,0 +1,80 @@
+package marshaling
+
+import (
+	"github.com/gagliardetto/codemill/x"
+)
+
+var (
+	GenerateBoilerplate bool
+)
+
+const (
+	// NOTE: hardcoded inside TestQueryContent const.
+	InlineExpectationsTestTagFormat = "$marshalingFormat" // Must start with a $ sign.
+)
+
+const (
+	TestQueryContent = `
+import go
+import TestUtilities.InlineExpectationsTest
+
+class Configuration extends TaintTracking::Configuration {
+  Configuration() { this = "test-configuration" }
+
+  override predicate isSource(DataFlow::Node source) {
+    exists(Function fn | fn.hasQualifiedName(_, "source") | source = fn.getACall().getResult())
+  }
+
+  override predicate isSink(DataFlow::Node sink) {
+    exists(Function fn | fn.hasQualifiedName(_, "sink") | sink = fn.getACall().getAnArgument())
+  }
+
+  override predicate isAdditionalTaintStep(DataFlow::Node pred, DataFlow::Node succ) {
+    exists(MarshalingFunction m, DataFlow::CallNode call | call = m.getACall() |
+      pred = m.getAnInput().getNode(call) and
+      succ = m.getOutput().getNode(call)
+    )
+  }
+}
+
+class MarshalingFunctionTest extends InlineExpectationsTest {
+  MarshalingFunctionTest() { this = "MarshalingFunctionTest" }
+
+  override string getARelevantTag() { result = ["marshalingFormat", "taintSink"] }
+
+  override predicate hasActualResult(string file, int line, string element, string tag, string value) {
+    tag = "marshalingFormat" and
+    exists(MarshalingFunction m, DataFlow::CallNode call | call = m.getACall() |
+      call.hasLocationInfo(file, line, _, _, _) and
+      element = call.toString() and
+      value = m.getFormat()
+    )
+    or
+    tag = "taintSink" and
+    exists(DataFlow::Node sink | any(Configuration c).hasFlow(_, sink) |
+      element = sink.toString() and
+      value = "" and
+      sink.hasLocationInfo(file, line, _, _, _)
+    )
+  }
+}
+`
+)
+
+var (
+	IncludeCommentsInGeneratedGo bool
+)
+
+func (han *Handler) GenerateGo(parentDir string, mdl *x.XModel) error {
+	if err := mdl.Validate(); err != nil {
+		return err
+	}
+	if err := han.Validate(mdl); err != nil {
+		return err
+	}
+
+	cdc := *codec
+	cdc.GenerateBoilerplate = GenerateBoilerplate
+	cdc.IncludeComments = IncludeCommentsInGeneratedGo
+	return cdc.GenerateGo(parentDir, mdl, mdl.Methods.ByName(MethodInput), mdl.Methods.ByName(MethodOutput))
+}
@@ -0,0 +1,17 @@
+package unmarshaling
+
+import (
+	"github.com/gagliardetto/codemill/x"
+	. "github.com/gagliardetto/cqlgen/jen"
+)
+
+func (han *Handler) GenerateCodeQL(impAdder x.ImportAdder, mdl *x.XModel, rootModuleGroup *Group) error {
+	if err := mdl.Validate(); err != nil {
+		return err
+	}
+	if err := han.Validate(mdl); err != nil {
+		return err
+	}
+
+	return codec.GenerateCodeQL(mdl, mdl.Methods.ByName(MethodInput), mdl.Methods.ByName(MethodOutput), rootModuleGroup)
+}
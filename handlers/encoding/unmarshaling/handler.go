@@ -0,0 +1,65 @@
+package unmarshaling
+
+import (
+	"fmt"
+
+	"github.com/gagliardetto/codemill/handlers/encoding"
+	"github.com/gagliardetto/codemill/x"
+)
+
+// NOTE:
+// - Each func that you add to MethodInput must also be added to MethodOutput (and vice versa).
+// - The input and output can be the receiver, a parameter, or a result.
+
+const (
+	Kind x.ModelKind = "Encoding::UnmarshalingFunction"
+)
+
+type Handler struct{}
+
+const (
+	MethodInput  = "{inp:Elem, out:Elem} <- $inp" // The element that is unmarshaled.
+	MethodOutput = "{inp:Elem, out:Elem} <- $out" // The element that receives the unmarshaled data.
+)
+
+const (
+	OptionFormat = encoding.OptionFormat // The encoding format, e.g. `json`, `yaml`, `msgpack`.
+)
+
+//
+func (han *Handler) ScavengeMethods() []*x.XMethod {
+	return x.ScavengeMethods(
+		// Each func that you add to MethodInput,
+		// you must also add it to MethodOutput.
+		MethodInput,  // "Coupled 1/2: Select the element (receiver/param/result) that is unmarshaled.",
+		MethodOutput, // "Coupled 2/2: Select the element (receiver/param/result) that receives the unmarshaled data.",
+	)
+}
+
+//
+func (han *Handler) ScavengeOptions() map[string]string {
+	return encoding.ScavengeOptions()
+}
+func (han *Handler) Validate(mdl *x.XModel) error {
+	defaultMthNum := len(han.ScavengeMethods())
+	if len(mdl.Methods) != defaultMthNum {
+		return fmt.Errorf("wrong number of methods; expected %v, got %v", defaultMthNum, len(mdl.Methods))
+	}
+	{
+		for i, must := range han.ScavengeMethods() {
+			if mdl.Methods[i].Name != must.Name {
+				return fmt.Errorf("#%v method is not called %s", i, must.Name)
+			}
+		}
+	}
+	return encoding.Validate(mdl, mdl.Methods.ByName(MethodInput), mdl.Methods.ByName(MethodOutput))
+}
+
+// codec generates the models and the tests of the model.
+var codec = &encoding.Codec{
+	Name:             "Unmarshaling",
+	Participle:       "unmarshaled",
+	Class:            "UnmarshalingFunction",
+	TestTagFormat:    InlineExpectationsTestTagFormat,
+	TestQueryContent: TestQueryContent,
+}
@@ -0,0 +1,54 @@
+package filesystemaccess
+
+import (
+	"github.com/gagliardetto/codemill/x"
+)
+
+var (
+	GenerateBoilerplate bool
+)
+
+const (
+	// NOTE: hardcoded inside TestQueryContent const.
+	InlineExpectationsTestTag = "$fsaccess" // Must start with a $ sign.
+)
+
+const (
+	TestQueryContent = `
+import go
+import TestUtilities.InlineExpectationsTest
+
+class FileSystemAccessTest extends InlineExpectationsTest {
+  FileSystemAccessTest() { this = "FileSystemAccessTest" }
+
+  override string getARelevantTag() { result = "fsaccess" }
+
+  override predicate hasActualResult(string file, int line, string element, string tag, string value) {
+    tag = "fsaccess" and
+    exists(FileSystemAccess fsa |
+      fsa.hasLocationInfo(file, line, _, _, _) and
+      element = fsa.getAFileNameOperand().toString() and
+      value = fsa.getAFileNameOperand().toString()
+    )
+  }
+}
+`
+)
+
+var (
+	IncludeCommentsInGeneratedGo bool
+)
+
+func (han *Handler) GenerateGo(parentDir string, mdl *x.XModel) error {
+	if err := mdl.Validate(); err != nil {
+		return err
+	}
+	if err := han.Validate(mdl); err != nil {
+		return err
+	}
+
+	sink := *paramSink
+	sink.GenerateBoilerplate = GenerateBoilerplate
+	sink.IncludeComments = IncludeCommentsInGeneratedGo
+	return sink.GenerateGo(parentDir, mdl, mdl.Methods.ByName(MethodPath))
+}
@@ -0,0 +1,50 @@
+package filesystemaccess
+
+import (
+	"fmt"
+
+	"github.com/gagliardetto/codemill/x"
+)
+
+const (
+	Kind x.ModelKind = "FileSystemAccess"
+)
+
+type Handler struct{}
+
+const (
+	MethodPath = "{path:Param} <- $path"
+)
+
+//
+func (han *Handler) ScavengeMethods() []*x.XMethod {
+	return []*x.XMethod{
+		{
+			Name:      MethodPath,
+			Selectors: []*x.XSelector{},
+		},
+	}
+}
+func (han *Handler) Validate(mdl *x.XModel) error {
+	if len(mdl.Methods) != 1 {
+		return fmt.Errorf("wrong number of methods; expected 1, got %v", len(mdl.Methods))
+	}
+	{
+		if mdl.Methods[0].Name != MethodPath {
+			return fmt.Errorf("#0 method is not called %s", MethodPath)
+		}
+	}
+	return nil
+}
+
+// paramSink generates the models and the tests for the MethodPath selectors.
+var paramSink = &x.ParamSink{
+	Doc:              "Models file system accesses.",
+	Extends:          "FileSystemAccess::Range",
+	Comment:          "File system access",
+	NodeName:         "pathNode",
+	Override:         "getAFileNameOperand",
+	TestTag:          InlineExpectationsTestTag,
+	TestQueryContent: TestQueryContent,
+	VarPrefix:        "path",
+}
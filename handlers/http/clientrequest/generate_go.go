@@ -0,0 +1,54 @@
+package clientrequest
+
+import (
+	"github.com/gagliardetto/codemill/x"
+)
+
+var (
+	GenerateBoilerplate bool
+)
+
+const (
+	// NOTE: hardcoded inside TestQueryContent const.
+	InlineExpectationsTestTag = "$clientRequestUrl" // Must start with a $ sign.
+)
+
+const (
+	TestQueryContent = `
+import go
+import TestUtilities.InlineExpectationsTest
+
+class HttpClientRequestTest extends InlineExpectationsTest {
+  HttpClientRequestTest() { this = "HttpClientRequestTest" }
+
+  override string getARelevantTag() { result = "clientRequestUrl" }
+
+  override predicate hasActualResult(string file, int line, string element, string tag, string value) {
+    tag = "clientRequestUrl" and
+    exists(HTTP::ClientRequest req |
+      req.hasLocationInfo(file, line, _, _, _) and
+      element = req.getUrl().toString() and
+      value = req.getUrl().toString()
+    )
+  }
+}
+`
+)
+
+var (
+	IncludeCommentsInGeneratedGo bool
+)
+
+func (han *Handler) GenerateGo(parentDir string, mdl *x.XModel) error {
+	if err := mdl.Validate(); err != nil {
+		return err
+	}
+	if err := han.Validate(mdl); err != nil {
+		return err
+	}
+
+	sink := *paramSink
+	sink.GenerateBoilerplate = GenerateBoilerplate
+	sink.IncludeComments = IncludeCommentsInGeneratedGo
+	return sink.GenerateGo(parentDir, mdl, mdl.Methods.ByName(MethodRequestURL))
+}
@@ -0,0 +1,50 @@
+package clientrequest
+
+import (
+	"fmt"
+
+	"github.com/gagliardetto/codemill/x"
+)
+
+const (
+	Kind x.ModelKind = "HTTP::ClientRequest"
+)
+
+type Handler struct{}
+
+const (
+	MethodRequestURL = "{requestUrl:Param} <- $requestUrl" // The URL of the outgoing request.
+)
+
+//
+func (han *Handler) ScavengeMethods() []*x.XMethod {
+	return []*x.XMethod{
+		{
+			Name:      MethodRequestURL,
+			Selectors: []*x.XSelector{},
+		},
+	}
+}
+func (han *Handler) Validate(mdl *x.XModel) error {
+	if len(mdl.Methods) != 1 {
+		return fmt.Errorf("wrong number of methods; expected 1, got %v", len(mdl.Methods))
+	}
+	{
+		if mdl.Methods[0].Name != MethodRequestURL {
+			return fmt.Errorf("#0 method is not called %s", MethodRequestURL)
+		}
+	}
+	return nil
+}
+
+// paramSink generates the models and the tests for the MethodRequestURL selectors.
+var paramSink = &x.ParamSink{
+	Doc:              "Models outgoing HTTP client requests.",
+	Extends:          "HTTP::ClientRequest::Range",
+	Comment:          "HTTP client request",
+	NodeName:         "requestUrlNode",
+	Override:         "getUrl",
+	TestTag:          InlineExpectationsTestTag,
+	TestQueryContent: TestQueryContent,
+	VarPrefix:        "requestUrl",
+}
@@ -0,0 +1,118 @@
+package cookiewrite
+
+import (
+	"github.com/gagliardetto/codemill/x"
+	. "github.com/gagliardetto/cqlgen/jen"
+	"github.com/gagliardetto/feparser"
+	. "github.com/gagliardetto/utilz"
+)
+
+func (han *Handler) GenerateCodeQL(impAdder x.ImportAdder, mdl *x.XModel, rootModuleGroup *Group) error {
+	if err := mdl.Validate(); err != nil {
+		return err
+	}
+	if err := han.Validate(mdl); err != nil {
+		return err
+	}
+
+	// Assuming the validation has already been done:
+	mtdCookieName := mdl.Methods.ByName(MethodCookieName)
+	mtdCookieValue := mdl.Methods.ByName(MethodCookieValue)
+	mtdCookieObject := mdl.Methods.ByName(MethodCookieObject)
+
+	{
+		// Add imports:
+		//impAdder.Import("DataFlow::PathGraph")
+	}
+
+	className := mdl.Name
+	allPathVersions := mdl.ListAllPathVersions()
+
+	if len(mtdCookieName.Selectors) == 0 {
+		Infof("No selectors found for %q method.", mtdCookieName.Name)
+	} else {
+		// Cookie name-value:
+		funcModelsClassName := feparser.NewCodeQlName(className)
+
+		// NOTE: the name and the value are coupled, so both
+		// disjunctions bind `this` to the same call.
+		cookieNameCode, cookieNameCount := x.CqlCallTargets(allPathVersions, mtdCookieName, "this", "Cookie write models", x.CqlBindArgument("this", "cookieNameNode"))
+		cookieValueCode, _ := x.CqlCallTargets(allPathVersions, mtdCookieValue, "this", "Cookie write models", x.CqlBindArgument("this", "cookieValueNode"))
+
+		tmp := DoGroup(func(tempFuncsModel *Group) {
+			tempFuncsModel.Doc(
+				"Models HTTP cookie writers.",
+				"The write is done with a call where you can set both the name and the value of the cookie.",
+			)
+			tempFuncsModel.Private().Class().Id(funcModelsClassName).Extends().List(
+				Id("HTTP::CookieWrite::Range"),
+				Id("DataFlow::CallNode"),
+			).BlockFunc(
+				func(blockBody *Group) {
+					blockBody.String().Id("package").Semicolon().Line()
+					blockBody.Id("DataFlow::Node").Id("cookieNameNode").Semicolon().Line()
+					blockBody.Id("DataFlow::Node").Id("cookieValueNode").Semicolon().Line()
+
+					blockBody.Id(funcModelsClassName).Call().Block(
+						Parens(cookieNameCode).And().Parens(cookieValueCode),
+					)
+
+					blockBody.Override().Id("DataFlow::Node").Id("getName").Call().Block(
+						Id("result").Eq().Id("cookieNameNode"),
+					)
+					blockBody.Override().Id("DataFlow::Node").Id("getValue").Call().Block(
+						Id("result").Eq().Id("cookieValueNode"),
+					)
+					blockBody.Override().Id("DataFlow::Node").Id("getCookieObject").Call().Block(
+						None(),
+					)
+				})
+		})
+		if cookieNameCount > 0 {
+			rootModuleGroup.Add(tmp)
+		}
+	}
+
+	if len(mtdCookieObject.Selectors) == 0 {
+		Infof("No selectors found for %q method.", mtdCookieObject.Name)
+	} else {
+		// Cookie object:
+		funcModelsClassName := feparser.NewCodeQlName(className, "CookieObject")
+
+		cookieObjectCode, cookieObjectCount := x.CqlCallTargets(allPathVersions, mtdCookieObject, "this", "Cookie write models", x.CqlBindArgument("this", "cookieObjectNode"))
+
+		tmp := DoGroup(func(tempFuncsModel *Group) {
+			tempFuncsModel.Doc(
+				"Models HTTP cookie writers.",
+				"The write is done with a call where you set a whole cookie object.",
+			)
+			tempFuncsModel.Private().Class().Id(funcModelsClassName).Extends().List(
+				Id("HTTP::CookieWrite::Range"),
+				Id("DataFlow::CallNode"),
+			).BlockFunc(
+				func(blockBody *Group) {
+					blockBody.String().Id("package").Semicolon().Line()
+					blockBody.Id("DataFlow::Node").Id("cookieObjectNode").Semicolon().Line()
+
+					blockBody.Id(funcModelsClassName).Call().Block(
+						cookieObjectCode,
+					)
+
+					blockBody.Override().Id("DataFlow::Node").Id("getName").Call().Block(
+						None(),
+					)
+					blockBody.Override().Id("DataFlow::Node").Id("getValue").Call().Block(
+						None(),
+					)
+					blockBody.Override().Id("DataFlow::Node").Id("getCookieObject").Call().Block(
+						Id("result").Eq().Id("cookieObjectNode"),
+					)
+				})
+		})
+		if cookieObjectCount > 0 {
+			rootModuleGroup.Add(tmp)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,73 @@
+package cookiewrite
+
+import (
+	"fmt"
+
+	"github.com/gagliardetto/codemill/x"
+)
+
+// NOTE:
+// - Each func that you add to MethodCookieName must also be added to MethodCookieValue (and vice versa).
+// - Funcs that set a whole cookie object go to MethodCookieObject.
+// - Each selector must select exactly one parameter.
+
+const (
+	Kind x.ModelKind = "HTTP::CookieWrite"
+)
+
+type Handler struct{}
+
+const (
+	MethodCookieName  = "{name:Param, val:Param} <- $name" // The name of the cookie.
+	MethodCookieValue = "{name:Param, val:Param} <- $val"  // The value of the cookie.
+
+	MethodCookieObject = "{cookie:Param} <- $cookie" // The cookie object (e.g. *http.Cookie).
+)
+
+//
+func (han *Handler) ScavengeMethods() []*x.XMethod {
+	return x.ScavengeMethods(
+		// Each func that you add to MethodCookieName,
+		// you must also add it to MethodCookieValue.
+		MethodCookieName,  // "Coupled 1/2: Select the parameter that specifies the name of the cookie.",
+		MethodCookieValue, // "Coupled 2/2: Select the parameter that specifies the value of the cookie.",
+
+		MethodCookieObject, // "Select the cookie-object parameter of any function that sets a whole cookie.",
+	)
+}
+func (han *Handler) Validate(mdl *x.XModel) error {
+	defaultMthNum := len(han.ScavengeMethods())
+	if len(mdl.Methods) != defaultMthNum {
+		return fmt.Errorf("wrong number of methods; expected %v, got %v", defaultMthNum, len(mdl.Methods))
+	}
+	{
+		for i, must := range han.ScavengeMethods() {
+			if mdl.Methods[i].Name != must.Name {
+				return fmt.Errorf("#%v method is not called %s", i, must.Name)
+			}
+		}
+	}
+	{
+		// Each name selector must have a corresponding value selector (and vice versa):
+		mtdName := mdl.Methods.ByName(MethodCookieName)
+		mtdValue := mdl.Methods.ByName(MethodCookieValue)
+		for _, mtd := range []*x.XMethod{mtdName, mtdValue} {
+			if err := x.ValidateParams(mtd, true); err != nil {
+				return err
+			}
+		}
+		if err := x.ValidateCoupled(mtdName, mtdValue); err != nil {
+			return err
+		}
+		if err := x.ValidateCoupled(mtdValue, mtdName); err != nil {
+			return err
+		}
+	}
+	{
+		// The cookie object must be exactly one parameter:
+		if err := x.ValidateParams(mdl.Methods.ByName(MethodCookieObject), true); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -11,6 +11,7 @@ import (
 // Predicate names:
 const (
 	setsHeaderDynamicKeyValue = "setsHeaderDynamicKeyValue"
+	setsHeaderMap             = "setsHeaderMap"
 )
 
 func (han *Handler) GenerateCodeQL(impAdder x.ImportAdder, mdl *x.XModel, rootModuleGroup *Group) error {
@@ -78,6 +79,87 @@ func (han *Handler) GenerateCodeQL(impAdder x.ImportAdder, mdl *x.XModel, rootMo
 		}
 	}
 
+	{
+		// Header maps:
+		funcModelsClassName := feparser.NewCodeQlName(className, "HeaderMap")
+		tmp := DoGroup(func(tempFuncsModel *Group) {
+			tempFuncsModel.Doc(
+				"Models HTTP header writers.",
+				"The write is done by adding an entry to a map of headers that is then passed to a call.",
+			)
+			tempFuncsModel.Private().Class().Id(funcModelsClassName).Extends().List(
+				Id("HTTP::HeaderWrite::Range"),
+				Id("DataFlow::ExprNode"),
+			).BlockFunc(
+				func(blockBody *Group) {
+
+					blockBody.Id("DataFlow::Node").Id("receiverNode").Semicolon().Line()
+					blockBody.Id("DataFlow::Node").Id("headerNameNode").Semicolon().Line()
+
+					blockBody.Id(funcModelsClassName).Call().Block(
+						Exists(
+							List(
+								Id("DataFlow::Node").Id("headersNode"),
+								Id("DataFlow::Write").Id("w"),
+								Id("DataFlow::Node").Id("base"),
+							),
+							DoGroup(func(st *Group) {
+								st.Id(setsHeaderMap).Call(
+									DontCare(),
+									DontCare(),
+									DontCare(),
+									Id("headersNode"),
+									Id("receiverNode"),
+								)
+								st.And()
+								st.Id("w").Dot("writesElement").Call(Id("base"), Id("headerNameNode"), This())
+							}),
+							DoGroup(func(st *Group) {
+								st.Comment("The entry is written to the map literal passed to the call,")
+								st.Comment("or to a variable that is then (i.e. after the write) passed to the call:")
+								st.Id("base").Eq().Id("headersNode")
+								st.Or()
+								st.Exists(
+									List(
+										Id("Variable").Id("v"),
+									),
+									DoGroup(func(ex *Group) {
+										ex.Id("base").Eq().Id("v").Dot("getARead").Call()
+										ex.And()
+										ex.Id("headersNode").Eq().Id("v").Dot("getARead").Call()
+										ex.And()
+										ex.Id("headersNode").Dot("asInstruction").Call().Eq().Id("w").Dot("getASuccessor+").Call()
+									}),
+									nil,
+								)
+							}),
+						),
+					)
+
+					blockBody.Override().Id("DataFlow::Node").Id("getName").Call().Block(
+						Id("result").Eq().Id("headerNameNode"),
+					)
+					blockBody.Override().Id("DataFlow::Node").Id("getValue").Call().Block(
+						Id("result").Eq().This(),
+					)
+					blockBody.Override().Id("HTTP::ResponseWriter").Id("getResponseWriter").Call().BlockFunc(
+						func(overrideBlockGroup *Group) {
+							overrideBlockGroup.Id("result").Dot("getANode").Call().Eq().Id("receiverNode")
+						})
+				})
+		})
+		mtdHeaderMap := mdl.Methods.ByName(MethodHeaderMap)
+		if len(mtdHeaderMap.Selectors) == 0 {
+			Infof("No selectors found for %q method.", mtdHeaderMap.Name)
+		} else {
+			pred := predicate_setsHeaderMap(allPathVersions, mtdHeaderMap)
+			if pred != nil {
+				rootModuleGroup.Add(tmp)
+				rootModuleGroup.Add(pred)
+			}
+		}
+	}
+
 	{ // Content-Type header writers:
 		contentTypeHeaderKey := "content-type"
 
@@ -92,7 +174,7 @@ func (han *Handler) GenerateCodeQL(impAdder x.ImportAdder, mdl *x.XModel, rootMo
 					allPathVersions,
 					mtdStaticValueFromFuncName,
 					rootModuleGroup,
-					x.GuessContentTypeFromName,
+					contentTypeGuesser(mdl),
 				)
 			}
 		}
@@ -120,7 +202,7 @@ func hardcodedKey_staticValue(
 	allPathVersions []string,
 	mtdStaticValueFromFuncName *x.XMethod,
 	rootModuleGroup *Group,
-	guesser func(string) string,
+	guesser func(qual *x.FuncQualifier, funcName string) string,
 ) {
 	// Static value:
 	funcModelsClassName := feparser.NewCodeQlName("Static", headerKey, "HeaderSetter")
@@ -249,7 +331,7 @@ func predicate_setsHeaderDynamicKeyValue(allPathVersions []string, mdl *x.XModel
 			if len(pc) > 0 {
 				addedCount++
 			}
-			predicateBlock.Add(pc...)
+			predicateBlock.Add(Join(Or(), pc...))
 		}
 	})
 	if addedCount == 0 {
@@ -262,7 +344,7 @@ func predicate_setsStaticHeaderValue(
 	headerKey string,
 	allPathVersions []string,
 	mtdStaticValueFromFuncName *x.XMethod,
-	guesser func(string) string,
+	guesser func(qual *x.FuncQualifier, funcName string) string,
 ) Code {
 	predicate := Commentf("Holds for a call that sets the `%s` header (implicit).", headerKey).
 		Private().Predicate().Id("setsStaticHeader" + feparser.NewCodeQlName(headerKey)).Call(
@@ -287,7 +369,7 @@ func predicate_setsStaticHeaderValue(
 			if len(pc) > 0 {
 				addedCount++
 			}
-			predicateBlock.Add(pc...)
+			predicateBlock.Add(Join(Or(), pc...))
 		}
 	})
 	if addedCount == 0 {
@@ -316,14 +398,14 @@ func predicate_setsDynamicHeaderValue(
 	predicate.BlockFunc(func(predicateBlock *Group) {
 		{
 			pc := par_cql_MethodHeaderValueNode(
-				headerKey,
 				allPathVersions,
 				mtdDynamicValue,
+				"valueNode",
 			)
 			if len(pc) > 0 {
 				addedCount++
 			}
-			predicateBlock.Add(pc...)
+			predicateBlock.Add(Join(Or(), pc...))
 		}
 	})
 	if addedCount == 0 {
@@ -332,6 +414,139 @@ func predicate_setsDynamicHeaderValue(
 	return predicate
 }
 
+func predicate_setsHeaderMap(
+	allPathVersions []string,
+	mtdHeaderMap *x.XMethod,
+) Code {
+	predicate := Comment("Holds for a call that sets the headers contained in a map passed as parameter.").
+		Private().Predicate().Id(setsHeaderMap).Call(
+		List(
+			String().Id("package"),
+			String().Id("receiverName"),
+			Id("DataFlow::CallNode").Id("setterCall"),
+			Id("DataFlow::Node").Id("headersNode"),
+			Id("DataFlow::Node").Id("receiverNode"),
+		),
+	)
+
+	addedCount := 0
+	predicate.BlockFunc(func(predicateBlock *Group) {
+		{
+			pc := par_cql_MethodHeaderValueNode(
+				allPathVersions,
+				mtdHeaderMap,
+				"headersNode",
+			)
+			if len(pc) > 0 {
+				addedCount++
+			}
+			predicateBlock.Add(Join(Or(), pc...))
+		}
+	})
+	if addedCount == 0 {
+		return nil
+	}
+	return predicate
+}
+
+// par_cql_Functions composes the cases for funcs (without a receiver) selected in b2fe;
+// for each func, gen composes the code that binds the nodes of the call,
+// and returns the indexes of the parameters it binds.
+func par_cql_Functions(
+	pathVersions []string,
+	b2fe x.BasicToFEFuncs,
+	callName string,
+	gen func(fn x.FuncInterface, qual *x.FuncQualifier) (Code, []int),
+) Code {
+	addedCount := 0
+	exists := Exists(
+		List(
+			String().Id("funcName"),
+			Id("Function").Id("fn"),
+		),
+		DoGroup(func(st *Group) {
+			st.Id("fn").Dot("hasQualifiedName").Call(
+				Id("package"),
+				Id("funcName"),
+			)
+			st.And()
+			st.Id(callName).Eq().Id("fn").Dot("getACall").Call()
+			st.And()
+			st.Comment("Funcs have no receiver; the response writer (if any) is one of the other arguments:")
+			st.Id("receiverName").Eq().Lit("")
+		}),
+		DoGroup(func(exists3 *Group) {
+			for _, pathVersion := range pathVersions {
+				cont, ok := b2fe[pathVersion]
+				if !ok {
+					continue
+				}
+
+				tempForPathVersion := make([]Code, 0)
+				for _, funcQual := range cont {
+					if AllFalse(funcQual.Pos...) {
+						continue
+					}
+					fn := x.GetFuncByQualifier(funcQual)
+
+					tempForPathVersion = append(tempForPathVersion,
+						DoGroup(
+							func(par *Group) {
+								par.Commentf("signature: %s", fn.GetFunc().Signature)
+
+								par.Id("funcName").Eq().Lit(fn.GetFunc().Name)
+
+								par.And()
+
+								code, selected := gen(fn, funcQual)
+								par.Add(code)
+
+								par.And()
+
+								par.Add(cql_ResponseWriterArgument(callName, fn, selected))
+							},
+						),
+					)
+				}
+
+				if len(tempForPathVersion) > 0 {
+					if addedCount > 0 {
+						exists3.Or()
+					}
+					addedCount++
+					path, _ := scanner.SplitPathVersion(pathVersion)
+					exists3.Id("package").Eq().Add(x.CqlFormatPackagePath(path))
+					exists3.And()
+					exists3.Parens(
+						Join(Or(), tempForPathVersion...),
+					)
+				}
+			}
+		}),
+	)
+	if addedCount == 0 {
+		return nil
+	}
+	return exists
+}
+
+// cql_ResponseWriterArgument binds the receiverNode of a call to a func (without a receiver)
+// to the arguments that are not selected (i.e. that are not the header key, value, or map).
+func cql_ResponseWriterArgument(callName string, fn x.FuncInterface, selected []int) Code {
+	others := make([]int, 0)
+	for index := range fn.GetFunc().Parameters {
+		if !IntSliceContains(selected, index) {
+			others = append(others, index)
+		}
+	}
+	if len(others) == 0 {
+		// No argument can be the response writer; bind the call itself,
+		// so that no response writer is found:
+		return Id("receiverNode").Eq().Id(callName)
+	}
+	return Id("receiverNode").Eq().Id(callName).Dot("getArgument").Call(IntsToSetOrLit(others...))
+}
+
 func generateCasesForHeaderKeyValWriters(
 	b2Key x.BasicToReceiverIDToMethods,
 	b2Val x.BasicToReceiverIDToMethods,
@@ -426,7 +641,7 @@ func par_cql_DynamicHeaderKeyValue(mdl *x.XModel, pathVersions []string) []Code
 		return nil
 	}
 
-	_, b2tmKey, b2itmKey, err := x.GroupFuncSelectors(methodWriteHeaderKey)
+	b2feKey, b2tmKey, b2itmKey, err := x.GroupFuncSelectors(methodWriteHeaderKey)
 	if err != nil {
 		Fatalf("Error while GroupFuncSelectors: %s", err)
 	}
@@ -437,6 +652,30 @@ func par_cql_DynamicHeaderKeyValue(mdl *x.XModel, pathVersions []string) []Code
 
 	pathCodez := make([]Code, 0)
 
+	// Functions:
+	{
+		exists := par_cql_Functions(pathVersions, b2feKey, "headerSetterCall",
+			func(fn x.FuncInterface, keyQual *x.FuncQualifier) (Code, []int) {
+				valQual := methodWriteHeaderVal.GetFuncSelector(keyQual.Path, keyQual.Version, keyQual.ID)
+				if valQual == nil {
+					Fatalf("Header val func not found: %v", keyQual.BasicQualifier)
+				}
+				_, keyCode := x.CqlParamQualToCode("headerSetterCall", "getArgument", keyQual)
+				_, valCode := x.CqlParamQualToCode("headerSetterCall", "getArgument", valQual)
+				selected := append(
+					x.MustPosToRelativeParamIndexes(fn, keyQual.Pos),
+					x.MustPosToRelativeParamIndexes(fn, valQual.Pos)...,
+				)
+				return Id("headerNameNode").Eq().Add(keyCode).
+					And().
+					Id("headerValueNode").Eq().Add(valCode), selected
+			},
+		)
+		if exists != nil {
+			pathCodez = append(pathCodez, exists)
+		}
+	}
+
 	// Type methods:
 	{
 		addedCount := 0
@@ -540,7 +779,7 @@ func par_cql_MethodStaticValueFromFuncName(
 	headerKey string,
 	pathVersions []string,
 	mtdStaticValueFromFuncName *x.XMethod,
-	guesser func(string) string,
+	guesser func(qual *x.FuncQualifier, funcName string) string,
 ) []Code {
 
 	b2fe, b2tm, b2itm, err := x.GroupFuncSelectors(mtdStaticValueFromFuncName)
@@ -551,11 +790,13 @@ func par_cql_MethodStaticValueFromFuncName(
 	pathCodez := make([]Code, 0)
 	// Functions:
 	{
-		for _, pathVersion := range pathVersions {
-			_, ok := b2fe[pathVersion]
-			if ok {
-				panic("Not implemented")
-			}
+		exists := par_cql_Functions(pathVersions, b2fe, "setterCall",
+			func(fn x.FuncInterface, qual *x.FuncQualifier) (Code, []int) {
+				return Id("valueString").Eq().Lit(guesser(qual, fn.GetFunc().Name)), nil
+			},
+		)
+		if exists != nil {
+			pathCodez = append(pathCodez, exists)
 		}
 	}
 	// Type methods:
@@ -624,7 +865,7 @@ func par_cql_MethodStaticValueFromFuncName(
 											par.And()
 
 											{
-												par.Id("valueString").Eq().Lit(guesser(fn.GetFunc().Name))
+												par.Id("valueString").Eq().Lit(guesser(methodQual, fn.GetFunc().Name))
 											}
 										},
 									)
@@ -719,7 +960,7 @@ func par_cql_MethodStaticValueFromFuncName(
 											par.And()
 
 											{
-												par.Id("valueString").Eq().Lit(guesser(fn.GetFunc().Name))
+												par.Id("valueString").Eq().Lit(guesser(methodQual, fn.GetFunc().Name))
 											}
 										},
 									)
@@ -751,10 +992,12 @@ func par_cql_MethodStaticValueFromFuncName(
 	return pathCodez
 }
 
+// par_cql_MethodHeaderValueNode composes the cases for the calls selected in the provided method,
+// binding the selected parameter to nodeName.
 func par_cql_MethodHeaderValueNode(
-	headerKey string,
 	pathVersions []string,
 	mtdDynamicValue *x.XMethod,
+	nodeName string,
 ) []Code {
 
 	b2fe, b2tm, b2itm, err := x.GroupFuncSelectors(mtdDynamicValue)
@@ -765,11 +1008,14 @@ func par_cql_MethodHeaderValueNode(
 	pathCodez := make([]Code, 0)
 	// Functions:
 	{
-		for _, pathVersion := range pathVersions {
-			_, ok := b2fe[pathVersion]
-			if ok {
-				panic("Not implemented")
-			}
+		exists := par_cql_Functions(pathVersions, b2fe, "setterCall",
+			func(fn x.FuncInterface, qual *x.FuncQualifier) (Code, []int) {
+				_, code := GetHeaderValueSetterFuncQualifierCodeElements(qual)
+				return Id(nodeName).Eq().Add(code), x.MustPosToRelativeParamIndexes(fn, qual.Pos)
+			},
+		)
+		if exists != nil {
+			pathCodez = append(pathCodez, exists)
 		}
 	}
 	// Type methods:
@@ -839,7 +1085,7 @@ func par_cql_MethodHeaderValueNode(
 
 											{
 												_, code := GetHeaderValueSetterFuncQualifierCodeElements(methodQual)
-												par.Id("valueNode").Eq().Add(code)
+												par.Id(nodeName).Eq().Add(code)
 											}
 										},
 									)
@@ -935,7 +1181,7 @@ func par_cql_MethodHeaderValueNode(
 
 											{
 												_, code := GetHeaderValueSetterFuncQualifierCodeElements(methodQual)
-												par.Id("valueNode").Eq().Add(code)
+												par.Id(nodeName).Eq().Add(code)
 											}
 										},
 									)
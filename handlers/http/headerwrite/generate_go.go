@@ -164,9 +164,6 @@ func (han *Handler) GenerateGo(parentDir string, mdl *x.XModel) error {
 	if err := han.Validate(mdl); err != nil {
 		return err
 	}
-	// TODO:
-	// - Validate Pos.
-
 	// Check if there are multiple versions of a same package:
 	mods := mdl.ListModules()
 	if x.HasMultiversion(mods) {
@@ -211,7 +208,16 @@ func (han *Handler) GenerateGo(parentDir string, mdl *x.XModel) error {
 				}
 			}
 			{
-				tmpCodez, err := addTests_ContentType_Static(file, mdl, pathVersion)
+				tmpCodez, err := addTests_ContentType_Static(file, mdl, pathVersion, contentTypeGuesser(mdl))
+				if err != nil {
+					panic(err)
+				}
+				if tmpCodez != nil {
+					codez = append(codez, tmpCodez...)
+				}
+			}
+			{
+				tmpCodez, err := addTests_HeaderMap(file, mdl, pathVersion)
 				if err != nil {
 					panic(err)
 				}
@@ -284,7 +290,7 @@ func addTests_Header_KeyVal_Method(file *File, mdl *x.XModel, pathVersion string
 		return nil, nil
 	}
 
-	_, b2tmKey, b2itmKey, err := x.GroupFuncSelectors(MethodWriteHeaderKey)
+	b2feKey, b2tmKey, b2itmKey, err := x.GroupFuncSelectors(MethodWriteHeaderKey)
 	if err != nil {
 		Fatalf("Error while GroupFuncSelectors: %s", err)
 	}
@@ -292,7 +298,23 @@ func addTests_Header_KeyVal_Method(file *File, mdl *x.XModel, pathVersion string
 	if err != nil {
 		Fatalf("Error while GroupFuncSelectors: %s", err)
 	}
-	// TODO: consider also header writes done with a function?
+
+	codez = append(codez,
+		addTests_Funcs(file, pathVersion, b2feKey, "Header write",
+			func(fn x.FuncInterface, keyFuncQual *x.FuncQualifier) []Code {
+				// TODO:
+				// - Check if found.
+				valFuncQual := MethodWriteHeaderVal.GetFuncSelector(keyFuncQual.Path, keyFuncQual.Version, keyFuncQual.ID)
+
+				return generateGoTestBlock_DynamicHeaderKeyVal(
+					file,
+					fn,
+					keyFuncQual,
+					valFuncQual,
+				)
+			},
+		)...,
+	)
 
 	{
 		codezTypeMethods := make([]Code, 0)
@@ -431,10 +453,21 @@ func addTests_ContentType_Dynamic(file *File, mdl *x.XModel, pathVersion string)
 		return nil, nil
 	}
 	{
-		_, b2tm, b2itm, err := x.GroupFuncSelectors(MethodCt)
+		b2fe, b2tm, b2itm, err := x.GroupFuncSelectors(MethodCt)
 		if err != nil {
 			Fatalf("Error while GroupFuncSelectors: %s", err)
 		}
+
+		codez = append(codez,
+			addTests_Funcs(file, pathVersion, b2fe, "Dynamic Content-Type header",
+				func(fn x.FuncInterface, funcQual *x.FuncQualifier) []Code {
+					return par_go_ContentType_DynamicValue(
+						file,
+						funcQual,
+					)
+				},
+			)...,
+		)
 		{
 			codezTypeMethods := make([]Code, 0)
 			b2tm.IterValid(pathVersion,
@@ -552,7 +585,12 @@ func addTests_ContentType_Dynamic(file *File, mdl *x.XModel, pathVersion string)
 	return codez, nil
 }
 
-func addTests_ContentType_Static(file *File, mdl *x.XModel, pathVersion string) ([]Code, error) {
+func addTests_ContentType_Static(
+	file *File,
+	mdl *x.XModel,
+	pathVersion string,
+	guesser func(qual *x.FuncQualifier, funcName string) string,
+) ([]Code, error) {
 	codez := make([]Code, 0)
 
 	MethodCtFromFuncName := mdl.Methods.ByName(MethodCtFromFuncName)
@@ -561,10 +599,22 @@ func addTests_ContentType_Static(file *File, mdl *x.XModel, pathVersion string)
 		return nil, nil
 	}
 	{
-		_, b2tm, b2itm, err := x.GroupFuncSelectors(MethodCtFromFuncName)
+		b2fe, b2tm, b2itm, err := x.GroupFuncSelectors(MethodCtFromFuncName)
 		if err != nil {
 			Fatalf("Error while GroupFuncSelectors: %s", err)
 		}
+
+		codez = append(codez,
+			addTests_Funcs(file, pathVersion, b2fe, "Static Content-Type header write",
+				func(fn x.FuncInterface, funcQual *x.FuncQualifier) []Code {
+					return par_go_ContentType_StaticValue(
+						file,
+						funcQual,
+						guesser,
+					)
+				},
+			)...,
+		)
 		{
 			codezTypeMethods := make([]Code, 0)
 			b2tm.IterValid(pathVersion,
@@ -596,6 +646,7 @@ func addTests_ContentType_Static(file *File, mdl *x.XModel, pathVersion string)
 									blocksOfCases := par_go_ContentType_StaticValue(
 										file,
 										methodQual,
+										guesser,
 									)
 									if len(blocksOfCases) == 1 {
 										groupCase.Add(blocksOfCases...)
@@ -653,6 +704,7 @@ func addTests_ContentType_Static(file *File, mdl *x.XModel, pathVersion string)
 									blocksOfCases := par_go_ContentType_StaticValue(
 										file,
 										methodQual,
+										guesser,
 									)
 									if len(blocksOfCases) == 1 {
 										groupCase.Add(blocksOfCases...)
@@ -682,6 +734,198 @@ func addTests_ContentType_Static(file *File, mdl *x.XModel, pathVersion string)
 	return codez, nil
 }
 
+func addTests_HeaderMap(file *File, mdl *x.XModel, pathVersion string) ([]Code, error) {
+	codez := make([]Code, 0)
+
+	MethodHeaderMap := mdl.Methods.ByName(MethodHeaderMap)
+	if len(MethodHeaderMap.Selectors) == 0 {
+		Infof("No selectors found for %q method.", MethodHeaderMap.Name)
+		return nil, nil
+	}
+	{
+		b2fe, b2tm, b2itm, err := x.GroupFuncSelectors(MethodHeaderMap)
+		if err != nil {
+			Fatalf("Error while GroupFuncSelectors: %s", err)
+		}
+
+		codez = append(codez,
+			addTests_Funcs(file, pathVersion, b2fe, "Header map",
+				func(fn x.FuncInterface, funcQual *x.FuncQualifier) []Code {
+					return par_go_HeaderMap(
+						file,
+						funcQual,
+					)
+				},
+			)...,
+		)
+
+		{
+			codezTypeMethods := make([]Code, 0)
+			b2tm.IterValid(pathVersion,
+				func(receiverTypeID string, methodQualifiers x.FuncQualifierSlice) {
+
+					qual := methodQualifiers[0]
+					// Find receiver type:
+					typ := x.FindType(qual.Path, qual.Version, receiverTypeID)
+					if typ == nil {
+						Fatalf("Type not found: %q", receiverTypeID)
+					}
+
+					gogentools.ImportPackage(file, typ.PkgPath, typ.PkgName)
+
+					code := BlockFunc(
+						func(groupCase *Group) {
+
+							for _, methodQual := range methodQualifiers {
+								fn := x.GetFuncByQualifier(methodQual)
+								thing := fn.(*feparser.FETypeMethod)
+								x.AddImportsFromFunc(file, fn)
+
+								{
+									if AllFalse(methodQual.Pos...) {
+										continue
+									}
+									groupCase.Comment(thing.Func.Signature)
+
+									blocksOfCases := par_go_HeaderMap(
+										file,
+										methodQual,
+									)
+									if len(blocksOfCases) == 1 {
+										groupCase.Add(blocksOfCases...)
+									} else {
+										groupCase.Block(blocksOfCases...)
+									}
+								}
+
+							}
+						})
+					codezTypeMethods = append(codezTypeMethods,
+						Commentf("Header map via method calls on %s.", typ.QualifiedName).
+							Line().
+							Add(code),
+					)
+				})
+			if len(codezTypeMethods) > 0 {
+				codez = append(codez,
+					Comment("Header map via method calls.").
+						Line().
+						Block(codezTypeMethods...),
+				)
+			}
+		}
+
+		{
+			codezIfaceMethods := make([]Code, 0)
+			b2itm.IterValid(pathVersion,
+				func(receiverTypeID string, methodQualifiers x.FuncQualifierSlice) {
+
+					qual := methodQualifiers[0]
+					// Find receiver type:
+					typ := x.FindType(qual.Path, qual.Version, receiverTypeID)
+					if typ == nil {
+						Fatalf("Type not found: %q", receiverTypeID)
+					}
+
+					gogentools.ImportPackage(file, typ.PkgPath, typ.PkgName)
+
+					code := BlockFunc(
+						func(groupCase *Group) {
+
+							for _, methodQual := range methodQualifiers {
+								fn := x.GetFuncByQualifier(methodQual)
+								thing := fn.(*feparser.FEInterfaceMethod)
+								x.AddImportsFromFunc(file, fn)
+
+								{
+									if AllFalse(methodQual.Pos...) {
+										continue
+									}
+									groupCase.Comment(thing.Func.Signature)
+
+									blocksOfCases := par_go_HeaderMap(
+										file,
+										methodQual,
+									)
+									if len(blocksOfCases) == 1 {
+										groupCase.Add(blocksOfCases...)
+									} else {
+										groupCase.Block(blocksOfCases...)
+									}
+								}
+							}
+						})
+					codezIfaceMethods = append(codezIfaceMethods,
+						Commentf("Header map via method calls on %s interface.", typ.QualifiedName).
+							Line().
+							Add(code),
+					)
+				})
+
+			if len(codezIfaceMethods) > 0 {
+				codez = append(codez,
+					Comment("Header map via interface method calls.").
+						Line().
+						Block(codezIfaceMethods...),
+				)
+			}
+		}
+	}
+
+	return codez, nil
+}
+
+// addTests_Funcs generates the tests for the funcs (without a receiver) of the pathVersion,
+// using gen to generate the test blocks of each func.
+func addTests_Funcs(
+	file *File,
+	pathVersion string,
+	b2fe x.BasicToFEFuncs,
+	what string,
+	gen func(fn x.FuncInterface, funcQual *x.FuncQualifier) []Code,
+) []Code {
+	codez := make([]Code, 0)
+
+	cont, ok := b2fe[pathVersion]
+	if ok && x.HasValidPos(cont...) {
+		addedCount := 0
+		code := BlockFunc(
+			func(groupCase *Group) {
+
+				for _, funcQual := range cont {
+					fn := x.GetFuncByQualifier(funcQual)
+					thing := fn.(*feparser.FEFunc)
+
+					x.AddImportsFromFunc(file, thing)
+
+					{
+						if AllFalse(funcQual.Pos...) {
+							continue
+						}
+						groupCase.Comment(thing.Signature)
+
+						blocksOfCases := gen(thing, funcQual)
+						if len(blocksOfCases) == 1 {
+							groupCase.Add(blocksOfCases...)
+						} else {
+							groupCase.Block(blocksOfCases...)
+						}
+						addedCount++
+					}
+
+				}
+			})
+		if addedCount > 0 {
+			codez = append(codez,
+				Commentf("%s via function calls.", what).
+					Line().
+					Add(code),
+			)
+		}
+	}
+	return codez
+}
+
 // Comments adds comments to a Group (if enabled), and returns the group.
 func Comments(group *Group, comments ...string) *Group {
 	if IncludeCommentsInGeneratedGo {
@@ -698,7 +942,7 @@ func newStatement() *Statement {
 
 func generateGoTestBlock_DynamicHeaderKeyVal(
 	file *File,
-	fe *feparser.FETypeMethod,
+	fe x.FuncInterface,
 	qualHeaderKey *x.FuncQualifier,
 	qualHeaderVal *x.FuncQualifier,
 ) []Code {
@@ -713,7 +957,7 @@ func generateGoTestBlock_DynamicHeaderKeyVal(
 		Fatalf("headerValIndexes len is not 1: %v", qualHeaderVal)
 	}
 
-	childBlock := generate_Method(
+	childBlock := generate_KeyVal(
 		file,
 		fe,
 		headerKeyIndexes[0],
@@ -732,12 +976,12 @@ func generateGoTestBlock_DynamicHeaderKeyVal(
 
 ////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
 
-func generate_Method(file *File, fe *feparser.FETypeMethod, indexKey int, indexVal int) *Statement {
+func generate_KeyVal(file *File, fe x.FuncInterface, indexKey int, indexVal int) *Statement {
 
-	keyParam := fe.Func.Parameters[indexKey]
+	keyParam := fe.GetFunc().Parameters[indexKey]
 	keyParam.VarName = gogentools.NewNameWithPrefix(feparser.NewLowerTitleName("key", keyParam.TypeName))
 
-	valParam := fe.Func.Parameters[indexVal]
+	valParam := fe.GetFunc().Parameters[indexVal]
 	valParam.VarName = gogentools.NewNameWithPrefix(feparser.NewLowerTitleName("val", valParam.TypeName))
 
 	code := BlockFunc(
@@ -746,32 +990,46 @@ func generate_Method(file *File, fe *feparser.FETypeMethod, indexKey int, indexV
 			ComposeTypeAssertion(file, groupCase, keyParam.VarName, keyParam.GetOriginal().GetType(), keyParam.GetOriginal().IsVariadic())
 			ComposeTypeAssertion(file, groupCase, valParam.VarName, valParam.GetOriginal().GetType(), valParam.GetOriginal().IsVariadic())
 
-			Comments(groupCase, "Declare medium object/interface:")
-			groupCase.Var().Id("rece").Qual(fe.Receiver.PkgPath, fe.Receiver.TypeName)
+			gogentools.ImportPackage(file, fe.GetFunc().PkgPath, fe.GetFunc().PkgName)
 
-			gogentools.ImportPackage(file, fe.Func.PkgPath, fe.Func.PkgName)
+			composeCall(file, groupCase, fe, []int{indexKey, indexVal}).
+				Add(TagDynamicHeader(keyParam.VarName, valParam.VarName))
 
-			groupCase.Id("rece").Dot(fe.Func.Name).CallFunc(
-				func(call *Group) {
+		})
+	return code
+}
 
-					tpFun := fe.Func.GetOriginal().GetType().(*types.Signature)
+// composeCall adds to the group the call to the func (declaring the receiver, if any),
+// passing the variables of the parameters at the provided indexes, and zero values for all the others.
+func composeCall(file *File, group *Group, fe x.FuncInterface, indexes []int) *Statement {
+	var callCode *Statement
+	if fe.GetReceiver() != nil {
+		Comments(group, "Declare medium object/interface:")
+		group.Var().Id("rece").Qual(fe.GetReceiver().PkgPath, fe.GetReceiver().TypeName)
 
-					zeroVals := gogentools.ScanTupleOfZeroValues(file, tpFun.Params(), fe.Func.GetOriginal().IsVariadic())
+		callCode = group.Id("rece").Dot(fe.GetFunc().Name)
+	} else {
+		callCode = group.Qual(fe.GetFunc().PkgPath, fe.GetFunc().Name)
+	}
 
-					for i, zero := range zeroVals {
-						isConsidered := IntSliceContains([]int{indexKey, indexVal}, i)
-						if isConsidered {
-							call.Id(fe.Func.Parameters[i].VarName)
-						} else {
-							call.Add(zero)
-						}
-					}
+	return callCode.CallFunc(
+		func(call *Group) {
 
-				},
-			).Add(TagDynamicHeader(keyParam.VarName, valParam.VarName))
+			tpFun := fe.GetFunc().GetOriginal().GetType().(*types.Signature)
 
-		})
-	return code
+			zeroVals := gogentools.ScanTupleOfZeroValues(file, tpFun.Params(), fe.GetFunc().GetOriginal().IsVariadic())
+
+			for i, zero := range zeroVals {
+				isConsidered := IntSliceContains(indexes, i)
+				if isConsidered {
+					call.Id(fe.GetFunc().Parameters[i].VarName)
+				} else {
+					call.Add(zero)
+				}
+			}
+
+		},
+	)
 }
 
 ////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
@@ -884,6 +1142,7 @@ func goChildBlock_ContentType_DynamicValue(
 func par_go_ContentType_StaticValue(
 	file *File,
 	ctQual *x.FuncQualifier,
+	guesser func(qual *x.FuncQualifier, funcName string) string,
 ) []Code {
 
 	childBlocks := make([]Code, 0)
@@ -894,6 +1153,7 @@ func par_go_ContentType_StaticValue(
 	childBlock := goChildBlock_ContentType_StaticValue(
 		file,
 		ctFn,
+		guesser(ctQual, ctFn.GetFunc().Name),
 	)
 	{
 		if childBlock != nil {
@@ -909,6 +1169,7 @@ func par_go_ContentType_StaticValue(
 func goChildBlock_ContentType_StaticValue(
 	file *File,
 	ctFn x.FuncInterface,
+	contentType string,
 ) *Statement {
 
 	ctFnHasReceiver := ctFn.GetReceiver() != nil
@@ -931,13 +1192,81 @@ func goChildBlock_ContentType_StaticValue(
 					afterCt = groupCase.Qual(ctFn.GetFunc().PkgPath, ctFn.GetFunc().Name)
 				}
 				afterCt.Call().
-					Add(TagStaticContentType("content-type", x.GuessContentTypeFromName(ctFn.GetFunc().Name)))
+					Add(TagStaticContentType("content-type", contentType))
 			}
 
 		})
 	return code
 }
 
+func par_go_HeaderMap(
+	file *File,
+	headersQual *x.FuncQualifier,
+) []Code {
+
+	childBlocks := make([]Code, 0)
+
+	headersFn := x.GetFuncByQualifier(headersQual)
+	headersIndexes := x.MustPosToRelativeParamIndexes(headersFn, headersQual.Pos)
+	if len(headersIndexes) != 1 {
+		Fatalf("headersIndexes len is not 1: %v", headersQual)
+	}
+
+	childBlock := goChildBlock_HeaderMap(
+		file,
+		headersFn,
+		headersIndexes[0],
+	)
+	{
+		if childBlock != nil {
+			childBlocks = append(childBlocks, childBlock)
+		} else {
+			Warnf(Sf("NOTHING GENERATED; headersQual %v", headersQual))
+		}
+	}
+
+	return childBlocks
+}
+
+// goChildBlock_HeaderMap generates a test where a header (with tainted key and value)
+// is added to a map, which is then passed to the func.
+// Only index writes are tested, because entries added with
+// the methods of `http.Header` (e.g. `Set`) are not modeled.
+func goChildBlock_HeaderMap(
+	file *File,
+	headersFn x.FuncInterface,
+	headersIndex int,
+) *Statement {
+
+	headersParam := headersFn.GetFunc().Parameters[headersIndex]
+	mapType, ok := headersParam.GetOriginal().GetType().Underlying().(*types.Map)
+	if !ok {
+		return nil
+	}
+	headersParam.VarName = gogentools.NewNameWithPrefix(feparser.NewLowerTitleName("headers", headersParam.TypeName))
+
+	keyVarName := gogentools.NewNameWithPrefix("key")
+	valVarName := gogentools.NewNameWithPrefix("val")
+
+	code := BlockFunc(
+		func(groupCase *Group) {
+
+			gogentools.ImportPackage(file, headersFn.GetFunc().PkgPath, headersFn.GetFunc().PkgName)
+
+			ComposeTypeAssertion(file, groupCase, keyVarName, mapType.Key(), false)
+			ComposeTypeAssertion(file, groupCase, valVarName, mapType.Elem(), false)
+
+			typ := getTypeContent(file, groupCase, headersParam.VarName, headersParam.GetOriginal().GetType(), headersParam.GetOriginal().IsVariadic())
+			groupCase.Id(headersParam.VarName).Op(":=").Add(typ).Values()
+			Comments(groupCase, "NOTE: only index writes are modeled (e.g. not `http.Header.Set`):")
+			groupCase.Id(headersParam.VarName).Index(Id(keyVarName)).Op("=").Id(valVarName).
+				Add(TagDynamicHeader(keyVarName, valVarName))
+
+			composeCall(file, groupCase, headersFn, []int{headersIndex})
+		})
+	return code
+}
+
 // TODO: verify
 func getTypeContent(file *File, group *Group, varName string, typ types.Type, isVariadic bool) *Statement {
 	assertContent := newStatement()
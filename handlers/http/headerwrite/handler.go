@@ -2,14 +2,22 @@ package headerwrite
 
 import (
 	"fmt"
+	"go/types"
 
 	"github.com/gagliardetto/codemill/x"
 )
 
 // NOTE:
-// - The method (on type or interface) must write both key and value.
-// - Functions without a receiver are not supported.
+// - The func (or method on type or interface) must write both key and value.
+// - For funcs without a receiver, the response writer (if any) is assumed to be one of the non-selected arguments.
+// - The headers map parameter must be a map (e.g. `map[string]string`, `http.Header`);
+//   each entry written to the map (i.e. `headers[key] = val`) is considered a header write.
+//   Entries added with the methods of `http.Header` (e.g. `headers.Set(key, val)`) are not modeled.
+// - The content-type of MethodCtFromFuncName funcs is the one set on the selector,
+//   or the one inferred from the func name with the content-type rules of the spec (see x.InferContentType).
 // - One method per model.
+// - Models saved with the previous 4 methods are migrated on load (see x.XModel.Migrate),
+//   which adds the (empty) MethodHeaderMap method.
 
 const (
 	Kind x.ModelKind = "HTTP::HeaderWrite"
@@ -17,6 +25,15 @@ const (
 
 type Handler struct{}
 
+// contentTypeGuesser returns a func that infers the content-type of the func selected by a qualifier
+// with the content-type rules of the spec of the model (see x.InferContentType).
+func contentTypeGuesser(mdl *x.XModel) func(qual *x.FuncQualifier, funcName string) string {
+	rules := mdl.ContentTypeRules()
+	return func(qual *x.FuncQualifier, funcName string) string {
+		return x.InferContentType(rules, qual, funcName)
+	}
+}
+
 const (
 	MethodWriteHeaderKey = "{key:Param, val:Param} <- $key"
 	MethodWriteHeaderVal = "{key:Param, val:Param} <- $val"
@@ -24,6 +41,8 @@ const (
 	MethodCt = "{ct:Param} <- $ct" // Content-type parameter; the function only allows to specify content-type.
 
 	MethodCtFromFuncName = "{ct:Inferred} <- *" // Content-type inferred from the function name.
+
+	MethodHeaderMap = "{headers:Param} <- $headers" // Headers map parameter; each entry of the map is a header.
 )
 
 //
@@ -35,6 +54,8 @@ func (han *Handler) ScavengeMethods() []*x.XMethod {
 		MethodCt, // "Select content-type param of any function that allows to set the content-type but does NOT set the body.",
 
 		MethodCtFromFuncName, // "Select any function that sets the content-type independently of params; content-type will be inferred from the func name.",
+
+		MethodHeaderMap, // "Select the parameter of any function that accepts a whole map of headers (e.g. `http.Header`).",
 	)
 }
 func (han *Handler) Validate(mdl *x.XModel) error {
@@ -43,14 +64,60 @@ func (han *Handler) Validate(mdl *x.XModel) error {
 		return fmt.Errorf("wrong number of methods; expected %v, got %v", defaultMthNum, len(mdl.Methods))
 	}
 	{
-		if mdl.Methods[0].Name != MethodWriteHeaderKey {
-			return fmt.Errorf("#0 method is not called %s", MethodWriteHeaderKey)
+		for i, must := range han.ScavengeMethods() {
+			if mdl.Methods[i].Name != must.Name {
+				return fmt.Errorf("#%v method is not called %s", i, must.Name)
+			}
 		}
-		if mdl.Methods[1].Name != MethodWriteHeaderVal {
-			return fmt.Errorf("#1 method is not called %s", MethodWriteHeaderVal)
+	}
+	mtdKey := mdl.Methods.ByName(MethodWriteHeaderKey)
+	mtdVal := mdl.Methods.ByName(MethodWriteHeaderVal)
+	{
+		// The key, the value, the content-type, and the headers map must be exactly one parameter:
+		for _, mtd := range []*x.XMethod{mtdKey, mtdVal, mdl.Methods.ByName(MethodCt), mdl.Methods.ByName(MethodHeaderMap)} {
+			if err := x.ValidateParams(mtd, true); err != nil {
+				return err
+			}
+		}
+	}
+	{
+		// Each key selector must have a corresponding value selector (and vice versa),
+		// and the key and the value must be different parameters:
+		if err := x.ValidateDistinctParams(mtdKey, mtdVal); err != nil {
+			return err
+		}
+		if err := x.ValidateCoupled(mtdVal, mtdKey); err != nil {
+			return err
+		}
+	}
+	{
+		// The content-type must be inferable for each func:
+		mtdCtFromFuncName := mdl.Methods.ByName(MethodCtFromFuncName)
+		for _, sel := range mtdCtFromFuncName.Selectors {
+			qual := sel.GetFuncQualifier()
+			if qual == nil {
+				return fmt.Errorf("method %s supports only func selectors", mtdCtFromFuncName.Name)
+			}
+			fn := x.GetFuncByQualifier(qual)
+			if x.InferContentType(mdl.ContentTypeRules(), qual, fn.GetFunc().Name) == "" {
+				return fmt.Errorf(
+					"%s: cannot infer the content-type from the func name; add a content-type rule to the spec, or set the content-type of the selector",
+					qual.ID,
+				)
+			}
+		}
+	}
+	{
+		mtdHeaderMap := mdl.Methods.ByName(MethodHeaderMap)
+		for _, sel := range mtdHeaderMap.Selectors {
+			qual := sel.GetFuncQualifier()
+			fn := x.GetFuncByQualifier(qual)
+			parameterIndexes := x.MustPosToRelativeParamIndexes(fn, qual.Pos)
+			param := fn.GetFunc().Parameters[parameterIndexes[0]]
+			if _, ok := param.GetOriginal().GetType().Underlying().(*types.Map); !ok {
+				return fmt.Errorf("%s: the headers parameter must be a map, got %s", qual.ID, param.GetOriginal().TypeString())
+			}
 		}
 	}
-	// TODO:
-	// - Make sure that each selector has a key and a value method.
 	return nil
 }
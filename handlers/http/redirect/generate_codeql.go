@@ -1,6 +1,8 @@
 package redirect
 
 import (
+	"sort"
+
 	"github.com/gagliardetto/codebox/scanner"
 	"github.com/gagliardetto/codemill/x"
 	. "github.com/gagliardetto/cqlgen/jen"
@@ -17,10 +19,12 @@ func (han *Handler) GenerateCodeQL(impAdder x.ImportAdder, mdl *x.XModel, rootMo
 	}
 
 	// Assuming the validation has already been done:
-	methodGetURL := mdl.Methods[0]
+	mtdGetURL := mdl.Methods.ByName(MethodGetURL)
+	mtdStatusCode := mdl.Methods.ByName(MethodStatusCode)
+	mtdURLFromField := mdl.Methods.ByName(MethodURLFromField)
 
-	if len(methodGetURL.Selectors) == 0 {
-		Infof("No selectors found for %q method.", methodGetURL.Name)
+	if len(mtdGetURL.Selectors) == 0 && len(mtdURLFromField.Selectors) == 0 {
+		Infof("No selectors found for %q and %q methods.", mtdGetURL.Name, mtdURLFromField.Name)
 		return nil
 	}
 
@@ -32,13 +36,37 @@ func (han *Handler) GenerateCodeQL(impAdder x.ImportAdder, mdl *x.XModel, rootMo
 	className := mdl.Name
 	allPathVersions := mdl.ListAllPathVersions()
 
-	b2fe, b2tm, b2itm, err := x.GroupFuncSelectors(methodGetURL)
-	if err != nil {
-		Fatalf("Error while GroupFuncSelectors: %s", err)
-	}
-	{
-		addedCount := 0
+	if len(mtdGetURL.Selectors) > 0 {
 		funcModelsClassName := feparser.NewCodeQlName(className)
+
+		callsCode, addedCount := x.CqlCallTargets(allPathVersions, mtdGetURL, "this", "HTTP redirect models",
+			func(fn x.FuncInterface, urlQual *x.FuncQualifier) Code {
+				return DoGroup(func(gr *Group) {
+					_, urlCode := x.CqlParamQualToCode("this", "getArgument", urlQual)
+					gr.Id("urlNode").Eq().Add(urlCode)
+
+					statusQual := mtdStatusCode.GetFuncSelector(urlQual.Path, urlQual.Version, urlQual.ID)
+					if statusQual != nil {
+						_, statusCode := x.CqlParamQualToCode("this", "getArgument", statusQual)
+						gr.And()
+						gr.Comment("The status code (when statically known) is a redirect status code:")
+						gr.Op("not")
+						gr.Exists(
+							List(
+								Id("int").Id("code"),
+							),
+							DoGroup(func(ex *Group) {
+								ex.Id("code").Eq().Add(statusCode).Dot("getIntValue").Call()
+							}),
+							DoGroup(func(ex *Group) {
+								ex.Id("code").Op("<").Lit(300).Or().Id("code").Op(">").Lit(399)
+							}),
+						)
+					}
+				})
+			},
+		)
+
 		tmp := DoGroup(func(tempFuncsModel *Group) {
 			tempFuncsModel.Doc("Models HTTP redirects.")
 			tempFuncsModel.Private().Class().Id(funcModelsClassName).Extends().List(
@@ -49,202 +77,9 @@ func (han *Handler) GenerateCodeQL(impAdder x.ImportAdder, mdl *x.XModel, rootMo
 					funcModelsClassGroup.String().Id("package").Semicolon().Line()
 					funcModelsClassGroup.Id("DataFlow::Node").Id("urlNode").Semicolon().Line()
 
-					funcModelsClassGroup.Id(funcModelsClassName).Call().BlockFunc(
-						func(funcModelsSelfMethodGroup *Group) {
-							{
-								funcModelsSelfMethodGroup.DoGroup(
-									func(groupCase *Group) {
-										for _, pathVersion := range allPathVersions {
-											pathCodez := make([]Code, 0)
-											// Functions:
-											{
-												cont, ok := b2fe[pathVersion]
-												if ok {
-													for _, funcQual := range cont {
-														if AllFalse(funcQual.Pos...) {
-															continue
-														}
-														fn := x.GetFuncByQualifier(funcQual)
-														thing := fn.(*feparser.FEFunc)
-														pathCodez = append(pathCodez,
-															ParensFunc(
-																func(par *Group) {
-																	par.Commentf("signature: %s", thing.Signature)
-																	par.This().
-																		Dot("getTarget").Call().
-																		Dot("hasQualifiedName").Call(
-																		Id("package"),
-																		Lit(thing.Name),
-																	)
-
-																	par.And()
-
-																	_, code := GetFuncQualifierCodeElements(funcQual)
-																	par.Id("urlNode").Eq().Add(code)
-																},
-															),
-														)
-													}
-
-												}
-											}
-											// Type methods:
-											{
-												b2tm.IterValid(pathVersion,
-													func(receiverTypeID string, methodQualifiers x.FuncQualifierSlice) {
-														codez := DoGroup(func(mtdGroup *Group) {
-															qual := methodQualifiers[0]
-															source := x.GetCachedSource(qual.Path, qual.Version)
-															if source == nil {
-																Fatalf("Source not found: %s@%s", qual.Path, qual.Version)
-															}
-															// Find receiver type:
-															typ := x.FindTypeByID(source, receiverTypeID)
-															if typ == nil {
-																Fatalf("Type not found: %q", receiverTypeID)
-															}
-
-															mtdGroup.Commentf("Receiver type: %s", typ.TypeString)
-
-															methodIndex := 0
-															mtdGroup.ParensFunc(
-																func(parMethods *Group) {
-																	for _, methodQual := range methodQualifiers {
-																		if AllFalse(methodQual.Pos...) {
-																			continue
-																		}
-																		if methodIndex > 0 {
-																			parMethods.Or()
-																		}
-																		methodIndex++
-
-																		fn := x.GetFuncByQualifier(methodQual)
-																		thing := fn.(*feparser.FETypeMethod)
-
-																		parMethods.ParensFunc(
-																			func(par *Group) {
-																				par.Commentf("signature: %s", thing.Func.Signature)
-
-																				par.This().
-																					Eq().
-																					Any(
-																						DoGroup(func(gr *Group) {
-																							gr.Id("Method").Id("m")
-																						}),
-																						DoGroup(func(gr *Group) {
-																							gr.Id("m").Dot("hasQualifiedName").Call(
-																								Id("package"),
-																								Lit(thing.Receiver.TypeName),
-																								Lit(thing.Func.Name),
-																							)
-																						}),
-																						nil,
-																					).Dot("getACall").Call()
-
-																				par.And()
-
-																				_, code := GetFuncQualifierCodeElements(methodQual)
-																				par.Id("urlNode").Eq().Add(code)
-																			},
-																		)
-
-																	}
-																},
-															)
-
-														})
-														pathCodez = append(pathCodez, codez)
-													})
-											}
-											// Interface methods:
-											{
-												b2itm.IterValid(pathVersion,
-													func(receiverTypeID string, methodQualifiers x.FuncQualifierSlice) {
-														codez := DoGroup(func(mtdGroup *Group) {
-															qual := methodQualifiers[0]
-															source := x.GetCachedSource(qual.Path, qual.Version)
-															if source == nil {
-																Fatalf("Source not found: %s@%s", qual.Path, qual.Version)
-															}
-															// Find receiver type:
-															typ := x.FindTypeByID(source, receiverTypeID)
-															if typ == nil {
-																Fatalf("Type not found: %q", receiverTypeID)
-															}
-															mtdGroup.Commentf("Receiver interface: %s", typ.TypeString)
-
-															methodIndex := 0
-															mtdGroup.ParensFunc(
-																func(parMethods *Group) {
-																	for _, methodQual := range methodQualifiers {
-																		if AllFalse(methodQual.Pos...) {
-																			continue
-																		}
-																		if methodIndex > 0 {
-																			parMethods.Or()
-																		}
-																		methodIndex++
-
-																		fn := x.GetFuncByQualifier(methodQual)
-																		thing := fn.(*feparser.FEInterfaceMethod)
-
-																		parMethods.ParensFunc(
-																			func(par *Group) {
-																				par.Commentf("signature: %s", thing.Func.Signature)
-
-																				par.This().
-																					Eq().
-																					Any(
-																						DoGroup(func(gr *Group) {
-																							gr.Id("Method").Id("m")
-																						}),
-																						DoGroup(func(gr *Group) {
-																							gr.Id("m").Dot("implements").Call(
-																								Id("package"),
-																								Lit(thing.Receiver.TypeName),
-																								Lit(thing.Func.Name),
-																							)
-																						}),
-																						nil,
-																					).Dot("getACall").Call()
-
-																				par.And()
-
-																				_, code := GetFuncQualifierCodeElements(methodQual)
-																				par.Id("urlNode").Eq().Add(code)
-																			},
-																		)
-
-																	}
-																},
-															)
-
-														})
-														pathCodez = append(pathCodez, codez)
-													})
-											}
-
-											if len(pathCodez) > 0 {
-												if addedCount > 0 {
-													groupCase.Or()
-												}
-												path, _ := scanner.SplitPathVersion(pathVersion)
-												groupCase.Commentf("HTTP redirect models for package: %s", pathVersion)
-												groupCase.Id("package").Eq().Add(x.CqlFormatPackagePath(path)).And()
-
-												groupCase.Parens(
-													Join(
-														Or(),
-														pathCodez...,
-													),
-												)
-
-												addedCount++
-											}
-										}
-									})
-							}
-						})
+					funcModelsClassGroup.Id(funcModelsClassName).Call().Block(
+						callsCode,
+					)
 
 					funcModelsClassGroup.Override().Id("DataFlow::Node").Id("getUrl").Call().BlockFunc(
 						func(overrideBlockGroup *Group) {
@@ -258,14 +93,113 @@ func (han *Handler) GenerateCodeQL(impAdder x.ImportAdder, mdl *x.XModel, rootMo
 				})
 		})
 		if addedCount > 0 {
-
 			rootModuleGroup.Add(tmp)
 		}
 	}
 
-	return nil
-}
+	if len(mtdURLFromField.Selectors) > 0 {
+		fieldModelsClassName := feparser.NewCodeQlName(className, "FromField")
+
+		b2st, err := x.GroupStructSelectors(mtdURLFromField)
+		if err != nil {
+			Fatalf("Error while GroupStructSelectors: %s", err)
+		}
 
-func GetFuncQualifierCodeElements(qual *x.FuncQualifier) (x.FuncInterface, Code) {
-	return x.CqlParamQualToCode("this", "getArgument", qual)
+		rootModuleGroup.Doc("Models HTTP redirects where the URL is written to a struct field.")
+		rootModuleGroup.Private().Class().Id(fieldModelsClassName).Extends().List(
+			Id("HTTP::Redirect::Range"),
+		).BlockFunc(
+			func(fieldModelsClassGroup *Group) {
+				fieldModelsClassGroup.Id("DataFlow::Node").Id("urlNode").Semicolon().Line()
+
+				fieldModelsClassGroup.Id(fieldModelsClassName).Call().BlockFunc(func(metGr *Group) {
+					metGr.Exists(
+						List(
+							Qual("DataFlow", "Field").Id("fld"),
+							Qual("DataFlow", "Write").Id("w"),
+						),
+						DoGroup(func(st *Group) {
+							st.Id("w").Dot("writesField").Call(This(), Id("fld"), Id("urlNode"))
+						}),
+						DoGroup(func(st *Group) {
+							st.ParensFunc(func(par *Group) {
+								index := 0
+								keys := func(v x.BasicToStructIDToFields) []string {
+									res := make([]string, 0)
+									for key := range v {
+										res = append(res, key)
+									}
+									sort.Strings(res)
+									return res
+								}(b2st)
+								for _, pathVersion := range keys {
+									structQualifiers, ok := b2st[pathVersion]
+									if !ok {
+										continue
+									}
+									if index > 0 {
+										par.Or()
+									}
+									index++
+									path, _ := scanner.SplitPathVersion(pathVersion)
+
+									par.Comment("Structs of package: " + pathVersion)
+									par.Exists(
+										List(
+											String().Id("structName"),
+											String().Id("fields"),
+										),
+										DoGroup(func(ex *Group) {
+											ex.Id("fld").Dot("hasQualifiedName").Call(
+												x.CqlFormatPackagePath(path),
+												Id("structName"),
+												Id("fields"),
+											)
+										}),
+										DoGroup(func(ex *Group) {
+											for qualIndex, qual := range structQualifiers {
+												if qualIndex > 0 {
+													ex.Or()
+												}
+												source := x.GetCachedSource(qual.Path, qual.Version)
+												if source == nil {
+													Fatalf("Source not found: %s@%s", qual.Path, qual.Version)
+												}
+												// Make sure that the struct exist:
+												str := x.FindStructByID(source, qual.ID)
+												if str == nil {
+													Fatalf("Struct not found: %q", qual.ID)
+												}
+
+												fieldNames := make([]string, 0)
+												for fieldName := range qual.Fields {
+													fieldNames = append(fieldNames, fieldName)
+												}
+												sort.Strings(fieldNames)
+
+												ex.Id("structName").Eq().Lit(str.TypeName)
+												ex.And()
+												ex.Id("fields").Eq().Add(StringsToSetOrLit(fieldNames...))
+											}
+										}),
+									)
+								}
+							})
+						}),
+					)
+				})
+
+				fieldModelsClassGroup.Override().Id("DataFlow::Node").Id("getUrl").Call().BlockFunc(
+					func(overrideBlockGroup *Group) {
+						overrideBlockGroup.Id("result").Eq().Id("urlNode")
+					})
+
+				fieldModelsClassGroup.Override().Id("HTTP::ResponseWriter").Id("getResponseWriter").Call().BlockFunc(
+					func(overrideBlockGroup *Group) {
+						overrideBlockGroup.Id("none").Call()
+					})
+			})
+	}
+
+	return nil
 }
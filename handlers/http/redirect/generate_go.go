@@ -4,6 +4,7 @@ import (
 	"go/types"
 	"os"
 	"path/filepath"
+	"sort"
 
 	. "github.com/dave/jennifer/jen"
 	"github.com/gagliardetto/codebox/gogentools"
@@ -91,9 +92,6 @@ func (han *Handler) GenerateGo(parentDir string, mdl *x.XModel) error {
 	if err := han.Validate(mdl); err != nil {
 		return err
 	}
-	// TODO:
-	// - Validate Pos.
-
 	// Check if there are multiple versions of a same package:
 	mods := mdl.ListModules()
 	if x.HasMultiversion(mods) {
@@ -108,10 +106,12 @@ func (han *Handler) GenerateGo(parentDir string, mdl *x.XModel) error {
 	MustCreateFolderIfNotExists(outDir, os.ModePerm)
 
 	// Assuming the validation has already been done:
-	methodGetURL := mdl.Methods[0]
+	mtdGetURL := mdl.Methods.ByName(MethodGetURL)
+	mtdStatusCode := mdl.Methods.ByName(MethodStatusCode)
+	mtdURLFromField := mdl.Methods.ByName(MethodURLFromField)
 
-	if len(methodGetURL.Selectors) == 0 {
-		Infof("No selectors found for %q method.", methodGetURL.Name)
+	if len(mtdGetURL.Selectors) == 0 && len(mtdURLFromField.Selectors) == 0 {
+		Infof("No selectors found for %q and %q methods.", mtdGetURL.Name, mtdURLFromField.Name)
 		return nil
 	}
 
@@ -126,165 +126,38 @@ func (han *Handler) GenerateGo(parentDir string, mdl *x.XModel) error {
 		}
 		codez := make([]Code, 0)
 
-		b2fe, b2tm, b2itm, err := x.GroupFuncSelectors(methodGetURL)
+		b2fe, b2tm, b2itm, err := x.GroupFuncSelectors(mtdGetURL)
 		if err != nil {
 			Fatalf("Error while GroupFuncSelectors: %s", err)
 		}
 
+		codez = append(codez,
+			generateGoTestCodez(file, pathVersion, "Redirect", b2fe, b2tm, b2itm,
+				func(file *File, fn x.FuncInterface, urlQual *x.FuncQualifier, receiverTypeID string) []Code {
+					statusQual := mtdStatusCode.GetFuncSelector(urlQual.Path, urlQual.Version, urlQual.ID)
+					return generateGoTestBlock(file, fn, urlQual, statusQual)
+				},
+			)...,
+		)
+
+		b2st, err := x.GroupStructSelectors(mtdURLFromField)
+		if err != nil {
+			Fatalf("Error while GroupStructSelectors: %s", err)
+		}
+
 		{
-			cont, ok := b2fe[pathVersion]
-			if ok && x.HasValidPos(cont...) {
-				addedCount := 0
+			structQualifiers, ok := b2st[pathVersion]
+			if ok {
 				code := BlockFunc(
 					func(groupCase *Group) {
-
-						for _, funcQual := range cont {
-							fn := x.GetFuncByQualifier(funcQual)
-							thing := fn.(*feparser.FEFunc)
-
-							x.AddImportsFromFunc(file, thing)
-
-							{
-								if AllFalse(funcQual.Pos...) {
-									continue
-								}
-								groupCase.Comment(thing.Signature)
-
-								blocksOfCases := generateGoTestBlock_Func(
-									file,
-									thing,
-									funcQual,
-								)
-								if len(blocksOfCases) == 1 {
-									groupCase.Add(blocksOfCases...)
-								} else {
-									groupCase.Block(blocksOfCases...)
-								}
-								addedCount++
-							}
-
+						for _, qual := range structQualifiers {
+							groupCase.Add(generate_StructField(file, qual))
 						}
 					})
-				if addedCount > 0 {
-					codez = append(codez,
-						Comment("Redirect via function call.").
-							Line().
-							Add(code),
-					)
-				}
-			}
-		}
-		{
-			codezTypeMethods := make([]Code, 0)
-			b2tm.IterValid(pathVersion,
-				func(receiverTypeID string, methodQualifiers x.FuncQualifierSlice) {
-
-					qual := methodQualifiers[0]
-					// Find receiver type:
-					typ := x.FindType(qual.Path, qual.Version, receiverTypeID)
-					if typ == nil {
-						Fatalf("Type not found: %q", receiverTypeID)
-					}
-
-					gogentools.ImportPackage(file, typ.PkgPath, typ.PkgName)
-
-					code := BlockFunc(
-						func(groupCase *Group) {
-
-							for _, methodQual := range methodQualifiers {
-								fn := x.GetFuncByQualifier(methodQual)
-								thing := fn.(*feparser.FETypeMethod)
-								x.AddImportsFromFunc(file, fn)
-
-								{
-									if AllFalse(methodQual.Pos...) {
-										continue
-									}
-									groupCase.Comment(thing.Func.Signature)
-
-									blocksOfCases := generateGoTestBlock_Method(
-										file,
-										thing,
-										methodQual,
-									)
-									if len(blocksOfCases) == 1 {
-										groupCase.Add(blocksOfCases...)
-									} else {
-										groupCase.Block(blocksOfCases...)
-									}
-								}
-
-							}
-						})
-					// TODO: what if no flows are enabled? Check that before adding the comment.
-					codezTypeMethods = append(codezTypeMethods,
-						Commentf("Redirect via method calls on %s.", typ.QualifiedName).
-							Line().
-							Add(code),
-					)
-				})
-			if len(codezTypeMethods) > 0 {
-				codez = append(codez,
-					Comment("Redirect via method calls.").
-						Line().
-						Block(codezTypeMethods...),
-				)
-			}
-		}
-
-		{
-			codezIfaceMethods := make([]Code, 0)
-			b2itm.IterValid(pathVersion,
-				func(receiverTypeID string, methodQualifiers x.FuncQualifierSlice) {
-					qual := methodQualifiers[0]
-					// Find receiver type:
-					typ := x.FindType(qual.Path, qual.Version, receiverTypeID)
-					if typ == nil {
-						Fatalf("Type not found: %q", receiverTypeID)
-					}
-
-					gogentools.ImportPackage(file, typ.PkgPath, typ.PkgName)
-
-					code := BlockFunc(
-						func(groupCase *Group) {
-
-							for _, methodQual := range methodQualifiers {
-								fn := x.GetFuncByQualifier(methodQual)
-								thing := fn.(*feparser.FEInterfaceMethod)
-								x.AddImportsFromFunc(file, fn)
-
-								{
-									if AllFalse(methodQual.Pos...) {
-										continue
-									}
-									groupCase.Comment(thing.Func.Signature)
-
-									converted := feparser.FEIToFET(thing)
-									blocksOfCases := generateGoTestBlock_Method(
-										file,
-										converted,
-										methodQual,
-									)
-									if len(blocksOfCases) == 1 {
-										groupCase.Add(blocksOfCases...)
-									} else {
-										groupCase.Block(blocksOfCases...)
-									}
-								}
-							}
-						})
-					codezIfaceMethods = append(codezIfaceMethods,
-						Commentf("Redirect via method calls on %s interface.", typ.QualifiedName).
-							Line().
-							Add(code),
-					)
-				})
-
-			if len(codezIfaceMethods) > 0 {
 				codez = append(codez,
-					Comment("Redirect via interface method calls.").
+					Comment("Redirect via struct fields.").
 						Line().
-						Block(codezIfaceMethods...),
+						Add(code),
 				)
 			}
 		}
@@ -351,41 +224,188 @@ func newStatement() *Statement {
 	return &Statement{}
 }
 
-func generateGoTestBlock_Func(file *File, fe *feparser.FEFunc, qual *x.FuncQualifier) []Code {
-	childBlocks := make([]Code, 0)
+// generateGoTestCodez generates the test code blocks for all the funcs and methods
+// selected for the provided pathVersion, using gen to generate the single test blocks.
+func generateGoTestCodez(
+	file *File,
+	pathVersion string,
+	what string,
+	b2fe x.BasicToFEFuncs,
+	b2tm x.BasicToTypeIDToMethods,
+	b2itm x.BasicToInterfaceIDToMethods,
+	gen func(file *File, fn x.FuncInterface, qual *x.FuncQualifier, receiverTypeID string) []Code,
+) []Code {
+	codez := make([]Code, 0)
+
+	{
+		cont, ok := b2fe[pathVersion]
+		if ok && x.HasValidPos(cont...) {
+			addedCount := 0
+			code := BlockFunc(
+				func(groupCase *Group) {
 
-	indexes := x.MustPosToRelativeParamIndexes(fe, qual.Pos)
+					for _, qual := range cont {
+						fn := x.GetFuncByQualifier(qual)
+						thing := fn.(*feparser.FEFunc)
 
-	childBlock := generate_Func(
-		file,
-		fe,
-		indexes,
-	)
+						x.AddImportsFromFunc(file, thing)
+
+						{
+							if AllFalse(qual.Pos...) {
+								continue
+							}
+							groupCase.Comment(thing.Signature)
+
+							blocksOfCases := gen(file, thing, qual, "")
+							if len(blocksOfCases) == 1 {
+								groupCase.Add(blocksOfCases...)
+							} else {
+								groupCase.Block(blocksOfCases...)
+							}
+							addedCount++
+						}
+
+					}
+				})
+			if addedCount > 0 {
+				codez = append(codez,
+					Commentf("%s via function call.", what).
+						Line().
+						Add(code),
+				)
+			}
+		}
+	}
 	{
-		if childBlock != nil {
-			childBlocks = append(childBlocks, childBlock)
-		} else {
-			Warnf(Sf("NOTHING GENERATED; pos %v, param indexes %v", qual.Pos, indexes))
+		codezTypeMethods := make([]Code, 0)
+		b2tm.IterValid(pathVersion,
+			func(receiverTypeID string, methodQualifiers x.FuncQualifierSlice) {
+
+				firstQual := methodQualifiers[0]
+				// Find receiver type:
+				typ := x.FindType(firstQual.Path, firstQual.Version, receiverTypeID)
+				if typ == nil {
+					Fatalf("Type not found: %q", receiverTypeID)
+				}
+
+				gogentools.ImportPackage(file, typ.PkgPath, typ.PkgName)
+
+				code := BlockFunc(
+					func(groupCase *Group) {
+
+						for _, qual := range methodQualifiers {
+							fn := x.GetFuncByQualifier(qual)
+							thing := fn.(*feparser.FETypeMethod)
+							x.AddImportsFromFunc(file, fn)
+
+							{
+								if AllFalse(qual.Pos...) {
+									continue
+								}
+								groupCase.Comment(thing.Func.Signature)
+
+								blocksOfCases := gen(file, thing, qual, receiverTypeID)
+								if len(blocksOfCases) == 1 {
+									groupCase.Add(blocksOfCases...)
+								} else {
+									groupCase.Block(blocksOfCases...)
+								}
+							}
+
+						}
+					})
+				codezTypeMethods = append(codezTypeMethods,
+					Commentf("%s via method calls on %s.", what, typ.QualifiedName).
+						Line().
+						Add(code),
+				)
+			})
+		if len(codezTypeMethods) > 0 {
+			codez = append(codez,
+				Commentf("%s via method calls.", what).
+					Line().
+					Block(codezTypeMethods...),
+			)
 		}
 	}
 
-	return childBlocks
+	{
+		codezIfaceMethods := make([]Code, 0)
+		b2itm.IterValid(pathVersion,
+			func(receiverTypeID string, methodQualifiers x.FuncQualifierSlice) {
+				firstQual := methodQualifiers[0]
+				// Find receiver type:
+				typ := x.FindType(firstQual.Path, firstQual.Version, receiverTypeID)
+				if typ == nil {
+					Fatalf("Type not found: %q", receiverTypeID)
+				}
+
+				gogentools.ImportPackage(file, typ.PkgPath, typ.PkgName)
+
+				code := BlockFunc(
+					func(groupCase *Group) {
+
+						for _, qual := range methodQualifiers {
+							fn := x.GetFuncByQualifier(qual)
+							thing := fn.(*feparser.FEInterfaceMethod)
+							x.AddImportsFromFunc(file, fn)
+
+							{
+								if AllFalse(qual.Pos...) {
+									continue
+								}
+								groupCase.Comment(thing.Func.Signature)
+
+								converted := feparser.FEIToFET(thing)
+
+								blocksOfCases := gen(file, converted, qual, receiverTypeID)
+								if len(blocksOfCases) == 1 {
+									groupCase.Add(blocksOfCases...)
+								} else {
+									groupCase.Block(blocksOfCases...)
+								}
+							}
+						}
+					})
+				codezIfaceMethods = append(codezIfaceMethods,
+					Commentf("%s via method calls on %s interface.", what, typ.QualifiedName).
+						Line().
+						Add(code),
+				)
+			})
+
+		if len(codezIfaceMethods) > 0 {
+			codez = append(codez,
+				Commentf("%s via interface method calls.", what).
+					Line().
+					Block(codezIfaceMethods...),
+			)
+		}
+	}
+
+	return codez
 }
-func generateGoTestBlock_Method(file *File, fe *feparser.FETypeMethod, qual *x.FuncQualifier) []Code {
+
+func generateGoTestBlock(file *File, fn x.FuncInterface, urlQual *x.FuncQualifier, statusQual *x.FuncQualifier) []Code {
 	childBlocks := make([]Code, 0)
 
-	indexes := x.MustPosToRelativeParamIndexes(fe, qual.Pos)
+	urlIndexes := x.MustPosToRelativeParamIndexes(fn, urlQual.Pos)
+	statusIndex := -1
+	if statusQual != nil {
+		statusIndex = x.MustPosToRelativeParamIndexes(fn, statusQual.Pos)[0]
+	}
 
-	childBlock := generate_Method(
+	childBlock := generate_Call(
 		file,
-		fe,
-		indexes,
+		fn,
+		urlIndexes,
+		statusIndex,
 	)
 	{
 		if childBlock != nil {
 			childBlocks = append(childBlocks, childBlock)
 		} else {
-			Warnf(Sf("NOTHING GENERATED; pos %v, param indexes %v", qual.Pos, indexes))
+			Warnf(Sf("NOTHING GENERATED; pos %v, param indexes %v", urlQual.Pos, urlIndexes))
 		}
 	}
 
@@ -393,102 +413,131 @@ func generateGoTestBlock_Method(file *File, fe *feparser.FETypeMethod, qual *x.F
 }
 
 ////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
-func generate_Func(file *File, fe *feparser.FEFunc, indexes []int) *Statement {
-
-	for _, index := range indexes {
-		in := fe.Parameters[index]
-
+// generate_Call generates a call to the func (or method) that redirects to tainted URLs;
+// if statusIndex is not -1, the status code param is set to a redirect status code.
+func generate_Call(file *File, fn x.FuncInterface, urlIndexes []int, statusIndex int) *Statement {
+	varNames := make([]string, 0)
+	for _, index := range urlIndexes {
+		in := fn.GetFunc().Parameters[index]
 		in.VarName = gogentools.NewNameWithPrefix(feparser.NewLowerTitleName("url", in.TypeName))
+		varNames = append(varNames, in.VarName)
 	}
 
-	varNames := make([]string, 0)
-	for _, index := range indexes {
-		in := fe.Parameters[index]
-
-		varNames = append(varNames, in.VarName)
+	if statusIndex != -1 {
+		statusParam := fn.GetFunc().Parameters[statusIndex]
+		statusParam.VarName = gogentools.NewNameWithPrefix(feparser.NewLowerTitleName("status", statusParam.TypeName))
 	}
 
 	code := BlockFunc(
 		func(groupCase *Group) {
 
-			for _, index := range indexes {
-				in := fe.Parameters[index]
-
+			for _, index := range urlIndexes {
+				in := fn.GetFunc().Parameters[index]
 				ComposeTypeAssertion(file, groupCase, in.VarName, in.GetOriginal().GetType(), in.GetOriginal().IsVariadic())
 			}
 
-			groupCase.Qual(fe.PkgPath, fe.Name).CallFunc(
-				func(call *Group) {
-
-					tpFun := fe.GetOriginal().GetType().(*types.Signature)
-
-					zeroVals := gogentools.ScanTupleOfZeroValues(file, tpFun.Params(), fe.GetOriginal().IsVariadic())
+			if statusIndex != -1 {
+				statusParam := fn.GetFunc().Parameters[statusIndex]
+				ComposeStatusCodeDeclaration(file, groupCase, statusParam.VarName, statusParam.GetOriginal().GetType())
+			}
 
-					for i, zero := range zeroVals {
-						isConsidered := IntSliceContains(indexes, i)
-						if isConsidered {
-							call.Id(fe.Parameters[i].VarName)
-						} else {
-							call.Add(zero)
-						}
-					}
+			if fn.GetReceiver() != nil {
+				Comments(groupCase, "Declare medium object/interface:")
+				groupCase.Var().Id("rece").Qual(fn.GetReceiver().PkgPath, fn.GetReceiver().TypeName)
+			}
 
-				},
-			).Add(Tag(varNames...))
+			gogentools.ImportPackage(file, fn.GetFunc().PkgPath, fn.GetFunc().PkgName)
 
+			isConsidered := func(i int) bool { return IntSliceContains(urlIndexes, i) || i == statusIndex }
+			groupCase.Add(composeCall(file, fn, isConsidered)).Add(Tag(varNames...))
 		})
 	return code
 }
-func generate_Method(file *File, fe *feparser.FETypeMethod, indexes []int) *Statement {
-
-	for _, index := range indexes {
-		in := fe.Func.Parameters[index]
 
-		in.VarName = gogentools.NewNameWithPrefix(feparser.NewLowerTitleName("url", in.TypeName))
+// composeCall composes the call to the func, passing the variables of the considered parameters,
+// and the zero value for all the others.
+func composeCall(file *File, fn x.FuncInterface, isConsidered func(index int) bool) *Statement {
+	callCode := newStatement()
+	if fn.GetReceiver() != nil {
+		callCode.Id("rece").Dot(fn.GetFunc().Name)
+	} else {
+		callCode.Qual(fn.GetFunc().PkgPath, fn.GetFunc().Name)
 	}
 
-	varNames := make([]string, 0)
-	for _, index := range indexes {
-		in := fe.Func.Parameters[index]
-
-		varNames = append(varNames, in.VarName)
-	}
+	callCode.CallFunc(
+		func(call *Group) {
 
-	code := BlockFunc(
-		func(groupCase *Group) {
+			tpFun := fn.GetFunc().GetOriginal().GetType().(*types.Signature)
 
-			for _, index := range indexes {
-				in := fe.Func.Parameters[index]
+			zeroVals := gogentools.ScanTupleOfZeroValues(file, tpFun.Params(), fn.GetFunc().GetOriginal().IsVariadic())
 
-				ComposeTypeAssertion(file, groupCase, in.VarName, in.GetOriginal().GetType(), in.GetOriginal().IsVariadic())
+			for i, zero := range zeroVals {
+				if isConsidered(i) {
+					call.Id(fn.GetFunc().Parameters[i].VarName)
+				} else {
+					call.Add(zero)
+				}
 			}
 
-			Comments(groupCase, "Declare medium object/interface:")
-			groupCase.Var().Id("rece").Qual(fe.Receiver.PkgPath, fe.Receiver.TypeName)
-
-			gogentools.ImportPackage(file, fe.Func.PkgPath, fe.Func.PkgName)
-
-			groupCase.Id("rece").Dot(fe.Func.Name).CallFunc(
-				func(call *Group) {
+		},
+	)
+	return callCode
+}
 
-					tpFun := fe.Func.GetOriginal().GetType().(*types.Signature)
+// generate_StructField generates writes of tainted URLs to the selected fields of the struct.
+func generate_StructField(file *File, qual *x.StructQualifier) *Statement {
+	source := x.GetCachedSource(qual.Path, qual.Version)
+	if source == nil {
+		Fatalf("Source not found: %s@%s", qual.Path, qual.Version)
+	}
+	// Make sure that the struct exist:
+	str := x.FindStructByID(source, qual.ID)
+	if str == nil {
+		Fatalf("Struct not found: %q", qual.ID)
+	}
 
-					zeroVals := gogentools.ScanTupleOfZeroValues(file, tpFun.Params(), fe.Func.GetOriginal().IsVariadic())
+	gogentools.ImportPackage(file, str.PkgPath, str.PkgName)
 
-					for i, zero := range zeroVals {
-						isConsidered := IntSliceContains(indexes, i)
-						if isConsidered {
-							call.Id(fe.Func.Parameters[i].VarName)
-						} else {
-							call.Add(zero)
-						}
-					}
+	fieldNames := make([]string, 0)
+	for fieldName := range qual.Fields {
+		fieldNames = append(fieldNames, fieldName)
+	}
+	sort.Strings(fieldNames)
 
-				},
-			).Add(Tag(varNames...))
+	return BlockFunc(
+		func(groupCase *Group) {
+			structVarName := gogentools.NewNameWithPrefix(feparser.NewLowerTitleName("redirect", str.TypeName))
+			groupCase.Var().Id(structVarName).Qual(str.PkgPath, str.TypeName)
 
+			for _, fieldName := range fieldNames {
+				fld := x.FindFieldByName(str, fieldName)
+				if fld == nil {
+					Fatalf("Field not found: %q", fieldName)
+				}
+				if fld.TypeString != "string" {
+					// TODO: support fields of other types (e.g. *url.URL).
+					Warnf(Sf("NOTHING GENERATED; field %s.%s of type %s must be tested manually", str.QualifiedName, fieldName, fld.TypeString))
+					groupCase.Commentf("TODO: %s.%s is not a string (%s).", structVarName, fieldName, fld.TypeString)
+					continue
+				}
+				urlVarName := gogentools.NewNameWithPrefix(feparser.NewLowerTitleName("url", fieldName))
+				groupCase.Id(urlVarName).Op(":=").Id("source").Call().Assert(String())
+				groupCase.Id(structVarName).Dot(fieldName).Op("=").Id(urlVarName).Add(Tag(urlVarName))
+			}
+			groupCase.Id("_").Op("=").Id(structVarName)
 		})
-	return code
+}
+
+// ComposeStatusCodeDeclaration declares a redirect status code:
+// `var name Type = 302` if the type is an integer, or `var name Type` otherwise.
+func ComposeStatusCodeDeclaration(file *File, group *Group, varName string, typ types.Type) {
+	if basic, ok := typ.Underlying().(*types.Basic); ok && basic.Info()&types.IsInteger != 0 {
+		typeContent := newStatement()
+		gogentools.ComposeTypeDeclaration(file, typeContent, typ)
+		group.Var().Id(varName).Add(typeContent).Op("=").Lit(302)
+		return
+	}
+	gogentools.ComposeVarDeclaration(file, group, varName, typ, false)
 }
 
 ////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
@@ -6,6 +6,12 @@ import (
 	"github.com/gagliardetto/codemill/x"
 )
 
+// NOTE:
+// - The URL of a call redirect must be one or more parameters (not the receiver).
+// - Each func that you add to MethodStatusCode must also be added to MethodGetURL;
+//   the status code is optional, and must be a different parameter than the URL.
+// - The URL of a struct redirect is the value written to the selected field(s).
+
 const (
 	Kind x.ModelKind = "HTTP::Redirect"
 )
@@ -13,25 +19,54 @@ const (
 type Handler struct{}
 
 const (
-	MethodGetURL = "{url:Param} <- $url"
+	MethodGetURL       = "{url:Param} <- $url"                  // The URL the response is redirected to.
+	MethodStatusCode   = "{url:Param, status:Param} <- $status" // The status code of the redirect (optional).
+	MethodURLFromField = "{url:Fields} <- $url"                 // The struct field that holds the redirect URL.
 )
 
 //
 func (han *Handler) ScavengeMethods() []*x.XMethod {
-	return []*x.XMethod{
-		{
-			Name:      MethodGetURL,
-			Selectors: []*x.XSelector{},
-		},
-	}
+	return x.ScavengeMethods(
+		MethodGetURL,       // "Select the parameter that specifies the URL of the redirect.",
+		MethodStatusCode,   // "Optional: select the status code parameter of funcs already selected in the URL method.",
+		MethodURLFromField, // "Select the struct field(s) that hold the URL of the redirect (e.g. `Location`).",
+	)
 }
 func (han *Handler) Validate(mdl *x.XModel) error {
-	if len(mdl.Methods) != 1 {
-		return fmt.Errorf("wrong number of methods; expected 1, got %v", len(mdl.Methods))
+	defaultMthNum := len(han.ScavengeMethods())
+	if len(mdl.Methods) != defaultMthNum {
+		return fmt.Errorf("wrong number of methods; expected %v, got %v", defaultMthNum, len(mdl.Methods))
+	}
+	{
+		for i, must := range han.ScavengeMethods() {
+			if mdl.Methods[i].Name != must.Name {
+				return fmt.Errorf("#%v method is not called %s", i, must.Name)
+			}
+		}
+	}
+	mtdGetURL := mdl.Methods.ByName(MethodGetURL)
+	mtdStatusCode := mdl.Methods.ByName(MethodStatusCode)
+	mtdURLFromField := mdl.Methods.ByName(MethodURLFromField)
+	{
+		if err := x.ValidateParams(mtdGetURL, false); err != nil {
+			return err
+		}
+		if err := x.ValidateParams(mtdStatusCode, true); err != nil {
+			return err
+		}
+	}
+	{
+		// Each status code selector must have a corresponding URL selector,
+		// and the status code and the URL must be different parameters:
+		if err := x.ValidateDistinctParams(mtdStatusCode, mtdGetURL); err != nil {
+			return err
+		}
 	}
 	{
-		if mdl.Methods[0].Name != MethodGetURL {
-			return fmt.Errorf("#0 method is not called %s", MethodGetURL)
+		for _, sel := range mtdURLFromField.Selectors {
+			if sel.Kind != x.SelectorKindStruct {
+				return fmt.Errorf("method %s supports only struct selectors", mtdURLFromField.Name)
+			}
 		}
 	}
 	return nil
@@ -0,0 +1,75 @@
+package requesthandler
+
+import (
+	"github.com/gagliardetto/codemill/x"
+	. "github.com/gagliardetto/cqlgen/jen"
+	"github.com/gagliardetto/feparser"
+	. "github.com/gagliardetto/utilz"
+)
+
+func (han *Handler) GenerateCodeQL(impAdder x.ImportAdder, mdl *x.XModel, rootModuleGroup *Group) error {
+	if err := mdl.Validate(); err != nil {
+		return err
+	}
+	if err := han.Validate(mdl); err != nil {
+		return err
+	}
+
+	// Assuming the validation has already been done:
+	mtdRoutePattern := mdl.Methods.ByName(MethodRoutePattern)
+	mtdHandler := mdl.Methods.ByName(MethodHandler)
+
+	if len(mtdRoutePattern.Selectors) == 0 {
+		Infof("No selectors found for %q method.", mtdRoutePattern.Name)
+		return nil
+	}
+
+	{
+		// Add imports:
+		//impAdder.Import("DataFlow::PathGraph")
+	}
+
+	className := mdl.Name
+	allPathVersions := mdl.ListAllPathVersions()
+
+	{
+		funcModelsClassName := feparser.NewCodeQlName(className)
+
+		// NOTE: the pattern and the handler are coupled, so both
+		// disjunctions bind `call` to the same call.
+		routePatternCode, routePatternCount := x.CqlCallTargets(allPathVersions, mtdRoutePattern, "call", "Request handler models", x.CqlBindArgument("call", "routePatternNode"))
+		handlerCode, _ := x.CqlCallTargets(allPathVersions, mtdHandler, "call", "Request handler models", x.CqlBindArgument("call", "this"))
+
+		tmp := DoGroup(func(tempFuncsModel *Group) {
+			tempFuncsModel.Doc("Models HTTP request handlers registered for a route pattern.")
+			tempFuncsModel.Private().Class().Id(funcModelsClassName).Extends().List(
+				Id("HTTP::RequestHandler::Range"),
+			).BlockFunc(
+				func(funcModelsClassGroup *Group) {
+					funcModelsClassGroup.String().Id("package").Semicolon().Line()
+					funcModelsClassGroup.Id("DataFlow::CallNode").Id("call").Semicolon().Line()
+					funcModelsClassGroup.Id("DataFlow::Node").Id("routePatternNode").Semicolon().Line()
+
+					funcModelsClassGroup.Id(funcModelsClassName).Call().Block(
+						Parens(routePatternCode).And().Parens(handlerCode),
+					)
+
+					funcModelsClassGroup.Override().Predicate().Id("guardedBy").Call(Id("DataFlow::Node").Id("check")).BlockFunc(
+						func(overrideBlockGroup *Group) {
+							overrideBlockGroup.Id("check").Eq().Id("routePatternNode")
+						})
+
+					funcModelsClassGroup.Doc("Gets the node that specifies the route pattern the handler is registered for.")
+					funcModelsClassGroup.Id("DataFlow::Node").Id("getRoutePattern").Call().BlockFunc(
+						func(blockGroup *Group) {
+							blockGroup.Id("result").Eq().Id("routePatternNode")
+						})
+				})
+		})
+		if routePatternCount > 0 {
+			rootModuleGroup.Add(tmp)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,525 @@
+package requesthandler
+
+import (
+	"go/types"
+	"os"
+	"path/filepath"
+
+	. "github.com/dave/jennifer/jen"
+	"github.com/gagliardetto/codebox/gogentools"
+	"github.com/gagliardetto/codemill/x"
+	"github.com/gagliardetto/feparser"
+	. "github.com/gagliardetto/utilz"
+)
+
+var (
+	GenerateBoilerplate bool
+)
+
+const (
+	// NOTE: hardcoded inside TestQueryContent const.
+	InlineExpectationsTestTag = "$routeHandler" // Must start with a $ sign.
+)
+
+func Tag(vals ...string) Code {
+	tg := ""
+	for i, v := range vals {
+		if i > 0 {
+			tg += " "
+		}
+		tg += InlineExpectationsTestTag + "=" + v
+	}
+	return Comment(tg)
+}
+
+const (
+	TestQueryContent = `
+import go
+import TestUtilities.InlineExpectationsTest
+
+class RequestHandlerTest extends InlineExpectationsTest {
+  RequestHandlerTest() { this = "RequestHandlerTest" }
+
+  override string getARelevantTag() { result = "routeHandler" }
+
+  override predicate hasActualResult(string file, int line, string element, string tag, string value) {
+    tag = "routeHandler" and
+    exists(HTTP::RequestHandler h, DataFlow::Node check | h.guardedBy(check) |
+      h.hasLocationInfo(file, line, _, _, _) and
+      element = h.toString() and
+      value = check.toString()
+    )
+  }
+}
+`
+)
+
+func NewTestFile(includeBoilerplace bool) *File {
+	file := NewFile("main")
+	// Set a prefix to avoid collision between variable names and packages:
+	file.PackagePrefix = "cql"
+	// Add comment to file:
+	file.HeaderComment("Code generated by https://github.com/gagliardetto. DO NOT EDIT.")
+
+	if includeBoilerplace {
+		file.PackageComment("//go:generate depstubber --vendor --auto")
+		{
+			// main function:
+			file.Func().Id("main").Params().Block()
+		}
+		{
+			// The `source` function returns a new route pattern:
+			code := Func().
+				Id("source").
+				Params().
+				Interface().
+				Block(Return(Nil()))
+			file.Add(code.Line())
+		}
+	}
+	return file
+}
+
+var (
+	IncludeCommentsInGeneratedGo bool
+)
+
+func (han *Handler) GenerateGo(parentDir string, mdl *x.XModel) error {
+	if err := mdl.Validate(); err != nil {
+		return err
+	}
+	if err := han.Validate(mdl); err != nil {
+		return err
+	}
+
+	// Check if there are multiple versions of a same package:
+	mods := mdl.ListModules()
+	if x.HasMultiversion(mods) {
+		Ln(RedBG("Has multiversion"))
+	}
+	// If there are no multiple versions of the same module,
+	// that means we can save all the code to one file.
+	allInOneFile := !x.HasMultiversion(mods)
+
+	// Create the directory for the tests for this model:
+	outDir := filepath.Join(parentDir, feparser.NewCodeQlName(mdl.Name))
+	MustCreateFolderIfNotExists(outDir, os.ModePerm)
+
+	// Assuming the validation has already been done:
+	mtdRoutePattern := mdl.Methods.ByName(MethodRoutePattern)
+	mtdHandler := mdl.Methods.ByName(MethodHandler)
+
+	if len(mtdRoutePattern.Selectors) == 0 {
+		Infof("No selectors found for %q method.", mtdRoutePattern.Name)
+		return nil
+	}
+
+	allPathVersions := mdl.ListAllPathVersions()
+
+	file := NewTestFile(GenerateBoilerplate)
+
+	b2fePattern, b2tmPattern, b2itmPattern, err := x.GroupFuncSelectors(mtdRoutePattern)
+	if err != nil {
+		Fatalf("Error while GroupFuncSelectors: %s", err)
+	}
+	b2feHandler, b2tmHandler, b2itmHandler, err := x.GroupFuncSelectors(mtdHandler)
+	if err != nil {
+		Fatalf("Error while GroupFuncSelectors: %s", err)
+	}
+
+	for _, pathVersion := range allPathVersions {
+		if !allInOneFile {
+			// Reset file:
+			file = NewTestFile(GenerateBoilerplate)
+		}
+		codez := make([]Code, 0)
+
+		{
+			cont, ok := b2fePattern[pathVersion]
+			if ok && x.HasValidPos(cont...) {
+				addedCount := 0
+				code := BlockFunc(
+					func(groupCase *Group) {
+
+						for _, patternQual := range cont {
+							fn := x.GetFuncByQualifier(patternQual)
+							thing := fn.(*feparser.FEFunc)
+
+							x.AddImportsFromFunc(file, thing)
+
+							{
+								if AllFalse(patternQual.Pos...) {
+									continue
+								}
+								groupCase.Comment(thing.Signature)
+
+								handlerQual := b2feHandler[pathVersion].ByBasicQualifier(patternQual.BasicQualifier)
+
+								blocksOfCases := generateGoTestBlock(
+									file,
+									thing,
+									patternQual,
+									handlerQual,
+								)
+								if len(blocksOfCases) == 1 {
+									groupCase.Add(blocksOfCases...)
+								} else {
+									groupCase.Block(blocksOfCases...)
+								}
+								addedCount++
+							}
+
+						}
+					})
+				if addedCount > 0 {
+					codez = append(codez,
+						Comment("Request handler registration via function call.").
+							Line().
+							Add(code),
+					)
+				}
+			}
+		}
+		{
+			codezTypeMethods := make([]Code, 0)
+			b2tmPattern.IterValid(pathVersion,
+				func(receiverTypeID string, methodQualifiers x.FuncQualifierSlice) {
+
+					qual := methodQualifiers[0]
+					// Find receiver type:
+					typ := x.FindType(qual.Path, qual.Version, receiverTypeID)
+					if typ == nil {
+						Fatalf("Type not found: %q", receiverTypeID)
+					}
+
+					gogentools.ImportPackage(file, typ.PkgPath, typ.PkgName)
+
+					code := BlockFunc(
+						func(groupCase *Group) {
+
+							for _, patternQual := range methodQualifiers {
+								fn := x.GetFuncByQualifier(patternQual)
+								thing := fn.(*feparser.FETypeMethod)
+								x.AddImportsFromFunc(file, fn)
+
+								{
+									if AllFalse(patternQual.Pos...) {
+										continue
+									}
+									groupCase.Comment(thing.Func.Signature)
+
+									handlerQual := b2tmHandler[pathVersion][receiverTypeID].ByBasicQualifier(patternQual.BasicQualifier)
+
+									blocksOfCases := generateGoTestBlock(
+										file,
+										thing,
+										patternQual,
+										handlerQual,
+									)
+									if len(blocksOfCases) == 1 {
+										groupCase.Add(blocksOfCases...)
+									} else {
+										groupCase.Block(blocksOfCases...)
+									}
+								}
+
+							}
+						})
+					codezTypeMethods = append(codezTypeMethods,
+						Commentf("Request handler registration via method calls on %s.", typ.QualifiedName).
+							Line().
+							Add(code),
+					)
+				})
+			if len(codezTypeMethods) > 0 {
+				codez = append(codez,
+					Comment("Request handler registration via method calls.").
+						Line().
+						Block(codezTypeMethods...),
+				)
+			}
+		}
+
+		{
+			codezIfaceMethods := make([]Code, 0)
+			b2itmPattern.IterValid(pathVersion,
+				func(receiverTypeID string, methodQualifiers x.FuncQualifierSlice) {
+					qual := methodQualifiers[0]
+					// Find receiver type:
+					typ := x.FindType(qual.Path, qual.Version, receiverTypeID)
+					if typ == nil {
+						Fatalf("Type not found: %q", receiverTypeID)
+					}
+
+					gogentools.ImportPackage(file, typ.PkgPath, typ.PkgName)
+
+					code := BlockFunc(
+						func(groupCase *Group) {
+
+							for _, patternQual := range methodQualifiers {
+								fn := x.GetFuncByQualifier(patternQual)
+								thing := fn.(*feparser.FEInterfaceMethod)
+								x.AddImportsFromFunc(file, fn)
+
+								{
+									if AllFalse(patternQual.Pos...) {
+										continue
+									}
+									groupCase.Comment(thing.Func.Signature)
+
+									converted := feparser.FEIToFET(thing)
+
+									handlerQual := b2itmHandler[pathVersion][receiverTypeID].ByBasicQualifier(patternQual.BasicQualifier)
+
+									blocksOfCases := generateGoTestBlock(
+										file,
+										converted,
+										patternQual,
+										handlerQual,
+									)
+									if len(blocksOfCases) == 1 {
+										groupCase.Add(blocksOfCases...)
+									} else {
+										groupCase.Block(blocksOfCases...)
+									}
+								}
+							}
+						})
+					codezIfaceMethods = append(codezIfaceMethods,
+						Commentf("Request handler registration via method calls on %s interface.", typ.QualifiedName).
+							Line().
+							Add(code),
+					)
+				})
+
+			if len(codezIfaceMethods) > 0 {
+				codez = append(codez,
+					Comment("Request handler registration via interface method calls.").
+						Line().
+						Block(codezIfaceMethods...),
+				)
+			}
+		}
+
+		{
+			file.Commentf("Package %s", pathVersion)
+			file.Func().Id(mdl.Name + "_" + feparser.FormatCodeQlName(pathVersion)).Params().Block(codez...)
+		}
+
+		if !allInOneFile {
+			pkgDstDirpath := filepath.Join(outDir, feparser.FormatID(mdl.Name, "For", feparser.FormatCodeQlName(pathVersion)))
+			MustCreateFolderIfNotExists(pkgDstDirpath, os.ModePerm)
+
+			assetFileName := feparser.FormatID(mdl.Name, "For", feparser.FormatCodeQlName(pathVersion)) + ".go"
+			if err := x.SaveGoFile(pkgDstDirpath, assetFileName, file); err != nil {
+				Fatalf("Error while saving go file: %s", err)
+			}
+
+			if err := x.WriteGoModFile(pkgDstDirpath, pathVersion); err != nil {
+				Fatalf("Error while saving go.mod file: %s", err)
+			}
+			if err := x.WriteCodeQLTestQuery(pkgDstDirpath, mdl.Name, TestQueryContent); err != nil {
+				Fatalf("Error while saving <name>.ql file: %s", err)
+			}
+			if err := x.WriteEmptyCodeQLDotExpectedFile(pkgDstDirpath, mdl.Name); err != nil {
+				Fatalf("Error while saving <name>.expected file: %s", err)
+			}
+		}
+	}
+
+	if allInOneFile {
+		pkgDstDirpath := outDir
+		MustCreateFolderIfNotExists(pkgDstDirpath, os.ModePerm)
+
+		assetFileName := feparser.FormatID(mdl.Name) + ".go"
+		if err := x.SaveGoFile(pkgDstDirpath, assetFileName, file); err != nil {
+			Fatalf("Error while saving go file: %s", err)
+		}
+
+		if err := x.WriteGoModFile(pkgDstDirpath, allPathVersions...); err != nil {
+			Fatalf("Error while saving go.mod file: %s", err)
+		}
+		if err := x.WriteCodeQLTestQuery(pkgDstDirpath, mdl.Name, TestQueryContent); err != nil {
+			Fatalf("Error while saving <name>.ql file: %s", err)
+		}
+		if err := x.WriteEmptyCodeQLDotExpectedFile(pkgDstDirpath, mdl.Name); err != nil {
+			Fatalf("Error while saving <name>.expected file: %s", err)
+		}
+	}
+	return nil
+}
+
+// Comments adds comments to a Group (if enabled), and returns the group.
+func Comments(group *Group, comments ...string) *Group {
+	if IncludeCommentsInGeneratedGo {
+		for _, comment := range comments {
+			group.Line().Comment(comment)
+		}
+	}
+	return group
+}
+
+func newStatement() *Statement {
+	return &Statement{}
+}
+
+func generateGoTestBlock(
+	file *File,
+	fn x.FuncInterface,
+	patternQual *x.FuncQualifier,
+	handlerQual *x.FuncQualifier,
+) []Code {
+	childBlocks := make([]Code, 0)
+
+	patternIndexes := x.MustPosToRelativeParamIndexes(fn, patternQual.Pos)
+	if len(patternIndexes) != 1 {
+		Fatalf("patternIndexes len is not 1: %v", patternQual)
+	}
+	handlerIndexes := x.MustPosToRelativeParamIndexes(fn, handlerQual.Pos)
+	if len(handlerIndexes) != 1 {
+		Fatalf("handlerIndexes len is not 1: %v", handlerQual)
+	}
+
+	childBlock := generate(
+		file,
+		fn,
+		patternIndexes[0],
+		handlerIndexes[0],
+	)
+	{
+		if childBlock != nil {
+			childBlocks = append(childBlocks, childBlock)
+		} else {
+			Warnf(Sf("NOTHING GENERATED; patternQual %v, handlerQual %v", patternQual, handlerQual))
+		}
+	}
+
+	return childBlocks
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+func generate(file *File, fn x.FuncInterface, patternIndex int, handlerIndex int) *Statement {
+	if patternIndex == handlerIndex {
+		return nil
+	}
+
+	patternParam := fn.GetFunc().Parameters[patternIndex]
+	patternParam.VarName = gogentools.NewNameWithPrefix(feparser.NewLowerTitleName("pattern", patternParam.TypeName))
+
+	handlerParam := fn.GetFunc().Parameters[handlerIndex]
+	handlerParam.VarName = gogentools.NewNameWithPrefix(feparser.NewLowerTitleName("handler", handlerParam.TypeName))
+
+	hasReceiver := fn.GetReceiver() != nil
+
+	code := BlockFunc(
+		func(groupCase *Group) {
+
+			ComposeTypeAssertion(file, groupCase, patternParam.VarName, patternParam.GetOriginal().GetType(), patternParam.GetOriginal().IsVariadic())
+			ComposeHandlerDeclaration(file, groupCase, handlerParam.VarName, handlerParam.GetOriginal().GetType(), handlerParam.GetOriginal().IsVariadic())
+
+			if hasReceiver {
+				Comments(groupCase, "Declare medium object/interface:")
+				groupCase.Var().Id("rece").Qual(fn.GetReceiver().PkgPath, fn.GetReceiver().TypeName)
+			}
+
+			gogentools.ImportPackage(file, fn.GetFunc().PkgPath, fn.GetFunc().PkgName)
+
+			var after *Statement
+			if hasReceiver {
+				after = groupCase.Id("rece").Dot(fn.GetFunc().Name)
+			} else {
+				after = groupCase.Qual(fn.GetFunc().PkgPath, fn.GetFunc().Name)
+			}
+
+			after.CallFunc(
+				func(call *Group) {
+
+					tpFun := fn.GetFunc().GetOriginal().GetType().(*types.Signature)
+
+					zeroVals := gogentools.ScanTupleOfZeroValues(file, tpFun.Params(), fn.GetFunc().GetOriginal().IsVariadic())
+
+					for i, zero := range zeroVals {
+						isConsidered := i == patternIndex || i == handlerIndex
+						if isConsidered {
+							call.Id(fn.GetFunc().Parameters[i].VarName)
+						} else {
+							call.Add(zero)
+						}
+					}
+
+				},
+			).Add(Tag(patternParam.VarName))
+
+		})
+	return code
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// declare `name := source(1).(Type)`
+func ComposeTypeAssertion(file *File, group *Group, varName string, typ types.Type, isVariadic bool) {
+	assertContent := newStatement()
+	if isVariadic {
+		if slice, ok := typ.(*types.Slice); ok {
+			gogentools.ComposeTypeDeclaration(file, assertContent, slice.Elem())
+		} else {
+			gogentools.ComposeTypeDeclaration(file, assertContent, typ)
+		}
+	} else {
+		gogentools.ComposeTypeDeclaration(file, assertContent, typ)
+	}
+	group.Id(varName).Op(":=").Id("source").Call().Assert(assertContent)
+}
+
+// ComposeHandlerDeclaration declares a user-defined handler:
+// - for func types (e.g. `http.HandlerFunc`), `var name Type = func(...) {...}`;
+// - for any other type (e.g. the `http.Handler` interface), `name := source().(Type)`.
+func ComposeHandlerDeclaration(file *File, group *Group, varName string, typ types.Type, isVariadic bool) {
+	elemTyp := typ
+	if isVariadic {
+		if slice, ok := typ.(*types.Slice); ok {
+			elemTyp = slice.Elem()
+		}
+	}
+	sig, ok := elemTyp.Underlying().(*types.Signature)
+	if !ok {
+		ComposeTypeAssertion(file, group, varName, typ, isVariadic)
+		return
+	}
+
+	typeContent := newStatement()
+	gogentools.ComposeTypeDeclaration(file, typeContent, elemTyp)
+
+	paramsContent := make([]Code, 0)
+	for i := 0; i < sig.Params().Len(); i++ {
+		paramContent := newStatement()
+		paramTyp := sig.Params().At(i).Type()
+		if sig.Variadic() && i == sig.Params().Len()-1 {
+			if slice, ok := paramTyp.(*types.Slice); ok {
+				paramContent.Op("...")
+				paramTyp = slice.Elem()
+			}
+		}
+		gogentools.ComposeTypeDeclaration(file, paramContent, paramTyp)
+		paramsContent = append(paramsContent, paramContent)
+	}
+
+	resultsContent := make([]Code, 0)
+	for i := 0; i < sig.Results().Len(); i++ {
+		resultContent := newStatement()
+		gogentools.ComposeTypeDeclaration(file, resultContent, sig.Results().At(i).Type())
+		resultsContent = append(resultsContent, resultContent)
+	}
+	resultZeroVals := gogentools.ScanTupleOfZeroValues(file, sig.Results(), false)
+
+	fnLit := Func().Params(paramsContent...)
+	if len(resultsContent) > 0 {
+		fnLit.Params(resultsContent...)
+	}
+	group.Var().Id(varName).Add(typeContent).Op("=").Add(fnLit).BlockFunc(
+		func(body *Group) {
+			if len(resultZeroVals) > 0 {
+				body.Return(resultZeroVals...)
+			}
+		})
+}
@@ -0,0 +1,63 @@
+package requesthandler
+
+import (
+	"fmt"
+
+	"github.com/gagliardetto/codemill/x"
+)
+
+// NOTE:
+// - Each func that you add to MethodRoutePattern must also be added to MethodHandler (and vice versa).
+// - Both the route pattern and the handler must be exactly one parameter
+//   (the handler parameter can be variadic, e.g. `GET(path string, handlers ...HandlerFunc)`).
+
+const (
+	Kind x.ModelKind = "HTTP::RequestHandler"
+)
+
+type Handler struct{}
+
+const (
+	MethodRoutePattern = "{pattern:Param, handler:Param} <- $pattern" // The route pattern the handler is registered for.
+	MethodHandler      = "{pattern:Param, handler:Param} <- $handler" // The handler that is registered.
+)
+
+//
+func (han *Handler) ScavengeMethods() []*x.XMethod {
+	return x.ScavengeMethods(
+		// Each func that you add to MethodRoutePattern,
+		// you must also add it to MethodHandler.
+		MethodRoutePattern, // "Coupled 1/2: Select the parameter that specifies the route pattern.",
+		MethodHandler,      // "Coupled 2/2: Select the parameter that specifies the handler.",
+	)
+}
+func (han *Handler) Validate(mdl *x.XModel) error {
+	defaultMthNum := len(han.ScavengeMethods())
+	if len(mdl.Methods) != defaultMthNum {
+		return fmt.Errorf("wrong number of methods; expected %v, got %v", defaultMthNum, len(mdl.Methods))
+	}
+	{
+		for i, must := range han.ScavengeMethods() {
+			if mdl.Methods[i].Name != must.Name {
+				return fmt.Errorf("#%v method is not called %s", i, must.Name)
+			}
+		}
+	}
+	{
+		// Each pattern selector must have a corresponding handler selector (and vice versa):
+		mtdPattern := mdl.Methods.ByName(MethodRoutePattern)
+		mtdHandler := mdl.Methods.ByName(MethodHandler)
+		for _, mtd := range []*x.XMethod{mtdPattern, mtdHandler} {
+			if err := x.ValidateParams(mtd, true); err != nil {
+				return err
+			}
+		}
+		if err := x.ValidateCoupled(mtdPattern, mtdHandler); err != nil {
+			return err
+		}
+		if err := x.ValidateCoupled(mtdHandler, mtdPattern); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -66,7 +66,7 @@ func (han *Handler) GenerateCodeQL(impAdder x.ImportAdder, mdl *x.XModel, rootMo
 						})
 				})
 		})
-		pred := predicate_setsBody_Static_ContentType(allPathVersions, mdl)
+		pred := predicate_setsBody_Static_ContentType(allPathVersions, mdl, mdl.ContentTypeRules())
 		if pred != nil {
 			rootModuleGroup.Add(tmp)
 			rootModuleGroup.Add(pred)
@@ -172,7 +172,7 @@ const (
 	setsBody                      = "setsBody"
 )
 
-func predicate_setsBody_Static_ContentType(allPathVersions []string, mdl *x.XModel) Code {
+func predicate_setsBody_Static_ContentType(allPathVersions []string, mdl *x.XModel, rules []*x.ContentTypeRule) Code {
 	predicate :=
 		Comment("Holds for a call that sets the body; the content-type is implicitly set.").
 			Private().Predicate().Id(setsBodyAndStaticContentType).Call(
@@ -185,19 +185,15 @@ func predicate_setsBody_Static_ContentType(allPathVersions []string, mdl *x.XMod
 			),
 		)
 
-	addedCount := 0
-	predicate.BlockFunc(func(predicateBlock *Group) {
-		{
-			pc := cql_MethodBodyWithCtFromFuncName(mdl, allPathVersions)
-			if len(pc) > 0 {
-				addedCount++
-			}
-			predicateBlock.Add(pc...)
-		}
-	})
-	if addedCount == 0 {
+	pc := make([]Code, 0)
+	pc = append(pc, cql_MethodBodyWithCtFromFuncName(mdl, allPathVersions, rules)...)
+	pc = append(pc, cql_MethodBodyWithCtFromRenderer(mdl, allPathVersions, rules)...)
+	if len(pc) == 0 {
 		return nil
 	}
+	predicate.Block(
+		Join(Or(), pc...),
+	)
 	return predicate
 }
 
@@ -263,7 +259,7 @@ func GetBodySetterFuncQualifierCodeElements(qual *x.FuncQualifier) (x.FuncInterf
 }
 
 // cql_MethodBodyWithCtFromFuncName generates model statements for MethodBodyWithCtFromFuncName
-func cql_MethodBodyWithCtFromFuncName(mdl *x.XModel, pathVersions []string) []Code {
+func cql_MethodBodyWithCtFromFuncName(mdl *x.XModel, pathVersions []string, rules []*x.ContentTypeRule) []Code {
 
 	// Assuming the validation has already been done:
 	mtdBodyWithCtFromFuncName := mdl.Methods.ByName(MethodBodyWithCtFromFuncName)
@@ -288,6 +284,82 @@ func cql_MethodBodyWithCtFromFuncName(mdl *x.XModel, pathVersions []string) []Co
 			}
 		}
 	}
+	pathCodez = append(pathCodez,
+		cql_StaticContentTypeMethods(b2tm, b2itm, pathVersions,
+			func(fn x.FuncInterface, methodQual *x.FuncQualifier) Code {
+				_, code := GetBodySetterFuncQualifierCodeElements(methodQual)
+				return Id("bodyNode").Eq().Add(code).
+					And().
+					Id("contentTypeString").Eq().Lit(x.InferContentType(rules, methodQual, fn.GetFunc().Name))
+			},
+		)...,
+	)
+
+	return pathCodez
+}
+
+// cql_MethodBodyWithCtFromRenderer generates model statements for MethodBodyWithCtFromRenderer;
+// the content-type is bound according to the concrete type of the renderer argument.
+func cql_MethodBodyWithCtFromRenderer(mdl *x.XModel, pathVersions []string, rules []*x.ContentTypeRule) []Code {
+
+	// Assuming the validation has already been done:
+	mtdBodyWithCtFromRenderer := mdl.Methods.ByName(MethodBodyWithCtFromRenderer)
+	if len(mtdBodyWithCtFromRenderer.Selectors) == 0 {
+		Infof("No selectors found for %q method.", mtdBodyWithCtFromRenderer.Name)
+		return nil
+	}
+
+	_, b2tm, b2itm, err := x.GroupFuncSelectors(mtdBodyWithCtFromRenderer)
+	if err != nil {
+		Fatalf("Error while GroupFuncSelectors: %s", err)
+	}
+
+	return cql_StaticContentTypeMethods(b2tm, b2itm, pathVersions,
+		func(fn x.FuncInterface, methodQual *x.FuncQualifier) Code {
+			_, code := GetBodySetterFuncQualifierCodeElements(methodQual)
+
+			rendererCodez := make([]Code, 0)
+			for _, renderer := range getRendererTypes(rules, fn, methodQual) {
+				rendererCodez = append(rendererCodez,
+					DoGroup(func(st *Group) {
+						st.Commentf("Renderer type: %s.%s", renderer.PkgPath, renderer.TypeName)
+						st.Exists(
+							List(
+								Id("Type").Id("rendererType"),
+							),
+							Id("rendererType").Dot("hasQualifiedName").Call(
+								x.CqlFormatPackagePath(renderer.PkgPath),
+								Lit(renderer.TypeName),
+							),
+							Id("bodyNode").Dot("getType").Call().Eq().Id("rendererType").
+								Or().
+								Id("bodyNode").Dot("getType").Call().Eq().Id("rendererType").Dot("getPointerType").Call(),
+						)
+						st.And()
+						st.Id("contentTypeString").Eq().Lit(renderer.ContentType)
+					}),
+				)
+			}
+
+			return Id("bodyNode").Eq().Add(code).
+				And().
+				Parens(
+					Join(Or(), rendererCodez...),
+				)
+		},
+	)
+}
+
+// cql_StaticContentTypeMethods generates the statements for the provided type methods and interface methods,
+// binding methodName, bodySetterCall, and receiverNode; the code returned by gen
+// must bind bodyNode and contentTypeString for each method.
+func cql_StaticContentTypeMethods(
+	b2tm x.BasicToTypeIDToMethods,
+	b2itm x.BasicToInterfaceIDToMethods,
+	pathVersions []string,
+	gen func(fn x.FuncInterface, methodQual *x.FuncQualifier) Code,
+) []Code {
+	pathCodez := make([]Code, 0)
 	// Type methods:
 	{
 		addedCount := 0
@@ -354,14 +426,7 @@ func cql_MethodBodyWithCtFromFuncName(mdl *x.XModel, pathVersions []string) []Co
 
 											par.And()
 
-											{
-												_, code := GetBodySetterFuncQualifierCodeElements(methodQual)
-												par.Id("bodyNode").Eq().Add(code)
-											}
-
-											par.And()
-
-											par.Id("contentTypeString").Eq().Lit(x.GuessContentTypeFromName(fn.GetFunc().Name))
+											par.Add(gen(fn, methodQual))
 										},
 									)
 								}
@@ -455,14 +520,7 @@ func cql_MethodBodyWithCtFromFuncName(mdl *x.XModel, pathVersions []string) []Co
 
 											par.And()
 
-											{
-												_, code := GetBodySetterFuncQualifierCodeElements(methodQual)
-												par.Id("bodyNode").Eq().Add(code)
-											}
-
-											par.And()
-
-											par.Id("contentTypeString").Eq().Lit(x.GuessContentTypeFromName(fn.GetFunc().Name))
+											par.Add(gen(fn, methodQual))
 										},
 									)
 								}
@@ -188,7 +188,7 @@ func (han *Handler) GenerateGo(parentDir string, mdl *x.XModel) error {
 		pathCodez := make([]Code, 0)
 		{
 			{
-				pc := go_MethodBodyWithCtFromFuncName(mdl, file, pathVersion)
+				pc := go_MethodBodyWithCtFromFuncName(mdl, file, pathVersion, mdl.ContentTypeRules())
 				pathCodez = append(pathCodez, pc...)
 			}
 			{
@@ -199,6 +199,10 @@ func (han *Handler) GenerateGo(parentDir string, mdl *x.XModel) error {
 				pc := go_body_setter(mdl, file, pathVersion)
 				pathCodez = append(pathCodez, pc...)
 			}
+			{
+				pc := go_MethodBodyWithCtFromRenderer(mdl, file, pathVersion, mdl.ContentTypeRules())
+				pathCodez = append(pathCodez, pc...)
+			}
 		}
 
 		{
@@ -249,7 +253,7 @@ func (han *Handler) GenerateGo(parentDir string, mdl *x.XModel) error {
 	return nil
 }
 
-func go_MethodBodyWithCtFromFuncName(mdl *x.XModel, file *File, pathVersion string) []Code {
+func go_MethodBodyWithCtFromFuncName(mdl *x.XModel, file *File, pathVersion string, rules []*x.ContentTypeRule) []Code {
 
 	method := mdl.Methods.ByName(MethodBodyWithCtFromFuncName)
 
@@ -284,6 +288,7 @@ func go_MethodBodyWithCtFromFuncName(mdl *x.XModel, file *File, pathVersion stri
 							groupCase.Comment(thing.Signature)
 
 							blocksOfCases := par_MethodBodyWithCtFromFuncName_generateGoTestBlock(
+								rules,
 								file,
 								thing,
 								funcQual,
@@ -336,6 +341,7 @@ func go_MethodBodyWithCtFromFuncName(mdl *x.XModel, file *File, pathVersion stri
 								groupCase.Comment(thing.Func.Signature)
 
 								blocksOfCases := par_MethodBodyWithCtFromFuncName_generateGoTestBlock(
+									rules,
 									file,
 									thing,
 									methodQual,
@@ -394,6 +400,7 @@ func go_MethodBodyWithCtFromFuncName(mdl *x.XModel, file *File, pathVersion stri
 
 								converted := feparser.FEIToFET(thing)
 								blocksOfCases := par_MethodBodyWithCtFromFuncName_generateGoTestBlock(
+									rules,
 									file,
 									converted,
 									methodQual,
@@ -424,7 +431,7 @@ func go_MethodBodyWithCtFromFuncName(mdl *x.XModel, file *File, pathVersion stri
 	return codez
 }
 
-func par_MethodBodyWithCtFromFuncName_generateGoTestBlock(file *File, fn x.FuncInterface, qual *x.FuncQualifier) []Code {
+func par_MethodBodyWithCtFromFuncName_generateGoTestBlock(rules []*x.ContentTypeRule, file *File, fn x.FuncInterface, qual *x.FuncQualifier) []Code {
 	childBlocks := make([]Code, 0)
 
 	indexes := x.MustPosToRelativeParamIndexes(fn, qual.Pos)
@@ -432,6 +439,7 @@ func par_MethodBodyWithCtFromFuncName_generateGoTestBlock(file *File, fn x.FuncI
 	childBlock := par_MethodBodyWithCtFromFuncName_generate(
 		file,
 		fn,
+		x.InferContentType(rules, qual, fn.GetFunc().Name),
 		indexes,
 	)
 	{
@@ -447,7 +455,7 @@ func par_MethodBodyWithCtFromFuncName_generateGoTestBlock(file *File, fn x.FuncI
 
 ////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
 
-func par_MethodBodyWithCtFromFuncName_generate(file *File, fn x.FuncInterface, indexes []int) *Statement {
+func par_MethodBodyWithCtFromFuncName_generate(file *File, fn x.FuncInterface, contentType string, indexes []int) *Statement {
 
 	for _, index := range indexes {
 		in := fn.GetFunc().Parameters[index]
@@ -503,7 +511,202 @@ func par_MethodBodyWithCtFromFuncName_generate(file *File, fn x.FuncInterface, i
 					}
 
 				},
-			).Add(NewTag(TagContentType(x.GuessContentTypeFromName(fn.GetFunc().Name)), TagResponseBody(varNames...)))
+			).Add(NewTag(TagContentType(contentType), TagResponseBody(varNames...)))
+
+		})
+	return code
+}
+
+////////////////
+
+func go_MethodBodyWithCtFromRenderer(mdl *x.XModel, file *File, pathVersion string, rules []*x.ContentTypeRule) []Code {
+
+	method := mdl.Methods.ByName(MethodBodyWithCtFromRenderer)
+
+	if len(method.Selectors) == 0 {
+		Infof("No selectors found for %q method.", method.Name)
+		return nil
+	}
+
+	// NOTE: only type methods and interface methods are supported (see Validate).
+	_, b2tm, b2itm, err := x.GroupFuncSelectors(method)
+	if err != nil {
+		Fatalf("Error while GroupFuncSelectors: %s", err)
+	}
+
+	codez := make([]Code, 0)
+	{
+		codezTypeMethods := make([]Code, 0)
+		b2tm.IterValid(pathVersion,
+			func(receiverTypeID string, methodQualifiers x.FuncQualifierSlice) {
+
+				qual := methodQualifiers[0]
+				// Find receiver type:
+				typ := x.FindType(qual.Path, qual.Version, receiverTypeID)
+				if typ == nil {
+					Fatalf("Type not found: %q", receiverTypeID)
+				}
+
+				gogentools.ImportPackage(file, typ.PkgPath, typ.PkgName)
+
+				code := BlockFunc(
+					func(groupCase *Group) {
+
+						for _, methodQual := range methodQualifiers {
+							fn := x.GetFuncByQualifier(methodQual)
+							thing := fn.(*feparser.FETypeMethod)
+							x.AddImportsFromFunc(file, fn)
+
+							{
+								if AllFalse(methodQual.Pos...) {
+									continue
+								}
+								groupCase.Comment(thing.Func.Signature)
+
+								groupCase.Add(
+									par_MethodBodyWithCtFromRenderer_generateGoTestBlock(
+										rules,
+										file,
+										thing,
+										methodQual,
+									)...,
+								)
+							}
+
+						}
+					})
+				codezTypeMethods = append(codezTypeMethods,
+					Commentf("Response body is set via a method call on the %s type (the content-type depends on the type of the renderer).", typ.QualifiedName).
+						Line().
+						Add(code),
+				)
+			})
+		if len(codezTypeMethods) > 0 {
+			codez = append(codez,
+				Comment("Response body is set via a method call (the content-type depends on the type of the renderer).").
+					Line().
+					Block(codezTypeMethods...),
+			)
+		}
+	}
+
+	{
+		codezIfaceMethods := make([]Code, 0)
+		b2itm.IterValid(pathVersion,
+			func(receiverTypeID string, methodQualifiers x.FuncQualifierSlice) {
+				qual := methodQualifiers[0]
+				// Find receiver type:
+				typ := x.FindType(qual.Path, qual.Version, receiverTypeID)
+				if typ == nil {
+					Fatalf("Type not found: %q", receiverTypeID)
+				}
+
+				gogentools.ImportPackage(file, typ.PkgPath, typ.PkgName)
+
+				code := BlockFunc(
+					func(groupCase *Group) {
+
+						for _, methodQual := range methodQualifiers {
+							fn := x.GetFuncByQualifier(methodQual)
+							thing := fn.(*feparser.FEInterfaceMethod)
+							x.AddImportsFromFunc(file, fn)
+
+							{
+								if AllFalse(methodQual.Pos...) {
+									continue
+								}
+								groupCase.Comment(thing.Func.Signature)
+
+								converted := feparser.FEIToFET(thing)
+								groupCase.Add(
+									par_MethodBodyWithCtFromRenderer_generateGoTestBlock(
+										rules,
+										file,
+										converted,
+										methodQual,
+									)...,
+								)
+							}
+						}
+					})
+				codezIfaceMethods = append(codezIfaceMethods,
+					Commentf("Response body is set via a method call on the %s interface (the content-type depends on the type of the renderer).", typ.QualifiedName).
+						Line().
+						Add(code),
+				)
+			})
+
+		if len(codezIfaceMethods) > 0 {
+			codez = append(codez,
+				Comment("Response body is set via an interface method call (the content-type depends on the type of the renderer).").
+					Line().
+					Block(codezIfaceMethods...),
+			)
+		}
+	}
+	return codez
+}
+
+// par_MethodBodyWithCtFromRenderer_generateGoTestBlock generates a test block
+// for each renderer type that can be passed to the func.
+func par_MethodBodyWithCtFromRenderer_generateGoTestBlock(rules []*x.ContentTypeRule, file *File, fn x.FuncInterface, qual *x.FuncQualifier) []Code {
+	childBlocks := make([]Code, 0)
+
+	indexes := x.MustPosToRelativeParamIndexes(fn, qual.Pos)
+
+	for _, renderer := range getRendererTypes(rules, fn, qual) {
+		childBlocks = append(childBlocks,
+			par_MethodBodyWithCtFromRenderer_generate(
+				file,
+				fn,
+				renderer,
+				indexes[0],
+			),
+		)
+	}
+	if len(childBlocks) == 0 {
+		Warnf(Sf("NOTHING GENERATED; no renderer types found for %s", fn.GetFunc().Signature))
+	}
+
+	return childBlocks
+}
+
+func par_MethodBodyWithCtFromRenderer_generate(file *File, fn x.FuncInterface, renderer *rendererType, index int) *Statement {
+
+	varName := gogentools.NewNameWithPrefix(feparser.NewLowerTitleName("body", renderer.TypeName))
+
+	code := BlockFunc(
+		func(groupCase *Group) {
+
+			groupCase.Commentf("Renderer type: %s.%s", renderer.PkgPath, renderer.TypeName)
+
+			typ := renderer.GetOriginal().GetType()
+			if renderer.IsPtr {
+				typ = types.NewPointer(typ)
+			}
+			ComposeTypeAssertion(file, groupCase, varName, typ, false)
+
+			groupCase.Var().Id("rece").Qual(fn.GetReceiver().PkgPath, fn.GetReceiver().TypeName)
+
+			gogentools.ImportPackage(file, fn.GetFunc().PkgPath, fn.GetFunc().PkgName)
+
+			groupCase.Id("rece").Dot(fn.GetFunc().Name).CallFunc(
+				func(call *Group) {
+
+					tpFun := fn.GetFunc().GetOriginal().GetType().(*types.Signature)
+
+					zeroVals := gogentools.ScanTupleOfZeroValues(file, tpFun.Params(), fn.GetFunc().GetOriginal().IsVariadic())
+
+					for i, zero := range zeroVals {
+						if i == index {
+							call.Id(varName)
+						} else {
+							call.Add(zero)
+						}
+					}
+
+				},
+			).Add(NewTag(TagContentType(renderer.ContentType), TagResponseBody(varName)))
 
 		})
 	return code
@@ -2,12 +2,22 @@ package responsebody
 
 import (
 	"fmt"
+	"go/types"
 
 	"github.com/gagliardetto/codemill/x"
+	. "github.com/gagliardetto/utilz"
 )
 
 // NOTES:
 // - Assumes all selectors are added because they work in the same context.
+// - The content-type of MethodBodyWithCtFromFuncName funcs is the one set on the selector,
+//   or the one inferred from the func name with the content-type rules of the spec (see x.InferContentType).
+// - The renderer types of MethodBodyWithCtFromRenderer funcs are looked up in all the loaded packages;
+//   add the package that defines them to the Preload list of the spec if it's not otherwise selected.
+// - The content-type of a renderer type is the one set on the selector for that type (see RendererContentTypes),
+//   or the one inferred from the type name with the content-type rules of the spec.
+// - Models saved with the previous 4 methods are migrated on load (see x.XModel.Migrate),
+//   which adds the (empty) MethodBodyWithCtFromRenderer method.
 
 const (
 	Kind x.ModelKind = "HTTP::ResponseBody"
@@ -22,6 +32,8 @@ const (
 	MethodBodyWithCtIsCt   = "{ct:Param, body:Param} <- $ct"   // Content-type parameter; body will be from another parameter of the same function.
 
 	MethodBody = "{body:Param} <- $body" // Body parameter; the function only allows to specify a body parameter and does not set content-type in any way.
+
+	MethodBodyWithCtFromRenderer = "{ct:Inferred, renderer:Param} <- $renderer" // Renderer parameter; the content-type will be inferred from the concrete type of the renderer.
 )
 
 //
@@ -36,6 +48,8 @@ func (han *Handler) ScavengeMethods() []*x.XMethod {
 		MethodBodyWithCtIsCt,   // "Coupled 2/2: Select body parameter of func; content-type will be selected from another parameter of the same function.",
 
 		MethodBody, // "Select body param of any function that allows to set the body but does NOT determine the content-type.",
+
+		MethodBodyWithCtFromRenderer, // "Select the renderer (interface) parameter of a method; the content-type will be inferred from the concrete type of the renderer.",
 	)
 }
 func (han *Handler) Validate(mdl *x.XModel) error {
@@ -43,7 +57,6 @@ func (han *Handler) Validate(mdl *x.XModel) error {
 	if len(mdl.Methods) != defaultMthNum {
 		return fmt.Errorf("wrong number of methods; expected %v, got %v", defaultMthNum, len(mdl.Methods))
 	}
-	// TODO: validate
 	{
 		for i, must := range han.ScavengeMethods() {
 			if mdl.Methods[i].Name != must.Name {
@@ -51,5 +64,123 @@ func (han *Handler) Validate(mdl *x.XModel) error {
 			}
 		}
 	}
+	mtdBodyWithCtFromFuncName := mdl.Methods.ByName(MethodBodyWithCtFromFuncName)
+	mtdBodyWithCtIsBody := mdl.Methods.ByName(MethodBodyWithCtIsBody)
+	mtdBodyWithCtIsCt := mdl.Methods.ByName(MethodBodyWithCtIsCt)
+	mtdBodyWithCtFromRenderer := mdl.Methods.ByName(MethodBodyWithCtFromRenderer)
+	{
+		// The content-type must be inferable for each func:
+		for _, sel := range mtdBodyWithCtFromFuncName.Selectors {
+			qual := sel.GetFuncQualifier()
+			if qual == nil {
+				return fmt.Errorf("method %s supports only func selectors", mtdBodyWithCtFromFuncName.Name)
+			}
+			fn := x.GetFuncByQualifier(qual)
+			if x.InferContentType(mdl.ContentTypeRules(), qual, fn.GetFunc().Name) == "" {
+				return fmt.Errorf(
+					"%s: cannot infer the content-type from the func name; add a content-type rule to the spec, or set the content-type of the selector",
+					qual.ID,
+				)
+			}
+		}
+	}
+	{
+		if err := x.ValidateParams(mtdBodyWithCtIsBody, false); err != nil {
+			return err
+		}
+		if err := x.ValidateParams(mtdBodyWithCtIsCt, true); err != nil {
+			return err
+		}
+		// Each func selected in one of the coupled methods
+		// must also be selected in the other one,
+		// and the body and the content-type must be different parameters:
+		if err := x.ValidateDistinctParams(mtdBodyWithCtIsBody, mtdBodyWithCtIsCt); err != nil {
+			return err
+		}
+		if err := x.ValidateCoupled(mtdBodyWithCtIsCt, mtdBodyWithCtIsBody); err != nil {
+			return err
+		}
+	}
+	{
+		if err := x.ValidateParams(mtdBodyWithCtFromRenderer, true); err != nil {
+			return err
+		}
+		for _, sel := range mtdBodyWithCtFromRenderer.Selectors {
+			qual := sel.GetFuncQualifier()
+			fn := x.GetFuncByQualifier(qual)
+			if fn.GetReceiver() == nil {
+				return fmt.Errorf("%s: method %s supports only type methods and interface methods", qual.ID, mtdBodyWithCtFromRenderer.Name)
+			}
+			iface, ok := getRendererInterface(fn, qual)
+			if !ok {
+				return fmt.Errorf("%s: the renderer parameter must be of an interface type", qual.ID)
+			}
+			{
+				// The renderer types with a content-type set on the selector must implement the renderer interface:
+				names := make([]string, 0)
+				for _, typ := range x.FindImplementingTypes(iface) {
+					names = append(names, rendererTypeName(typ))
+				}
+				for name := range qual.RendererContentTypes {
+					if !SliceContains(names, name) {
+						return fmt.Errorf("%s: renderer type %s not found (or it does not implement the renderer interface)", qual.ID, name)
+					}
+				}
+			}
+			if len(getRendererTypes(mdl.ContentTypeRules(), fn, qual)) == 0 {
+				return fmt.Errorf(
+					"%s: no renderer types with a known content-type found; make sure the package of the renderer types is loaded (e.g. add it to Preload), and set their content-type on the selector or add a content-type rule to the spec",
+					qual.ID,
+				)
+			}
+		}
+	}
 	return nil
 }
+
+// rendererType is a concrete type that implements a renderer interface,
+// along with the content-type set when rendering it.
+type rendererType struct {
+	*x.ImplementingType
+	ContentType string
+}
+
+// getRendererInterface returns the interface type of the renderer parameter selected in the qualifier.
+func getRendererInterface(fn x.FuncInterface, qual *x.FuncQualifier) (*types.Interface, bool) {
+	indexes := x.MustPosToRelativeParamIndexes(fn, qual.Pos)
+	param := fn.GetFunc().Parameters[indexes[0]]
+	iface, ok := param.GetOriginal().GetType().Underlying().(*types.Interface)
+	return iface, ok
+}
+
+// rendererTypeName returns the name of a renderer type, as used in the keys of RendererContentTypes.
+func rendererTypeName(typ *x.ImplementingType) string {
+	return typ.PkgPath + "." + typ.TypeName
+}
+
+// getRendererTypes returns the loaded types that implement the renderer interface,
+// along with their content-type: the one set on the selector for the type, if any;
+// otherwise, the one inferred from their name with the provided rules.
+// Types whose content-type cannot be inferred are skipped.
+func getRendererTypes(rules []*x.ContentTypeRule, fn x.FuncInterface, qual *x.FuncQualifier) []*rendererType {
+	iface, ok := getRendererInterface(fn, qual)
+	if !ok {
+		return nil
+	}
+	res := make([]*rendererType, 0)
+	for _, typ := range x.FindImplementingTypes(iface) {
+		contentType := qual.RendererContentTypes[rendererTypeName(typ)]
+		if contentType == "" {
+			contentType = x.InferContentType(rules, nil, typ.TypeName)
+		}
+		if contentType == "" {
+			Warnf("%s: cannot infer the content-type of renderer %s; skipping", qual.ID, rendererTypeName(typ))
+			continue
+		}
+		res = append(res, &rendererType{
+			ImplementingType: typ,
+			ContentType:      contentType,
+		})
+	}
+	return res
+}
@@ -0,0 +1,98 @@
+package responsewriter
+
+import (
+	"sort"
+
+	"github.com/gagliardetto/codebox/scanner"
+	"github.com/gagliardetto/codemill/x"
+	. "github.com/gagliardetto/cqlgen/jen"
+	. "github.com/gagliardetto/utilz"
+)
+
+func (han *Handler) GenerateCodeQL(impAdder x.ImportAdder, mdl *x.XModel, moduleGroup *Group) error {
+	if err := mdl.Validate(); err != nil {
+		return err
+	}
+	if err := han.Validate(mdl); err != nil {
+		return err
+	}
+
+	// Assuming the validation has already been done:
+	self := mdl.Methods[0]
+
+	if len(self.Selectors) == 0 {
+		Infof("No selectors found for %q method.", self.Name)
+		return nil
+	}
+
+	b2typ, err := x.GroupTypeSelectors(self)
+	if err != nil {
+		Fatalf("Error while GroupTypeSelectors: %s", err)
+	}
+
+	className := mdl.Name
+
+	moduleGroup.Doc("Models HTTP response writers.")
+	moduleGroup.Private().Class().Id(className).Extends().List(Qual("HTTP::ResponseWriter", "Range")).
+		BlockFunc(func(classGr *Group) {
+			classGr.Id(className).Call().BlockFunc(func(metGr *Group) {
+				index := 0
+				keys := func(v x.BasicToTypes) []string {
+					res := make([]string, 0)
+					for key := range v {
+						res = append(res, key)
+					}
+					sort.Strings(res)
+					return res
+				}(b2typ)
+				for _, pathVersion := range keys {
+					typeQualifiers, ok := b2typ[pathVersion]
+					if !ok {
+						continue
+					}
+					if index > 0 {
+						metGr.Or()
+					}
+					index++
+					path, _ := scanner.SplitPathVersion(pathVersion)
+
+					metGr.Comment("Types of package: " + pathVersion)
+					metGr.Exists(
+						List(
+							Id("Type").Id("t"),
+						),
+						DoGroup(func(st *Group) {
+							var typeNames []string
+							for _, qual := range typeQualifiers {
+								// Find the type:
+								typ := x.FindType(qual.Path, qual.Version, qual.ID)
+								if typ == nil {
+									Fatalf("Type not found: %q", qual.ID)
+								}
+								typeNames = append(typeNames, typ.TypeName)
+							}
+
+							sort.Strings(typeNames)
+
+							st.Id("t").Dot("hasQualifiedName").Call(
+								x.CqlFormatPackagePath(path),
+								StringsToSetOrLit(typeNames...),
+							)
+						}),
+						DoGroup(func(st *Group) {
+							st.This().Dot("getType").Call().Eq().Id("t").
+								Or().
+								This().Dot("getType").Call().Eq().Id("t").Dot("getPointerType").Call()
+						}),
+					)
+				}
+			})
+
+			classGr.Override().Id("DataFlow::Node").Id("getANode").Call().BlockFunc(
+				func(overrideBlockGroup *Group) {
+					overrideBlockGroup.Id("result").Eq().This().Dot("getARead").Call().Dot("getASuccessor*").Call()
+				})
+		})
+
+	return nil
+}
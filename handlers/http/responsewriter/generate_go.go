@@ -0,0 +1,206 @@
+package responsewriter
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/dave/jennifer/jen"
+	"github.com/gagliardetto/codebox/gogentools"
+	"github.com/gagliardetto/codemill/x"
+	"github.com/gagliardetto/feparser"
+	. "github.com/gagliardetto/utilz"
+)
+
+var (
+	IncludeCommentsInGeneratedGo bool
+	GenerateBoilerplate          bool
+)
+
+const (
+	// NOTE: hardcoded inside TestQueryContent const.
+	InlineExpectationsTestTag = "$responseWriter" // Must start with a $ sign.
+)
+
+func Tag(varName string) Code {
+	return Comment(InlineExpectationsTestTag + "=" + varName)
+}
+
+const (
+	TestQueryContent = `
+import go
+import TestUtilities.InlineExpectationsTest
+
+class ResponseWriterTest extends InlineExpectationsTest {
+  ResponseWriterTest() { this = "ResponseWriterTest" }
+
+  override string getARelevantTag() { result = "responseWriter" }
+
+  override predicate hasActualResult(string file, int line, string element, string tag, string value) {
+    tag = "responseWriter" and
+    exists(HTTP::ResponseWriter rw |
+      rw.getDeclaration().hasLocationInfo(file, line, _, _, _) and
+      element = rw.toString() and
+      value = rw.getName()
+    )
+  }
+}
+`
+)
+
+func NewTestFile(includeBoilerplace bool) *File {
+	file := NewFile("main")
+	// Set a prefix to avoid collision between variable names and packages:
+	file.PackagePrefix = "cql"
+	// Add comment to file:
+	file.HeaderComment("Code generated by https://github.com/gagliardetto. DO NOT EDIT.")
+
+	if includeBoilerplace {
+		file.PackageComment("//go:generate depstubber --vendor --auto")
+		{
+			// main function:
+			file.Func().Id("main").Params().Block()
+		}
+		{
+			// sink function:
+			code := Func().
+				Id("sink").
+				Params(Id("v").Op("...").Interface()).
+				Block()
+			file.Add(code.Line())
+		}
+	}
+	return file
+}
+
+func (han *Handler) GenerateGo(parentDir string, mdl *x.XModel) error {
+	if err := mdl.Validate(); err != nil {
+		return err
+	}
+	if err := han.Validate(mdl); err != nil {
+		return err
+	}
+
+	// Check if there are multiple versions of a same package:
+	mods := mdl.ListModules()
+	if x.HasMultiversion(mods) {
+		Ln(RedBG("Has multiversion"))
+	}
+	// If there are no multiple versions of the same module,
+	// that means we can save all the code to one file.
+	allInOneFile := !x.HasMultiversion(mods)
+
+	// Create the directory for the tests for this model:
+	outDir := filepath.Join(parentDir, feparser.NewCodeQlName(mdl.Name))
+	MustCreateFolderIfNotExists(outDir, os.ModePerm)
+
+	// Assuming the validation has already been done:
+	self := mdl.Methods[0]
+
+	if len(self.Selectors) == 0 {
+		Infof("No selectors found for %q method.", self.Name)
+		return nil
+	}
+
+	allPathVersions := mdl.ListAllPathVersions()
+
+	file := NewTestFile(GenerateBoilerplate)
+
+	for _, pathVersion := range allPathVersions {
+		if !allInOneFile {
+			// Reset file:
+			file = NewTestFile(GenerateBoilerplate)
+		}
+		codez := make([]Code, 0)
+
+		b2typ, err := x.GroupTypeSelectors(self)
+		if err != nil {
+			Fatalf("Error while GroupTypeSelectors: %s", err)
+		}
+
+		{
+			typeQualifiers, ok := b2typ[pathVersion]
+			if ok {
+				code := BlockFunc(
+					func(groupCase *Group) {
+						for _, qual := range typeQualifiers {
+							// Find the type:
+							typ := x.FindType(qual.Path, qual.Version, qual.ID)
+							if typ == nil {
+								Fatalf("Type not found: %q", qual.ID)
+							}
+							gogentools.ImportPackage(file, typ.PkgPath, typ.PkgName)
+
+							typeVarName := gogentools.NewNameWithPrefix(feparser.NewLowerTitleName("rw", typ.TypeName))
+
+							groupCase.BlockFunc(
+								func(subGroup *Group) {
+									subGroup.Var().Id(typeVarName).Qual(typ.PkgPath, typ.TypeName).Add(Tag(typeVarName))
+									subGroup.Id("sink").Call(Id(typeVarName))
+								})
+						}
+					})
+				codez = append(codez,
+					Comment("Response writer types.").
+						Line().
+						Add(code),
+				)
+			}
+		}
+
+		{
+			file.Commentf("Package %s", pathVersion)
+			file.Func().Id(mdl.Name + "_" + feparser.FormatCodeQlName(pathVersion)).Params().Block(codez...)
+		}
+
+		if !allInOneFile {
+			pkgDstDirpath := filepath.Join(outDir, feparser.FormatID(mdl.Name, "For", feparser.FormatCodeQlName(pathVersion)))
+			MustCreateFolderIfNotExists(pkgDstDirpath, os.ModePerm)
+
+			assetFileName := feparser.FormatID(mdl.Name, "For", feparser.FormatCodeQlName(pathVersion)) + ".go"
+			if err := x.SaveGoFile(pkgDstDirpath, assetFileName, file); err != nil {
+				Fatalf("Error while saving go file: %s", err)
+			}
+
+			if err := x.WriteGoModFile(pkgDstDirpath, pathVersion); err != nil {
+				Fatalf("Error while saving go.mod file: %s", err)
+			}
+			if err := x.WriteCodeQLTestQuery(pkgDstDirpath, mdl.Name, TestQueryContent); err != nil {
+				Fatalf("Error while saving <name>.ql file: %s", err)
+			}
+			if err := x.WriteEmptyCodeQLDotExpectedFile(pkgDstDirpath, mdl.Name); err != nil {
+				Fatalf("Error while saving <name>.expected file: %s", err)
+			}
+		}
+	}
+
+	if allInOneFile {
+		pkgDstDirpath := outDir
+		MustCreateFolderIfNotExists(pkgDstDirpath, os.ModePerm)
+
+		assetFileName := feparser.FormatID(mdl.Name) + ".go"
+		if err := x.SaveGoFile(pkgDstDirpath, assetFileName, file); err != nil {
+			Fatalf("Error while saving go file: %s", err)
+		}
+
+		if err := x.WriteGoModFile(pkgDstDirpath, allPathVersions...); err != nil {
+			Fatalf("Error while saving go.mod file: %s", err)
+		}
+		if err := x.WriteCodeQLTestQuery(pkgDstDirpath, mdl.Name, TestQueryContent); err != nil {
+			Fatalf("Error while saving <name>.ql file: %s", err)
+		}
+		if err := x.WriteEmptyCodeQLDotExpectedFile(pkgDstDirpath, mdl.Name); err != nil {
+			Fatalf("Error while saving <name>.expected file: %s", err)
+		}
+	}
+	return nil
+}
+
+// Comments adds comments to a Group (if enabled), and returns the group.
+func Comments(group *Group, comments ...string) *Group {
+	if IncludeCommentsInGeneratedGo {
+		for _, comment := range comments {
+			group.Line().Comment(comment)
+		}
+	}
+	return group
+}
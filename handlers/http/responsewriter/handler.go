@@ -0,0 +1,45 @@
+package responsewriter
+
+import (
+	"fmt"
+
+	"github.com/gagliardetto/codemill/x"
+)
+
+const (
+	Kind x.ModelKind = "HTTP::ResponseWriter"
+)
+
+type Handler struct{}
+
+// NOTE:
+// - Only types can be selected; any variable of the selected type
+//   (or of a pointer to it) is considered a response writer.
+
+const (
+	MethodSelf = "{writer:[]Type} <- $writer"
+)
+
+//
+func (han *Handler) ScavengeMethods() []*x.XMethod {
+	return []*x.XMethod{
+		{
+			Name:      MethodSelf,
+			Selectors: []*x.XSelector{},
+		},
+	}
+}
+func (han *Handler) Validate(mdl *x.XModel) error {
+	if len(mdl.Methods) != 1 {
+		return fmt.Errorf("wrong number of methods; expected 1, got %v", len(mdl.Methods))
+	}
+	if mdl.Methods[0].Name != MethodSelf {
+		return fmt.Errorf("First method is not called %s", MethodSelf)
+	}
+	for _, sel := range mdl.Methods[0].Selectors {
+		if sel.Kind != x.SelectorKindType {
+			return fmt.Errorf("selector kind %q is not supported; only types can be selected", sel.Kind)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+package templateexecution
+
+import (
+	"github.com/gagliardetto/codemill/x"
+	. "github.com/gagliardetto/cqlgen/jen"
+	"github.com/gagliardetto/feparser"
+	. "github.com/gagliardetto/utilz"
+)
+
+func (han *Handler) GenerateCodeQL(impAdder x.ImportAdder, mdl *x.XModel, rootModuleGroup *Group) error {
+	if err := mdl.Validate(); err != nil {
+		return err
+	}
+	if err := han.Validate(mdl); err != nil {
+		return err
+	}
+
+	// Assuming the validation has already been done:
+	mtdTemplateName := mdl.Methods.ByName(MethodTemplateName)
+	mtdTemplateData := mdl.Methods.ByName(MethodTemplateData)
+
+	if len(mtdTemplateName.Selectors) == 0 {
+		Infof("No selectors found for %q method.", mtdTemplateName.Name)
+		return nil
+	}
+	{
+		// Add imports:
+		//impAdder.Import("DataFlow::PathGraph")
+	}
+
+	className := mdl.Name
+	allPathVersions := mdl.ListAllPathVersions()
+
+	{
+		funcModelsClassName := feparser.NewCodeQlName(className)
+
+		// NOTE: the name and the data are coupled, so both
+		// disjunctions bind `call` to the same call.
+		templateNameCode, templateNameCount := x.CqlCallTargets(allPathVersions, mtdTemplateName, "call", "Template execution models", x.CqlBindArgument("call", "templateNameNode"))
+		templateDataCode, _ := x.CqlCallTargets(allPathVersions, mtdTemplateData, "call", "Template execution models",
+			func(fn x.FuncInterface, dataQual *x.FuncQualifier) Code {
+				nameQual := mtdTemplateName.GetFuncSelector(dataQual.Path, dataQual.Version, dataQual.ID)
+				selected := append(
+					x.MustPosToRelativeParamIndexes(fn, nameQual.Pos),
+					x.MustPosToRelativeParamIndexes(fn, dataQual.Pos)...,
+				)
+				return x.CqlBindArgument("call", "this")(fn, dataQual).
+					And().
+					Add(cql_ResponseWriterNode("call", fn, selected))
+			},
+		)
+
+		tmp := DoGroup(func(tempFuncsModel *Group) {
+			tempFuncsModel.Doc("Models HTTP ResponseBody where the body is the data used to render an HTML template (by an engine that does not escape it).")
+			tempFuncsModel.Private().Class().Id(funcModelsClassName).Extends().List(
+				Id("HTTP::ResponseBody::Range"),
+			).BlockFunc(
+				func(funcModelsClassGroup *Group) {
+					funcModelsClassGroup.String().Id("package").Semicolon().Line()
+					funcModelsClassGroup.Id("DataFlow::CallNode").Id("call").Semicolon().Line()
+					funcModelsClassGroup.Id("DataFlow::Node").Id("templateNameNode").Semicolon().Line()
+					funcModelsClassGroup.Id("DataFlow::Node").Id("receiverNode").Semicolon().Line()
+
+					funcModelsClassGroup.Id(funcModelsClassName).Call().Block(
+						Parens(templateNameCode).And().Parens(templateDataCode),
+					)
+
+					funcModelsClassGroup.Override().Id("string").Id("getAContentType").Call().BlockFunc(
+						func(overrideBlockGroup *Group) {
+							overrideBlockGroup.Id("result").Eq().Lit("text/html")
+						})
+
+					funcModelsClassGroup.Override().Id("HTTP::ResponseWriter").Id("getResponseWriter").Call().BlockFunc(
+						func(overrideBlockGroup *Group) {
+							overrideBlockGroup.Id("result").Dot("getANode").Call().Eq().Id("receiverNode")
+						})
+				})
+		})
+		if EngineEscapes(mdl) {
+			// The data is escaped by the template engine,
+			// so it is not modeled as an HTML response body.
+			Infof("The template engine of %q escapes the data; no response body modeled.", mdl.Name)
+		} else if templateNameCount > 0 {
+			rootModuleGroup.Add(tmp)
+		}
+	}
+
+	return nil
+}
+
+// cql_ResponseWriterNode binds the receiverNode to the receiver of the call;
+// for a func without a receiver, it binds it to the arguments that are not selected
+// (i.e. that are neither the template name nor the data).
+func cql_ResponseWriterNode(callName string, fn x.FuncInterface, selected []int) Code {
+	if fn.GetReceiver() != nil {
+		return Id("receiverNode").Eq().Id(callName).Dot("getReceiver").Call()
+	}
+	others := make([]int, 0)
+	for index := range fn.GetFunc().Parameters {
+		if !IntSliceContains(selected, index) {
+			others = append(others, index)
+		}
+	}
+	if len(others) == 0 {
+		// No argument can be the response writer; bind the call itself,
+		// so that no response writer is found:
+		return Id("receiverNode").Eq().Id(callName)
+	}
+	return Id("receiverNode").Eq().Id(callName).Dot("getArgument").Call(IntsToSetOrLit(others...))
+}
@@ -0,0 +1,484 @@
+package templateexecution
+
+import (
+	"go/types"
+	"os"
+	"path/filepath"
+
+	. "github.com/dave/jennifer/jen"
+	"github.com/gagliardetto/codebox/gogentools"
+	"github.com/gagliardetto/codemill/x"
+	"github.com/gagliardetto/feparser"
+	. "github.com/gagliardetto/utilz"
+)
+
+var (
+	GenerateBoilerplate bool
+)
+
+const (
+	// NOTE: hardcoded inside TestQueryContent const.
+	InlineExpectationsTestTag = "$templateData" // Must start with a $ sign.
+)
+
+func Tag(vals ...string) Code {
+	tg := ""
+	for i, v := range vals {
+		if i > 0 {
+			tg += " "
+		}
+		tg += InlineExpectationsTestTag + "=" + v
+	}
+	return Comment(tg)
+}
+
+const (
+	TestQueryContent = `
+import go
+import TestUtilities.InlineExpectationsTest
+
+class TemplateExecutionTest extends InlineExpectationsTest {
+  TemplateExecutionTest() { this = "TemplateExecutionTest" }
+
+  override string getARelevantTag() { result = "templateData" }
+
+  override predicate hasActualResult(string file, int line, string element, string tag, string value) {
+    tag = "templateData" and
+    exists(HTTP::ResponseBody rb | rb.getAContentType() = "text/html" |
+      rb.hasLocationInfo(file, line, _, _, _) and
+      element = rb.toString() and
+      value = rb.toString()
+    )
+  }
+}
+`
+)
+
+func NewTestFile(includeBoilerplace bool) *File {
+	file := NewFile("main")
+	// Set a prefix to avoid collision between variable names and packages:
+	file.PackagePrefix = "cql"
+	// Add comment to file:
+	file.HeaderComment("Code generated by https://github.com/gagliardetto. DO NOT EDIT.")
+
+	if includeBoilerplace {
+		file.PackageComment("//go:generate depstubber --vendor --auto")
+		{
+			// main function:
+			file.Func().Id("main").Params().Block()
+		}
+		{
+			// The `source` function returns a new template name or data:
+			code := Func().
+				Id("source").
+				Params().
+				Interface().
+				Block(Return(Nil()))
+			file.Add(code.Line())
+		}
+	}
+	return file
+}
+
+var (
+	IncludeCommentsInGeneratedGo bool
+)
+
+func (han *Handler) GenerateGo(parentDir string, mdl *x.XModel) error {
+	if err := mdl.Validate(); err != nil {
+		return err
+	}
+	if err := han.Validate(mdl); err != nil {
+		return err
+	}
+
+	// Check if there are multiple versions of a same package:
+	mods := mdl.ListModules()
+	if x.HasMultiversion(mods) {
+		Ln(RedBG("Has multiversion"))
+	}
+	// If there are no multiple versions of the same module,
+	// that means we can save all the code to one file.
+	allInOneFile := !x.HasMultiversion(mods)
+
+	// Create the directory for the tests for this model:
+	outDir := filepath.Join(parentDir, feparser.NewCodeQlName(mdl.Name))
+	MustCreateFolderIfNotExists(outDir, os.ModePerm)
+
+	// Assuming the validation has already been done:
+	mtdTemplateName := mdl.Methods.ByName(MethodTemplateName)
+	mtdTemplateData := mdl.Methods.ByName(MethodTemplateData)
+
+	if len(mtdTemplateName.Selectors) == 0 {
+		Infof("No selectors found for %q method.", mtdTemplateName.Name)
+		return nil
+	}
+
+	allPathVersions := mdl.ListAllPathVersions()
+	escapes := EngineEscapes(mdl)
+
+	file := NewTestFile(GenerateBoilerplate)
+
+	b2feName, b2tmName, b2itmName, err := x.GroupFuncSelectors(mtdTemplateName)
+	if err != nil {
+		Fatalf("Error while GroupFuncSelectors: %s", err)
+	}
+	b2feData, b2tmData, b2itmData, err := x.GroupFuncSelectors(mtdTemplateData)
+	if err != nil {
+		Fatalf("Error while GroupFuncSelectors: %s", err)
+	}
+
+	for _, pathVersion := range allPathVersions {
+		if !allInOneFile {
+			// Reset file:
+			file = NewTestFile(GenerateBoilerplate)
+		}
+		codez := make([]Code, 0)
+
+		{
+			cont, ok := b2feName[pathVersion]
+			if ok && x.HasValidPos(cont...) {
+				addedCount := 0
+				code := BlockFunc(
+					func(groupCase *Group) {
+
+						for _, nameQual := range cont {
+							fn := x.GetFuncByQualifier(nameQual)
+							thing := fn.(*feparser.FEFunc)
+
+							x.AddImportsFromFunc(file, thing)
+
+							{
+								if AllFalse(nameQual.Pos...) {
+									continue
+								}
+								groupCase.Comment(thing.Signature)
+
+								dataQual := b2feData[pathVersion].ByBasicQualifier(nameQual.BasicQualifier)
+
+								blocksOfCases := generateGoTestBlock(
+									file,
+									thing,
+									nameQual,
+									dataQual,
+									escapes,
+								)
+								if len(blocksOfCases) == 1 {
+									groupCase.Add(blocksOfCases...)
+								} else {
+									groupCase.Block(blocksOfCases...)
+								}
+								addedCount++
+							}
+
+						}
+					})
+				if addedCount > 0 {
+					codez = append(codez,
+						Comment("Template execution via function call.").
+							Line().
+							Add(code),
+					)
+				}
+			}
+		}
+		{
+			codezTypeMethods := make([]Code, 0)
+			b2tmName.IterValid(pathVersion,
+				func(receiverTypeID string, methodQualifiers x.FuncQualifierSlice) {
+
+					qual := methodQualifiers[0]
+					// Find receiver type:
+					typ := x.FindType(qual.Path, qual.Version, receiverTypeID)
+					if typ == nil {
+						Fatalf("Type not found: %q", receiverTypeID)
+					}
+
+					gogentools.ImportPackage(file, typ.PkgPath, typ.PkgName)
+
+					code := BlockFunc(
+						func(groupCase *Group) {
+
+							for _, nameQual := range methodQualifiers {
+								fn := x.GetFuncByQualifier(nameQual)
+								thing := fn.(*feparser.FETypeMethod)
+								x.AddImportsFromFunc(file, fn)
+
+								{
+									if AllFalse(nameQual.Pos...) {
+										continue
+									}
+									groupCase.Comment(thing.Func.Signature)
+
+									dataQual := b2tmData[pathVersion][receiverTypeID].ByBasicQualifier(nameQual.BasicQualifier)
+
+									blocksOfCases := generateGoTestBlock(
+										file,
+										thing,
+										nameQual,
+										dataQual,
+										escapes,
+									)
+									if len(blocksOfCases) == 1 {
+										groupCase.Add(blocksOfCases...)
+									} else {
+										groupCase.Block(blocksOfCases...)
+									}
+								}
+
+							}
+						})
+					codezTypeMethods = append(codezTypeMethods,
+						Commentf("Template execution via method calls on %s.", typ.QualifiedName).
+							Line().
+							Add(code),
+					)
+				})
+			if len(codezTypeMethods) > 0 {
+				codez = append(codez,
+					Comment("Template execution via method calls.").
+						Line().
+						Block(codezTypeMethods...),
+				)
+			}
+		}
+
+		{
+			codezIfaceMethods := make([]Code, 0)
+			b2itmName.IterValid(pathVersion,
+				func(receiverTypeID string, methodQualifiers x.FuncQualifierSlice) {
+					qual := methodQualifiers[0]
+					// Find receiver type:
+					typ := x.FindType(qual.Path, qual.Version, receiverTypeID)
+					if typ == nil {
+						Fatalf("Type not found: %q", receiverTypeID)
+					}
+
+					gogentools.ImportPackage(file, typ.PkgPath, typ.PkgName)
+
+					code := BlockFunc(
+						func(groupCase *Group) {
+
+							for _, nameQual := range methodQualifiers {
+								fn := x.GetFuncByQualifier(nameQual)
+								thing := fn.(*feparser.FEInterfaceMethod)
+								x.AddImportsFromFunc(file, fn)
+
+								{
+									if AllFalse(nameQual.Pos...) {
+										continue
+									}
+									groupCase.Comment(thing.Func.Signature)
+
+									converted := feparser.FEIToFET(thing)
+
+									dataQual := b2itmData[pathVersion][receiverTypeID].ByBasicQualifier(nameQual.BasicQualifier)
+
+									blocksOfCases := generateGoTestBlock(
+										file,
+										converted,
+										nameQual,
+										dataQual,
+										escapes,
+									)
+									if len(blocksOfCases) == 1 {
+										groupCase.Add(blocksOfCases...)
+									} else {
+										groupCase.Block(blocksOfCases...)
+									}
+								}
+							}
+						})
+					codezIfaceMethods = append(codezIfaceMethods,
+						Commentf("Template execution via method calls on %s interface.", typ.QualifiedName).
+							Line().
+							Add(code),
+					)
+				})
+
+			if len(codezIfaceMethods) > 0 {
+				codez = append(codez,
+					Comment("Template execution via interface method calls.").
+						Line().
+						Block(codezIfaceMethods...),
+				)
+			}
+		}
+
+		{
+			file.Commentf("Package %s", pathVersion)
+			file.Func().Id(mdl.Name + "_" + feparser.FormatCodeQlName(pathVersion)).Params().Block(codez...)
+		}
+
+		if !allInOneFile {
+			pkgDstDirpath := filepath.Join(outDir, feparser.FormatID(mdl.Name, "For", feparser.FormatCodeQlName(pathVersion)))
+			MustCreateFolderIfNotExists(pkgDstDirpath, os.ModePerm)
+
+			assetFileName := feparser.FormatID(mdl.Name, "For", feparser.FormatCodeQlName(pathVersion)) + ".go"
+			if err := x.SaveGoFile(pkgDstDirpath, assetFileName, file); err != nil {
+				Fatalf("Error while saving go file: %s", err)
+			}
+
+			if err := x.WriteGoModFile(pkgDstDirpath, pathVersion); err != nil {
+				Fatalf("Error while saving go.mod file: %s", err)
+			}
+			if err := x.WriteCodeQLTestQuery(pkgDstDirpath, mdl.Name, TestQueryContent); err != nil {
+				Fatalf("Error while saving <name>.ql file: %s", err)
+			}
+			if err := x.WriteEmptyCodeQLDotExpectedFile(pkgDstDirpath, mdl.Name); err != nil {
+				Fatalf("Error while saving <name>.expected file: %s", err)
+			}
+		}
+	}
+
+	if allInOneFile {
+		pkgDstDirpath := outDir
+		MustCreateFolderIfNotExists(pkgDstDirpath, os.ModePerm)
+
+		assetFileName := feparser.FormatID(mdl.Name) + ".go"
+		if err := x.SaveGoFile(pkgDstDirpath, assetFileName, file); err != nil {
+			Fatalf("Error while saving go file: %s", err)
+		}
+
+		if err := x.WriteGoModFile(pkgDstDirpath, allPathVersions...); err != nil {
+			Fatalf("Error while saving go.mod file: %s", err)
+		}
+		if err := x.WriteCodeQLTestQuery(pkgDstDirpath, mdl.Name, TestQueryContent); err != nil {
+			Fatalf("Error while saving <name>.ql file: %s", err)
+		}
+		if err := x.WriteEmptyCodeQLDotExpectedFile(pkgDstDirpath, mdl.Name); err != nil {
+			Fatalf("Error while saving <name>.expected file: %s", err)
+		}
+	}
+	return nil
+}
+
+// Comments adds comments to a Group (if enabled), and returns the group.
+func Comments(group *Group, comments ...string) *Group {
+	if IncludeCommentsInGeneratedGo {
+		for _, comment := range comments {
+			group.Line().Comment(comment)
+		}
+	}
+	return group
+}
+
+func newStatement() *Statement {
+	return &Statement{}
+}
+
+func generateGoTestBlock(
+	file *File,
+	fn x.FuncInterface,
+	nameQual *x.FuncQualifier,
+	dataQual *x.FuncQualifier,
+	escapes bool,
+) []Code {
+	childBlocks := make([]Code, 0)
+
+	nameIndexes := x.MustPosToRelativeParamIndexes(fn, nameQual.Pos)
+	if len(nameIndexes) != 1 {
+		Fatalf("nameIndexes len is not 1: %v", nameQual)
+	}
+	dataIndexes := x.MustPosToRelativeParamIndexes(fn, dataQual.Pos)
+	if len(dataIndexes) != 1 {
+		Fatalf("dataIndexes len is not 1: %v", dataQual)
+	}
+
+	childBlock := generate(
+		file,
+		fn,
+		nameIndexes[0],
+		dataIndexes[0],
+		escapes,
+	)
+	{
+		if childBlock != nil {
+			childBlocks = append(childBlocks, childBlock)
+		} else {
+			Warnf(Sf("NOTHING GENERATED; nameQual %v, dataQual %v", nameQual, dataQual))
+		}
+	}
+
+	return childBlocks
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// generate generates a call to the renderer; the data is expected to be
+// a response body only if the template engine does not escape it.
+func generate(file *File, fn x.FuncInterface, nameIndex int, dataIndex int, escapes bool) *Statement {
+	if nameIndex == dataIndex {
+		return nil
+	}
+
+	nameParam := fn.GetFunc().Parameters[nameIndex]
+	nameParam.VarName = gogentools.NewNameWithPrefix(feparser.NewLowerTitleName("name", nameParam.TypeName))
+
+	dataParam := fn.GetFunc().Parameters[dataIndex]
+	dataParam.VarName = gogentools.NewNameWithPrefix(feparser.NewLowerTitleName("data", dataParam.TypeName))
+
+	hasReceiver := fn.GetReceiver() != nil
+
+	code := BlockFunc(
+		func(groupCase *Group) {
+
+			ComposeTypeAssertion(file, groupCase, nameParam.VarName, nameParam.GetOriginal().GetType(), nameParam.GetOriginal().IsVariadic())
+			ComposeTypeAssertion(file, groupCase, dataParam.VarName, dataParam.GetOriginal().GetType(), dataParam.GetOriginal().IsVariadic())
+
+			if hasReceiver {
+				Comments(groupCase, "Declare medium object/interface:")
+				groupCase.Var().Id("rece").Qual(fn.GetReceiver().PkgPath, fn.GetReceiver().TypeName)
+			}
+
+			gogentools.ImportPackage(file, fn.GetFunc().PkgPath, fn.GetFunc().PkgName)
+
+			var after *Statement
+			if hasReceiver {
+				after = groupCase.Id("rece").Dot(fn.GetFunc().Name)
+			} else {
+				after = groupCase.Qual(fn.GetFunc().PkgPath, fn.GetFunc().Name)
+			}
+
+			callCode := after.CallFunc(
+				func(call *Group) {
+
+					tpFun := fn.GetFunc().GetOriginal().GetType().(*types.Signature)
+
+					zeroVals := gogentools.ScanTupleOfZeroValues(file, tpFun.Params(), fn.GetFunc().GetOriginal().IsVariadic())
+
+					for i, zero := range zeroVals {
+						isConsidered := i == nameIndex || i == dataIndex
+						if isConsidered {
+							call.Id(fn.GetFunc().Parameters[i].VarName)
+						} else {
+							call.Add(zero)
+						}
+					}
+
+				},
+			)
+			if !escapes {
+				callCode.Add(Tag(dataParam.VarName))
+			}
+
+		})
+	return code
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// declare `name := source(1).(Type)`
+func ComposeTypeAssertion(file *File, group *Group, varName string, typ types.Type, isVariadic bool) {
+	assertContent := newStatement()
+	if isVariadic {
+		if slice, ok := typ.(*types.Slice); ok {
+			gogentools.ComposeTypeDeclaration(file, assertContent, slice.Elem())
+		} else {
+			gogentools.ComposeTypeDeclaration(file, assertContent, typ)
+		}
+	} else {
+		gogentools.ComposeTypeDeclaration(file, assertContent, typ)
+	}
+	group.Id(varName).Op(":=").Id("source").Call().Assert(assertContent)
+}
@@ -0,0 +1,93 @@
+package templateexecution
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gagliardetto/codemill/x"
+)
+
+// NOTE:
+// - Each func that you add to MethodTemplateName must also be added to MethodTemplateData (and vice versa).
+// - Both the template name and the data must be exactly one parameter.
+// - The data is modeled as an HTML response body only if the template engine
+//   does not escape it (i.e. OptionAutoEscape is "false"), to avoid XSS false positives
+//   for engines based on html/template.
+// - The template name is not modeled as a sink: there is no template-injection
+//   concept in the library to extend, and the classes are generated inside a private module,
+//   so they cannot be used directly by a query. The name is bound only to make sure
+//   that the data is selected on the same call.
+// - For a func without a receiver, the response writer is any of the parameters
+//   that are neither the template name nor the data.
+
+const (
+	Kind x.ModelKind = "HTTP::TemplateExecution"
+)
+
+type Handler struct{}
+
+const (
+	MethodTemplateName = "{name:Param, data:Param} <- $name" // The name of the template that is rendered.
+	MethodTemplateData = "{name:Param, data:Param} <- $data" // The data that the template is rendered with.
+)
+
+const (
+	OptionAutoEscape = "AutoEscape" // Whether the template engine escapes the data (e.g. html/template): "true" or "false".
+)
+
+//
+func (han *Handler) ScavengeMethods() []*x.XMethod {
+	return x.ScavengeMethods(
+		// Each func that you add to MethodTemplateName,
+		// you must also add it to MethodTemplateData.
+		MethodTemplateName, // "Coupled 1/2: Select the parameter that specifies the name of the rendered template.",
+		MethodTemplateData, // "Coupled 2/2: Select the parameter that specifies the data the template is rendered with.",
+	)
+}
+
+//
+func (han *Handler) ScavengeOptions() map[string]string {
+	return map[string]string{
+		OptionAutoEscape: "",
+	}
+}
+func (han *Handler) Validate(mdl *x.XModel) error {
+	defaultMthNum := len(han.ScavengeMethods())
+	if len(mdl.Methods) != defaultMthNum {
+		return fmt.Errorf("wrong number of methods; expected %v, got %v", defaultMthNum, len(mdl.Methods))
+	}
+	{
+		for i, must := range han.ScavengeMethods() {
+			if mdl.Methods[i].Name != must.Name {
+				return fmt.Errorf("#%v method is not called %s", i, must.Name)
+			}
+		}
+	}
+	switch strings.TrimSpace(mdl.GetOption(OptionAutoEscape)) {
+	case "true", "false":
+	default:
+		return fmt.Errorf("option %s must be set to %q or %q", OptionAutoEscape, "true", "false")
+	}
+	{
+		// Each name selector must have a corresponding data selector (and vice versa):
+		mtdName := mdl.Methods.ByName(MethodTemplateName)
+		mtdData := mdl.Methods.ByName(MethodTemplateData)
+		for _, mtd := range []*x.XMethod{mtdName, mtdData} {
+			if err := x.ValidateParams(mtd, true); err != nil {
+				return err
+			}
+		}
+		if err := x.ValidateCoupled(mtdName, mtdData); err != nil {
+			return err
+		}
+		if err := x.ValidateCoupled(mtdData, mtdName); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// EngineEscapes returns true if the template engine of the model escapes the data.
+func EngineEscapes(mdl *x.XModel) bool {
+	return strings.TrimSpace(mdl.GetOption(OptionAutoEscape)) == "true"
+}
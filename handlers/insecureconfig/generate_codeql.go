@@ -0,0 +1,158 @@
+package insecureconfig
+
+import (
+	"sort"
+
+	"github.com/gagliardetto/codebox/scanner"
+	"github.com/gagliardetto/codemill/x"
+	. "github.com/gagliardetto/cqlgen/jen"
+	. "github.com/gagliardetto/utilz"
+)
+
+func (han *Handler) GenerateCodeQL(impAdder x.ImportAdder, mdl *x.XModel, moduleGroup *Group) error {
+	if err := mdl.Validate(); err != nil {
+		return err
+	}
+	if err := han.Validate(mdl); err != nil {
+		return err
+	}
+
+	// Assuming the validation has already been done:
+	self := mdl.Methods[0]
+
+	if len(self.Selectors) == 0 {
+		Infof("No selectors found for %q method.", self.Name)
+		return nil
+	}
+
+	{
+		// Add imports:
+		impAdder.Import(GetImport(mdl))
+	}
+
+	className := mdl.Name
+	value := GetValue(mdl)
+
+	b2st, err := x.GroupStructSelectors(self)
+	if err != nil {
+		Fatalf("Error while GroupStructSelectors: %s", err)
+	}
+
+	hasFuncFields := false
+	for _, structQualifiers := range b2st {
+		for _, qual := range structQualifiers {
+			for _, meta := range qual.Fields {
+				if isFuncField(meta) {
+					hasFuncFields = true
+				}
+			}
+		}
+	}
+
+	moduleGroup.Doc(
+		"Models writes of the insecure value `"+value+"` to configuration fields.",
+		"For func-typed fields, the written value is a func literal that returns the insecure value.",
+	)
+	moduleGroup.Private().Class().Id(className).Extends().List(Id(GetExtends(mdl))).
+		BlockFunc(func(classGr *Group) {
+			classGr.Id(className).Call().BlockFunc(func(metGr *Group) {
+				metGr.Exists(
+					List(
+						Qual("DataFlow", "Field").Id("fld"),
+						Qual("DataFlow", "Write").Id("w"),
+					),
+					DoGroup(func(st *Group) {
+						st.Id("w").Dot("writesField").Call(DontCare(), Id("fld"), This())
+					}),
+					DoGroup(func(st *Group) {
+						st.ParensFunc(func(par *Group) {
+							par.This().Dot("getExactValue").Call().Eq().Lit(value)
+							if hasFuncFields {
+								par.Or()
+								par.Comment("The field is a func, and the func literal returns the insecure value:")
+								par.Exists(
+									List(
+										Id("FuncLit").Id("fnLit"),
+									),
+									DoGroup(func(ex *Group) {
+										ex.This().Dot("asExpr").Call().Eq().Id("fnLit")
+										ex.And()
+										ex.Id("fnLit").Dot("getAReturnStmt").Call().Dot("getAnExpr").Call().Dot("getExactValue").Call().Eq().Lit(value)
+									}),
+									nil,
+								)
+							}
+						})
+
+						st.And()
+
+						st.ParensFunc(func(par *Group) {
+							index := 0
+							keys := func(v x.BasicToStructIDToFields) []string {
+								res := make([]string, 0)
+								for key := range v {
+									res = append(res, key)
+								}
+								sort.Strings(res)
+								return res
+							}(b2st)
+							for _, pathVersion := range keys {
+								structQualifiers, ok := b2st[pathVersion]
+								if !ok {
+									continue
+								}
+								if index > 0 {
+									par.Or()
+								}
+								index++
+								path, _ := scanner.SplitPathVersion(pathVersion)
+
+								par.Comment("Structs of package: " + pathVersion)
+								par.Exists(
+									List(
+										String().Id("structName"),
+										String().Id("fields"),
+									),
+									DoGroup(func(ex *Group) {
+										ex.Id("fld").Dot("hasQualifiedName").Call(
+											x.CqlFormatPackagePath(path),
+											Id("structName"),
+											Id("fields"),
+										)
+									}),
+									DoGroup(func(ex *Group) {
+										for qualIndex, qual := range structQualifiers {
+											if qualIndex > 0 {
+												ex.Or()
+											}
+											source := x.GetCachedSource(qual.Path, qual.Version)
+											if source == nil {
+												Fatalf("Source not found: %s@%s", qual.Path, qual.Version)
+											}
+											// Make sure that the struct exist:
+											str := x.FindStructByID(source, qual.ID)
+											if str == nil {
+												Fatalf("Struct not found: %q", qual.ID)
+											}
+
+											fieldNames := make([]string, 0)
+											for fieldName := range qual.Fields {
+												fieldNames = append(fieldNames, fieldName)
+											}
+											sort.Strings(fieldNames)
+
+											ex.Id("structName").Eq().Lit(str.TypeName)
+											ex.And()
+											ex.Id("fields").Eq().Add(StringsToSetOrLit(fieldNames...))
+										}
+									}),
+								)
+							}
+						})
+					}),
+				)
+			})
+		})
+
+	return nil
+}
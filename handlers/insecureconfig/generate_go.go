@@ -0,0 +1,229 @@
+package insecureconfig
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+
+	. "github.com/dave/jennifer/jen"
+	"github.com/gagliardetto/codebox/gogentools"
+	"github.com/gagliardetto/codemill/x"
+	"github.com/gagliardetto/feparser"
+	. "github.com/gagliardetto/utilz"
+)
+
+var (
+	IncludeCommentsInGeneratedGo bool
+	GenerateBoilerplate          bool
+)
+
+const (
+	// NOTE: hardcoded inside TestQueryContent const.
+	InlineExpectationsTestTag = "$insecureConfig" // Must start with a $ sign.
+)
+
+func TagWithValue(fieldName string) Code {
+	return Comment(InlineExpectationsTestTag + "=" + fieldName)
+}
+
+const (
+	// NOTE: the %s placeholders are replaced with the OptionImport library
+	// and with the OptionExtends class.
+	TestQueryContent = `
+import go
+import TestUtilities.InlineExpectationsTest
+import %s
+
+class InsecureConfigurationTest extends InlineExpectationsTest {
+  InsecureConfigurationTest() { this = "InsecureConfigurationTest" }
+
+  override string getARelevantTag() { result = "insecureConfig" }
+
+  override predicate hasActualResult(string file, int line, string element, string tag, string value) {
+    tag = "insecureConfig" and
+    exists(%s insecure, DataFlow::Write w, DataFlow::Field fld |
+      w.writesField(_, fld, insecure) and
+      insecure.hasLocationInfo(file, line, _, _, _) and
+      element = insecure.toString() and
+      value = fld.getName()
+    )
+  }
+}
+`
+)
+
+func NewTestFile(includeBoilerplace bool) *File {
+	file := NewFile("main")
+	// Set a prefix to avoid collision between variable names and packages:
+	file.PackagePrefix = "cql"
+	// Add comment to file:
+	file.HeaderComment("Code generated by https://github.com/gagliardetto. DO NOT EDIT.")
+
+	if includeBoilerplace {
+		file.PackageComment("//go:generate depstubber --vendor --auto")
+		{
+			// main function:
+			file.Func().Id("main").Params().Block()
+		}
+	}
+	return file
+}
+
+func (han *Handler) GenerateGo(parentDir string, mdl *x.XModel) error {
+	if err := mdl.Validate(); err != nil {
+		return err
+	}
+	if err := han.Validate(mdl); err != nil {
+		return err
+	}
+
+	// Check if there are multiple versions of a same package:
+	mods := mdl.ListModules()
+	if x.HasMultiversion(mods) {
+		Ln(RedBG("Has multiversion"))
+	}
+	// If there are no multiple versions of the same module,
+	// that means we can save all the code to one file.
+	allInOneFile := !x.HasMultiversion(mods)
+
+	// Create the directory for the tests for this model:
+	outDir := filepath.Join(parentDir, feparser.NewCodeQlName(mdl.Name))
+	MustCreateFolderIfNotExists(outDir, os.ModePerm)
+
+	// Assuming the validation has already been done:
+	self := mdl.Methods[0]
+
+	if len(self.Selectors) == 0 {
+		Infof("No selectors found for %q method.", self.Name)
+		return nil
+	}
+
+	allPathVersions := mdl.ListAllPathVersions()
+	value := GetValue(mdl)
+	testQueryContent := Sf(TestQueryContent, GetImport(mdl), GetExtends(mdl))
+
+	file := NewTestFile(GenerateBoilerplate)
+
+	for _, pathVersion := range allPathVersions {
+		if !allInOneFile {
+			// Reset file:
+			file = NewTestFile(GenerateBoilerplate)
+		}
+		codez := make([]Code, 0)
+
+		b2st, err := x.GroupStructSelectors(self)
+		if err != nil {
+			Fatalf("Error while GroupStructSelectors: %s", err)
+		}
+
+		{
+			structQualifiers, ok := b2st[pathVersion]
+			if ok {
+				code := BlockFunc(
+					func(groupCase *Group) {
+
+						for _, qual := range structQualifiers {
+							source := x.GetCachedSource(qual.Path, qual.Version)
+							if source == nil {
+								Fatalf("Source not found: %s@%s", qual.Path, qual.Version)
+							}
+							// Make sure that the struct exist:
+							str := x.FindStructByID(source, qual.ID)
+							if str == nil {
+								Fatalf("Struct not found: %q", qual.ID)
+							}
+
+							gogentools.ImportPackage(file, str.PkgPath, str.PkgName)
+
+							fieldNames := make([]string, 0)
+							for fieldName := range qual.Fields {
+								fieldNames = append(fieldNames, fieldName)
+							}
+							sort.Strings(fieldNames)
+
+							groupCase.Commentf("Insecure configurations of %s struct fields.", str.QualifiedName)
+							groupCase.BlockFunc(
+								func(subGroup *Group) {
+									structVarName := gogentools.NewNameWithPrefix(feparser.NewLowerTitleName("struct", str.TypeName))
+									subGroup.Id(structVarName).Op(":=").New(Qual(str.PkgPath, str.TypeName))
+
+									for _, fieldName := range fieldNames {
+										meta := qual.Fields[fieldName]
+										if isFuncField(meta) {
+											// TODO: generate a func literal with the signature of the field.
+											Warnf(Sf("NOTHING GENERATED; func field %s.%s must be tested manually", str.QualifiedName, fieldName))
+											subGroup.Commentf("TODO: %s.%s is a func field (%s); it must return %s.", structVarName, fieldName, meta.TypeString, value)
+											continue
+										}
+										subGroup.Id(structVarName).Dot(fieldName).Op("=").Add(valueCode(meta, value)).Add(TagWithValue(fieldName))
+									}
+									subGroup.Id("_").Op("=").Id(structVarName)
+								})
+
+						}
+					})
+
+				codez = append(codez,
+					Comment("Insecure configurations from struct fields.").
+						Line().
+						Add(code),
+				)
+			}
+		}
+
+		{
+			file.Commentf("Package %s", pathVersion)
+			file.Func().Id(mdl.Name + "_" + feparser.FormatCodeQlName(pathVersion)).Params().Block(codez...)
+		}
+
+		if !allInOneFile {
+			pkgDstDirpath := filepath.Join(outDir, feparser.FormatID(mdl.Name, "For", feparser.FormatCodeQlName(pathVersion)))
+			MustCreateFolderIfNotExists(pkgDstDirpath, os.ModePerm)
+
+			assetFileName := feparser.FormatID(mdl.Name, "For", feparser.FormatCodeQlName(pathVersion)) + ".go"
+			if err := x.SaveGoFile(pkgDstDirpath, assetFileName, file); err != nil {
+				Fatalf("Error while saving go file: %s", err)
+			}
+
+			if err := x.WriteGoModFile(pkgDstDirpath, pathVersion); err != nil {
+				Fatalf("Error while saving go.mod file: %s", err)
+			}
+			if err := x.WriteCodeQLTestQuery(pkgDstDirpath, mdl.Name, testQueryContent); err != nil {
+				Fatalf("Error while saving <name>.ql file: %s", err)
+			}
+			if err := x.WriteEmptyCodeQLDotExpectedFile(pkgDstDirpath, mdl.Name); err != nil {
+				Fatalf("Error while saving <name>.expected file: %s", err)
+			}
+		}
+	}
+
+	if allInOneFile {
+		pkgDstDirpath := outDir
+		MustCreateFolderIfNotExists(pkgDstDirpath, os.ModePerm)
+
+		assetFileName := feparser.FormatID(mdl.Name) + ".go"
+		if err := x.SaveGoFile(pkgDstDirpath, assetFileName, file); err != nil {
+			Fatalf("Error while saving go file: %s", err)
+		}
+
+		if err := x.WriteGoModFile(pkgDstDirpath, allPathVersions...); err != nil {
+			Fatalf("Error while saving go.mod file: %s", err)
+		}
+		if err := x.WriteCodeQLTestQuery(pkgDstDirpath, mdl.Name, testQueryContent); err != nil {
+			Fatalf("Error while saving <name>.ql file: %s", err)
+		}
+		if err := x.WriteEmptyCodeQLDotExpectedFile(pkgDstDirpath, mdl.Name); err != nil {
+			Fatalf("Error while saving <name>.expected file: %s", err)
+		}
+	}
+	return nil
+}
+
+// valueCode returns the Go code for the insecure value,
+// quoting it if the field is a string.
+func valueCode(meta *x.FieldMeta, value string) Code {
+	if meta != nil && meta.TypeString == "string" {
+		return Lit(value)
+	}
+	return Op(value)
+}
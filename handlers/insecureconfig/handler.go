@@ -0,0 +1,108 @@
+package insecureconfig
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/gagliardetto/codemill/x"
+)
+
+// NOTE:
+// - Only struct selectors are supported; each selected field is considered
+//   insecure when it is written the constant value set in the OptionValue option
+//   (e.g. `InsecureSkipVerify = true`).
+// - For func-typed fields (e.g. `CheckOrigin`), the field is considered insecure
+//   when it is written a func literal that returns the constant value.
+// - CodeQL has no shared concept for insecure configurations, so the generated
+//   class extends the class set in the OptionExtends option (e.g. the `Range` class
+//   of the config-misuse query), imported from the library set in OptionImport.
+
+const (
+	Kind x.ModelKind = "InsecureConfiguration"
+)
+
+type Handler struct{}
+
+const (
+	MethodSelf = "{insecure:[]Fields} <- $insecure"
+)
+
+const (
+	OptionValue   = "Value"   // The constant value that makes the selected fields insecure, e.g. `true`; strings must be unquoted.
+	OptionExtends = "Extends" // The CodeQL class that the generated class extends, e.g. `InsecureTls::Range`.
+	OptionImport  = "Import"  // The CodeQL library that defines the OptionExtends class, e.g. `semmle.go.security.InsecureTls`.
+)
+
+var (
+	// e.g. `InsecureTls::Range`; a class name must start with an uppercase letter.
+	extendsRegex = regexp.MustCompile(`^([A-Za-z][A-Za-z0-9_]*::)*[A-Z][A-Za-z0-9_]*$`)
+	// e.g. `semmle.go.security.InsecureTls`.
+	importRegex = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_]*(\.[A-Za-z][A-Za-z0-9_]*)*$`)
+)
+
+//
+func (han *Handler) ScavengeMethods() []*x.XMethod {
+	return []*x.XMethod{
+		{
+			Name:      MethodSelf,
+			Selectors: []*x.XSelector{},
+		},
+	}
+}
+
+//
+func (han *Handler) ScavengeOptions() map[string]string {
+	return map[string]string{
+		OptionValue:   "",
+		OptionExtends: "",
+		OptionImport:  "",
+	}
+}
+func (han *Handler) Validate(mdl *x.XModel) error {
+	if len(mdl.Methods) != 1 {
+		return fmt.Errorf("wrong number of methods; expected 1, got %v", len(mdl.Methods))
+	}
+	if mdl.Methods[0].Name != MethodSelf {
+		return fmt.Errorf("First method is not called %s", MethodSelf)
+	}
+	if GetValue(mdl) == "" {
+		return fmt.Errorf("option %s is not set", OptionValue)
+	}
+	if extends := GetExtends(mdl); extends == "" {
+		return fmt.Errorf("option %s is not set", OptionExtends)
+	} else if !extendsRegex.MatchString(extends) {
+		return fmt.Errorf("option %s is not a valid CodeQL class name: %q", OptionExtends, extends)
+	}
+	if imp := GetImport(mdl); imp == "" {
+		return fmt.Errorf("option %s is not set", OptionImport)
+	} else if !importRegex.MatchString(imp) {
+		return fmt.Errorf("option %s is not a valid CodeQL module path: %q", OptionImport, imp)
+	}
+	for _, sel := range mdl.Methods[0].Selectors {
+		if sel.Kind != x.SelectorKindStruct {
+			return fmt.Errorf("selector kind %q is not supported; only %q selectors are supported", sel.Kind, x.SelectorKindStruct)
+		}
+	}
+	return nil
+}
+
+// GetValue returns the insecure value set in the model option.
+func GetValue(mdl *x.XModel) string {
+	return strings.TrimSpace(mdl.GetOption(OptionValue))
+}
+
+// GetExtends returns the CodeQL class set in the model option.
+func GetExtends(mdl *x.XModel) string {
+	return strings.TrimSpace(mdl.GetOption(OptionExtends))
+}
+
+// GetImport returns the CodeQL library set in the model option.
+func GetImport(mdl *x.XModel) string {
+	return strings.TrimSpace(mdl.GetOption(OptionImport))
+}
+
+// isFuncField tells whether the field is of a func type.
+func isFuncField(meta *x.FieldMeta) bool {
+	return meta != nil && strings.HasPrefix(meta.TypeString, "func(")
+}
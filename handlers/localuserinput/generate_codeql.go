@@ -0,0 +1,19 @@
+package localuserinput
+
+import (
+	"github.com/gagliardetto/codemill/x"
+	. "github.com/gagliardetto/cqlgen/jen"
+)
+
+func (han *Handler) GenerateCodeQL(impAdder x.ImportAdder, mdl *x.XModel, moduleGroup *Group) error {
+	if err := mdl.Validate(); err != nil {
+		return err
+	}
+	if err := han.Validate(mdl); err != nil {
+		return err
+	}
+
+	src := *localUserInput
+	src.Import = GetImport(mdl)
+	return src.GenerateCodeQL(impAdder, mdl, moduleGroup)
+}
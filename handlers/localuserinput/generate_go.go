@@ -0,0 +1,59 @@
+package localuserinput
+
+import (
+	"github.com/gagliardetto/codemill/x"
+	. "github.com/gagliardetto/utilz"
+)
+
+var (
+	IncludeCommentsInGeneratedGo bool
+	GenerateBoilerplate          bool
+)
+
+const (
+	// NOTE: hardcoded inside TestQueryContent const.
+	InlineExpectationsTestTag = "$localUserInput" // Must start with a $ sign.
+)
+
+const (
+	// NOTE: the %s placeholder is replaced with the OptionImport library.
+	TestQueryContent = `
+import go
+import TestUtilities.InlineExpectationsTest
+import %s
+
+class LocalUserInputTest extends InlineExpectationsTest {
+  LocalUserInputTest() { this = "LocalUserInputTest" }
+
+  override string getARelevantTag() { result = "localUserInput" }
+
+  override predicate hasActualResult(string file, int line, string element, string tag, string value) {
+    tag = "localUserInput" and
+    exists(DataFlow::CallNode sinkCall, DataFlow::ArgumentNode arg |
+      sinkCall.getCalleeName() = "sink" and
+      arg = sinkCall.getAnArgument() and
+      (arg.getAPredecessor*() instanceof LocalUserInput)
+    |
+      element = arg.toString() and
+      value = "" and
+      arg.hasLocationInfo(file, line, _, _, _)
+    )
+  }
+}
+`
+)
+
+func (han *Handler) GenerateGo(parentDir string, mdl *x.XModel) error {
+	if err := mdl.Validate(); err != nil {
+		return err
+	}
+	if err := han.Validate(mdl); err != nil {
+		return err
+	}
+
+	src := *localUserInput
+	src.TestQueryContent = Sf(TestQueryContent, GetImport(mdl))
+	src.GenerateBoilerplate = GenerateBoilerplate
+	src.IncludeComments = IncludeCommentsInGeneratedGo
+	return src.GenerateGo(parentDir, mdl)
+}
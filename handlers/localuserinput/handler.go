@@ -0,0 +1,76 @@
+package localuserinput
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gagliardetto/codemill/handlers/untrustedflowsource"
+	"github.com/gagliardetto/codemill/x"
+)
+
+const (
+	Kind x.ModelKind = "LocalUserInput"
+)
+
+type Handler struct{}
+
+// NOTE:
+// - The selectors are the same as the ones of untrustedflowsource.Handler,
+//   and so is the generator; only the generated class (and the test query) differ.
+// - The go library has no concept for local user input, so the generated class
+//   extends LocalUserInputRange, imported from the library set in OptionImport.
+
+const (
+	LocalUserInputRange = "LocalUserInput::Range"
+)
+
+const (
+	OptionImport = "Import" // The CodeQL library that defines the LocalUserInput concept, e.g. `semmle.go.security.LocalUserInput`.
+)
+
+const (
+	MethodSelf = "{source:[](Param|Result|Fields|Type)} <- $source"
+)
+
+//
+func (han *Handler) ScavengeMethods() []*x.XMethod {
+	return []*x.XMethod{
+		{
+			Name:      MethodSelf,
+			Selectors: []*x.XSelector{},
+		},
+	}
+}
+
+//
+func (han *Handler) ScavengeOptions() map[string]string {
+	return map[string]string{
+		OptionImport: "",
+	}
+}
+func (han *Handler) Validate(mdl *x.XModel) error {
+	if len(mdl.Methods) != 1 {
+		return fmt.Errorf("wrong number of methods; expected 1, got %v", len(mdl.Methods))
+	}
+	if mdl.Methods[0].Name != MethodSelf {
+		return fmt.Errorf("First method is not called %s", MethodSelf)
+	}
+	if GetImport(mdl) == "" {
+		return fmt.Errorf("option %s is not set", OptionImport)
+	}
+	return nil
+}
+
+// GetImport returns the CodeQL library set in the model option.
+func GetImport(mdl *x.XModel) string {
+	return strings.TrimSpace(mdl.GetOption(OptionImport))
+}
+
+// localUserInput generates the models and the tests of the model;
+// the Import and the TestQueryContent are set from the OptionImport option.
+var localUserInput = &untrustedflowsource.Source{
+	Doc:     "Provides models of local user input sources.",
+	Extends: LocalUserInputRange,
+	Comment: "Local user input sources",
+	TestTag: InlineExpectationsTestTag,
+}
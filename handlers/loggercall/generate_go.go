@@ -0,0 +1,54 @@
+package loggercall
+
+import (
+	"github.com/gagliardetto/codemill/x"
+)
+
+var (
+	GenerateBoilerplate bool
+)
+
+const (
+	// NOTE: hardcoded inside TestQueryContent const.
+	InlineExpectationsTestTag = "$logger" // Must start with a $ sign.
+)
+
+const (
+	TestQueryContent = `
+import go
+import TestUtilities.InlineExpectationsTest
+
+class LoggerCallTest extends InlineExpectationsTest {
+  LoggerCallTest() { this = "LoggerCallTest" }
+
+  override string getARelevantTag() { result = "logger" }
+
+  override predicate hasActualResult(string file, int line, string element, string tag, string value) {
+    tag = "logger" and
+    exists(LoggerCall lc |
+      lc.hasLocationInfo(file, line, _, _, _) and
+      element = lc.getAMessageComponent().toString() and
+      value = lc.getAMessageComponent().toString()
+    )
+  }
+}
+`
+)
+
+var (
+	IncludeCommentsInGeneratedGo bool
+)
+
+func (han *Handler) GenerateGo(parentDir string, mdl *x.XModel) error {
+	if err := mdl.Validate(); err != nil {
+		return err
+	}
+	if err := han.Validate(mdl); err != nil {
+		return err
+	}
+
+	sink := *paramSink
+	sink.GenerateBoilerplate = GenerateBoilerplate
+	sink.IncludeComments = IncludeCommentsInGeneratedGo
+	return sink.GenerateGo(parentDir, mdl, mdl.Methods.ByName(MethodMessageComponent))
+}
@@ -0,0 +1,56 @@
+package loggercall
+
+import (
+	"fmt"
+
+	"github.com/gagliardetto/codemill/x"
+)
+
+// NOTE:
+// - When a variadic parameter is selected (e.g. the key/value list of a structured logger),
+//   all the arguments passed to it are modeled as message components;
+//   the generated tests pass a key and a value to it.
+
+const (
+	Kind x.ModelKind = "LoggerCall"
+)
+
+type Handler struct{}
+
+const (
+	MethodMessageComponent = "{msg:Param} <- $msg" // Message and field parameters (including variadic key/value lists) of a logger call.
+)
+
+//
+func (han *Handler) ScavengeMethods() []*x.XMethod {
+	return []*x.XMethod{
+		{
+			Name:      MethodMessageComponent,
+			Selectors: []*x.XSelector{},
+		},
+	}
+}
+func (han *Handler) Validate(mdl *x.XModel) error {
+	if len(mdl.Methods) != 1 {
+		return fmt.Errorf("wrong number of methods; expected 1, got %v", len(mdl.Methods))
+	}
+	{
+		if mdl.Methods[0].Name != MethodMessageComponent {
+			return fmt.Errorf("#0 method is not called %s", MethodMessageComponent)
+		}
+	}
+	return nil
+}
+
+// paramSink generates the models and the tests for the MethodMessageComponent selectors.
+var paramSink = &x.ParamSink{
+	Doc:              "Models logger calls.",
+	Extends:          "LoggerCall::Range",
+	Comment:          "Logger call",
+	NodeName:         "messageComponentNode",
+	Override:         "getAMessageComponent",
+	TestTag:          InlineExpectationsTestTag,
+	TestQueryContent: TestQueryContent,
+	VarPrefix:        "msg",
+	VariadicPairs:    true,
+}
@@ -0,0 +1,87 @@
+package query
+
+import (
+	"go/types"
+
+	. "github.com/dave/jennifer/jen"
+	"github.com/gagliardetto/codebox/gogentools"
+	"github.com/gagliardetto/codemill/x"
+)
+
+var (
+	GenerateBoilerplate bool
+)
+
+const (
+	// NOTE: hardcoded inside TestQueryContent const.
+	InlineExpectationsTestTag = "$nosqlquery" // Must start with a $ sign.
+)
+
+const (
+	TestQueryContent = `
+import go
+import TestUtilities.InlineExpectationsTest
+
+class NoSqlQueryTest extends InlineExpectationsTest {
+  NoSqlQueryTest() { this = "NoSqlQueryTest" }
+
+  override string getARelevantTag() { result = "nosqlquery" }
+
+  override predicate hasActualResult(string file, int line, string element, string tag, string value) {
+    tag = "nosqlquery" and
+    exists(NoSql::Query q |
+      q.hasLocationInfo(file, line, _, _, _) and
+      element = q.toString() and
+      value = q.toString()
+    )
+  }
+}
+`
+)
+
+var (
+	IncludeCommentsInGeneratedGo bool
+)
+
+func (han *Handler) GenerateGo(parentDir string, mdl *x.XModel) error {
+	if err := mdl.Validate(); err != nil {
+		return err
+	}
+	if err := han.Validate(mdl); err != nil {
+		return err
+	}
+
+	sink := *paramSink
+	sink.GenerateBoilerplate = GenerateBoilerplate
+	sink.IncludeComments = IncludeCommentsInGeneratedGo
+	return sink.GenerateGo(parentDir, mdl, mdl.Methods.ByName(MethodQuery))
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// ComposeQueryDeclaration declares the query document:
+// - for maps with string keys (e.g. `bson.M`), `name := Type{"key": source().(ValueType)}`;
+// - for any other type, `name := source().(Type)`.
+func ComposeQueryDeclaration(file *File, group *Group, varName string, typ types.Type, isVariadic bool) {
+	elemTyp := typ
+	if isVariadic {
+		if slice, ok := typ.(*types.Slice); ok {
+			elemTyp = slice.Elem()
+		}
+	}
+	if mp, ok := elemTyp.Underlying().(*types.Map); ok {
+		if key, ok := mp.Key().Underlying().(*types.Basic); ok && key.Info()&types.IsString != 0 {
+			mapContent := &Statement{}
+			gogentools.ComposeTypeDeclaration(file, mapContent, elemTyp)
+
+			valueContent := &Statement{}
+			gogentools.ComposeTypeDeclaration(file, valueContent, mp.Elem())
+
+			group.Id(varName).Op(":=").Add(mapContent).Values(Dict{
+				Lit("key"): Id("source").Call().Assert(valueContent),
+			})
+			return
+		}
+	}
+	x.ComposeSourceTypeAssertion(file, group, varName, typ, isVariadic)
+}
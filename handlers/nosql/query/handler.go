@@ -0,0 +1,49 @@
+package query
+
+import (
+	"fmt"
+
+	"github.com/gagliardetto/codemill/x"
+)
+
+const (
+	Kind x.ModelKind = "NoSql::Query"
+)
+
+type Handler struct{}
+
+const (
+	MethodQuery = "{query:Param} <- $query"
+)
+
+//
+func (han *Handler) ScavengeMethods() []*x.XMethod {
+	return []*x.XMethod{
+		{
+			Name:      MethodQuery,
+			Selectors: []*x.XSelector{},
+		},
+	}
+}
+func (han *Handler) Validate(mdl *x.XModel) error {
+	if len(mdl.Methods) != 1 {
+		return fmt.Errorf("wrong number of methods; expected 1, got %v", len(mdl.Methods))
+	}
+	{
+		if mdl.Methods[0].Name != MethodQuery {
+			return fmt.Errorf("#0 method is not called %s", MethodQuery)
+		}
+	}
+	return nil
+}
+
+// paramSink generates the models and the tests for the MethodQuery selectors.
+var paramSink = &x.ParamSink{
+	Doc:              "Models NoSQL queries.",
+	Extends:          "NoSql::Query::Range",
+	Comment:          "NoSQL query",
+	TestTag:          InlineExpectationsTestTag,
+	TestQueryContent: TestQueryContent,
+	VarPrefix:        "query",
+	ComposeArgument:  ComposeQueryDeclaration,
+}
@@ -0,0 +1,360 @@
+package regex
+
+import (
+	"github.com/gagliardetto/codemill/x"
+	. "github.com/gagliardetto/cqlgen/jen"
+	"github.com/gagliardetto/feparser"
+	. "github.com/gagliardetto/utilz"
+)
+
+func (han *Handler) GenerateCodeQL(impAdder x.ImportAdder, mdl *x.XModel, rootModuleGroup *Group) error {
+	if err := mdl.Validate(); err != nil {
+		return err
+	}
+	if err := han.Validate(mdl); err != nil {
+		return err
+	}
+
+	// Assuming the validation has already been done:
+	mtdPattern := mdl.Methods.ByName(MethodPattern)
+	mtdMatchRegexp := mdl.Methods.ByName(MethodMatchRegexp)
+	mtdMatchValue := mdl.Methods.ByName(MethodMatchValue)
+	mtdMatchResult := mdl.Methods.ByName(MethodMatchResult)
+
+	if len(mtdPattern.Selectors) == 0 && len(mtdMatchRegexp.Selectors) == 0 {
+		Infof("No selectors found for %q and %q methods.", mtdPattern.Name, mtdMatchRegexp.Name)
+		return nil
+	}
+
+	{
+		// Add imports:
+		//impAdder.Import("DataFlow::PathGraph")
+	}
+
+	className := mdl.Name
+	allPathVersions := mdl.ListAllPathVersions()
+
+	{
+		patternClassName := feparser.NewCodeQlName(className, "Pattern")
+
+		patternCode, patternCount := x.CqlCallTargets(allPathVersions, mtdPattern, "call", "Regexp pattern models", x.CqlBindArgument("call", "this"))
+
+		tmp := DoGroup(func(tempFuncsModel *Group) {
+			tempFuncsModel.Doc("Models regexp patterns.")
+			tempFuncsModel.Private().Class().Id(patternClassName).Extends().Id("RegexpPattern::Range").BlockFunc(
+				func(patternClassGroup *Group) {
+					patternClassGroup.String().Id("package").Semicolon().Line()
+					patternClassGroup.Id("DataFlow::CallNode").Id("call").Semicolon().Line()
+
+					patternClassGroup.Id(patternClassName).Call().Block(
+						patternCode,
+					)
+
+					patternClassGroup.Override().Id("DataFlow::Node").Id("getAParse").Call().BlockFunc(
+						func(overrideBlockGroup *Group) {
+							overrideBlockGroup.Id("result").Eq().Id("call")
+						})
+
+					patternClassGroup.Override().Id("string").Id("getPattern").Call().BlockFunc(
+						func(overrideBlockGroup *Group) {
+							overrideBlockGroup.Id("result").Eq().This().Dot("getStringValue").Call()
+						})
+
+					patternClassGroup.Override().Id("DataFlow::Node").Id("getAUse").Call().BlockFunc(
+						func(overrideBlockGroup *Group) {
+							overrideBlockGroup.Id("result").Eq().Id("call").Dot("getAResult").Call().Dot("getASuccessor*").Call()
+						})
+				})
+		})
+		if patternCount > 0 {
+			rootModuleGroup.Add(tmp)
+		}
+	}
+
+	b2fe, b2tm, b2itm, err := x.GroupFuncSelectors(mtdMatchRegexp)
+	if err != nil {
+		Fatalf("Error while GroupFuncSelectors: %s", err)
+	}
+	b2feVal, b2tmVal, b2itmVal, err := x.GroupFuncSelectors(mtdMatchValue)
+	if err != nil {
+		Fatalf("Error while GroupFuncSelectors: %s", err)
+	}
+	b2feRes, b2tmRes, b2itmRes, err := x.GroupFuncSelectors(mtdMatchResult)
+	if err != nil {
+		Fatalf("Error while GroupFuncSelectors: %s", err)
+	}
+	{
+		addedCount := 0
+		funcModelsClassName := feparser.NewCodeQlName(className, "MatchFunctionModels")
+		tmp := DoGroup(func(tempFuncsModel *Group) {
+			tempFuncsModel.Doc("Models regexp-matching functions.")
+			tempFuncsModel.Private().Class().Id(funcModelsClassName).Extends().Qual("RegexpMatchFunction", "Range").BlockFunc(
+				func(funcModelsClassGroup *Group) {
+					funcModelsClassGroup.Id("FunctionInput").Id("re").Semicolon().Line()
+					funcModelsClassGroup.Id("FunctionInput").Id("inp").Semicolon().Line()
+					funcModelsClassGroup.Id("FunctionOutput").Id("outp").Semicolon().Line()
+
+					funcModelsClassGroup.Id(funcModelsClassName).Call().BlockFunc(
+						func(funcModelsSelfMethodGroup *Group) {
+							{
+								funcModelsSelfMethodGroup.DoGroup(
+									func(groupCase *Group) {
+										for _, pathVersion := range allPathVersions {
+											cont, ok := b2fe[pathVersion]
+											if ok {
+												pathCodez := make([]Code, 0)
+												for _, funcQual := range cont {
+													if AllFalse(funcQual.Pos...) {
+														continue
+													}
+
+													valQual := b2feVal[pathVersion].ByBasicQualifier(funcQual.BasicQualifier)
+													resQual := b2feRes[pathVersion].ByBasicQualifier(funcQual.BasicQualifier)
+													fn, code := GetMatchQualifierCodeElements(funcQual, valQual, resQual)
+													thing := fn.(*feparser.FEFunc)
+													pathCodez = append(pathCodez,
+														ParensFunc(
+															func(par *Group) {
+																par.Commentf("signature: %s", thing.Signature)
+																par.This().Dot("hasQualifiedName").Call(x.CqlFormatPackagePath(funcQual.Path), Lit(thing.Name))
+																par.And()
+																par.Add(code)
+															},
+														),
+													)
+												}
+
+												if len(pathCodez) > 0 {
+													if addedCount > 0 {
+														groupCase.Or()
+													}
+													groupCase.Commentf("Regexp-matching models for package: %s", pathVersion).Parens(
+														Join(
+															Or(),
+															pathCodez...,
+														),
+													)
+													addedCount++
+												}
+											}
+										}
+									})
+							}
+						})
+
+					addMatchOverrides(funcModelsClassGroup)
+				})
+		})
+		if addedCount > 0 {
+			rootModuleGroup.Add(tmp)
+		}
+	}
+
+	{
+		addedCount := 0
+		methodModelsClassName := feparser.NewCodeQlName(className, "MatchMethodModels")
+		tmp := DoGroup(func(tempMethodsModel *Group) {
+			tempMethodsModel.Doc("Models regexp-matching methods.")
+			tempMethodsModel.Private().Class().Id(methodModelsClassName).Extends().List(Qual("RegexpMatchFunction", "Range"), Id("Method")).BlockFunc(
+				func(methodModelsClassGroup *Group) {
+					methodModelsClassGroup.Id("FunctionInput").Id("re").Semicolon().Line()
+					methodModelsClassGroup.Id("FunctionInput").Id("inp").Semicolon().Line()
+					methodModelsClassGroup.Id("FunctionOutput").Id("outp").Semicolon().Line()
+
+					methodModelsClassGroup.Id(methodModelsClassName).Call().BlockFunc(
+						func(methodModelsSelfMethodGroup *Group) {
+							{
+								methodModelsSelfMethodGroup.DoGroup(
+									func(groupCase *Group) {
+										for _, pathVersion := range allPathVersions {
+											pathCodez := make([]Code, 0)
+											{
+												b2tm.IterValid(pathVersion,
+													func(receiverTypeID string, methodQualifiers x.FuncQualifierSlice) {
+														codez := DoGroup(func(mtdGroup *Group) {
+															qual := methodQualifiers[0]
+															source := x.GetCachedSource(qual.Path, qual.Version)
+															if source == nil {
+																Fatalf("Source not found: %s@%s", qual.Path, qual.Version)
+															}
+															// Find receiver type:
+															typ := x.FindTypeByID(source, receiverTypeID)
+															if typ == nil {
+																Fatalf("Type not found: %q", receiverTypeID)
+															}
+
+															mtdGroup.Commentf("Receiver type: %s", typ.TypeString)
+
+															methodIndex := 0
+															mtdGroup.ParensFunc(
+																func(parMethods *Group) {
+																	for _, methodQual := range methodQualifiers {
+																		if AllFalse(methodQual.Pos...) {
+																			continue
+																		}
+																		if methodIndex > 0 {
+																			parMethods.Or()
+																		}
+																		methodIndex++
+
+																		valQual := b2tmVal[pathVersion][receiverTypeID].ByBasicQualifier(methodQual.BasicQualifier)
+																		resQual := b2tmRes[pathVersion][receiverTypeID].ByBasicQualifier(methodQual.BasicQualifier)
+																		fn, code := GetMatchQualifierCodeElements(methodQual, valQual, resQual)
+																		thing := fn.(*feparser.FETypeMethod)
+
+																		parMethods.ParensFunc(
+																			func(par *Group) {
+																				par.Commentf("signature: %s", thing.Func.Signature)
+																				par.This().Dot("hasQualifiedName").Call(x.CqlFormatPackagePath(methodQual.Path), Lit(thing.Receiver.TypeName), Lit(thing.Func.Name))
+																				par.And()
+																				par.Add(code)
+																			},
+																		)
+
+																	}
+																},
+															)
+
+														})
+														pathCodez = append(pathCodez, codez)
+													})
+											}
+
+											b2itm.IterValid(pathVersion,
+												func(receiverTypeID string, methodQualifiers x.FuncQualifierSlice) {
+													codez := DoGroup(func(mtdGroup *Group) {
+														qual := methodQualifiers[0]
+														source := x.GetCachedSource(qual.Path, qual.Version)
+														if source == nil {
+															Fatalf("Source not found: %s@%s", qual.Path, qual.Version)
+														}
+														// Find receiver type:
+														typ := x.FindTypeByID(source, receiverTypeID)
+														if typ == nil {
+															Fatalf("Type not found: %q", receiverTypeID)
+														}
+														mtdGroup.Commentf("Receiver interface: %s", typ.TypeString)
+
+														methodIndex := 0
+														mtdGroup.ParensFunc(
+															func(parMethods *Group) {
+																for _, methodQual := range methodQualifiers {
+																	if AllFalse(methodQual.Pos...) {
+																		continue
+																	}
+																	if methodIndex > 0 {
+																		parMethods.Or()
+																	}
+																	methodIndex++
+
+																	valQual := b2itmVal[pathVersion][receiverTypeID].ByBasicQualifier(methodQual.BasicQualifier)
+																	resQual := b2itmRes[pathVersion][receiverTypeID].ByBasicQualifier(methodQual.BasicQualifier)
+																	fn, code := GetMatchQualifierCodeElements(methodQual, valQual, resQual)
+																	thing := fn.(*feparser.FEInterfaceMethod)
+
+																	parMethods.ParensFunc(
+																		func(par *Group) {
+																			par.Commentf("signature: %s", thing.Func.Signature)
+																			par.This().Dot("implements").Call(x.CqlFormatPackagePath(methodQual.Path), Lit(thing.Receiver.TypeName), Lit(thing.Func.Name))
+																			par.And()
+																			par.Add(code)
+																		},
+																	)
+
+																}
+															},
+														)
+
+													})
+													pathCodez = append(pathCodez, codez)
+												})
+
+											if len(pathCodez) > 0 {
+												if addedCount > 0 {
+													groupCase.Or()
+												}
+												groupCase.Commentf("Regexp-matching models for package: %s", pathVersion).Parens(
+													Join(
+														Or(),
+														pathCodez...,
+													),
+												)
+												addedCount++
+											}
+										}
+									})
+							}
+						})
+
+					addMatchOverrides(methodModelsClassGroup)
+				})
+		})
+		if addedCount > 0 {
+			rootModuleGroup.Add(tmp)
+		}
+	}
+
+	return nil
+}
+
+func addMatchOverrides(classGroup *Group) {
+	classGroup.Override().Id("FunctionInput").Id("getRegexpArg").Call().BlockFunc(
+		func(overrideBlockGroup *Group) {
+			overrideBlockGroup.Id("result").Eq().Id("re")
+		})
+
+	classGroup.Override().Id("FunctionInput").Id("getValue").Call().BlockFunc(
+		func(overrideBlockGroup *Group) {
+			overrideBlockGroup.Id("result").Eq().Id("inp")
+		})
+
+	classGroup.Override().Id("FunctionOutput").Id("getResult").Call().BlockFunc(
+		func(overrideBlockGroup *Group) {
+			overrideBlockGroup.Id("result").Eq().Id("outp")
+		})
+}
+
+func GetMatchQualifierCodeElements(reQual *x.FuncQualifier, valQual *x.FuncQualifier, resQual *x.FuncQualifier) (x.FuncInterface, Code) {
+	fn := x.GetFuncByQualifier(reQual)
+
+	reCodeElements := make([]Code, 0)
+	{
+		receiver, parameterIndexes, resultIndexes := x.PosToRelativeIndexes(fn, reQual.Pos)
+		reCodeElements = x.GenFunctionInputOutput("re", fn, receiver, parameterIndexes, resultIndexes)
+	}
+
+	valCodeElements := make([]Code, 0)
+	{
+		receiver, parameterIndexes, resultIndexes := x.PosToRelativeIndexes(fn, valQual.Pos)
+		valCodeElements = x.GenFunctionInputOutput("inp", fn, receiver, parameterIndexes, resultIndexes)
+	}
+
+	resCodeElements := make([]Code, 0)
+	{
+		receiver, parameterIndexes, resultIndexes := x.PosToRelativeIndexes(fn, resQual.Pos)
+		resCodeElements = x.GenFunctionInputOutput("outp", fn, receiver, parameterIndexes, resultIndexes)
+	}
+
+	code := Parens(
+		Join(
+			Or(),
+			reCodeElements...,
+		),
+	).
+		And().
+		Parens(
+			Join(
+				Or(),
+				valCodeElements...,
+			),
+		).
+		And().
+		Parens(
+			Join(
+				Or(),
+				resCodeElements...,
+			),
+		)
+
+	return fn, code
+}
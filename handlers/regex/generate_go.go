@@ -0,0 +1,616 @@
+package regex
+
+import (
+	"go/types"
+	"os"
+	"path/filepath"
+
+	. "github.com/dave/jennifer/jen"
+	"github.com/gagliardetto/codebox/gogentools"
+	"github.com/gagliardetto/codemill/x"
+	"github.com/gagliardetto/feparser"
+	. "github.com/gagliardetto/utilz"
+)
+
+var (
+	GenerateBoilerplate bool
+)
+
+const (
+	// NOTE: hardcoded inside TestQueryContent const.
+	InlineExpectationsTestTagPattern = "$regexpPattern"    // Must start with a $ sign.
+	InlineExpectationsTestTagValue   = "$regexpMatchValue" // Must start with a $ sign.
+)
+
+func TagPattern(varName string) Code {
+	return Comment(InlineExpectationsTestTagPattern + "=" + varName)
+}
+
+func TagValue(varName string) Code {
+	return Comment(InlineExpectationsTestTagValue + "=" + varName)
+}
+
+const (
+	TestQueryContent = `
+import go
+import TestUtilities.InlineExpectationsTest
+
+class RegexpTest extends InlineExpectationsTest {
+  RegexpTest() { this = "RegexpTest" }
+
+  override string getARelevantTag() { result = ["regexpPattern", "regexpMatchValue"] }
+
+  override predicate hasActualResult(string file, int line, string element, string tag, string value) {
+    tag = "regexpPattern" and
+    exists(RegexpPattern p |
+      p.hasLocationInfo(file, line, _, _, _) and
+      element = p.toString() and
+      value = p.toString()
+    )
+    or
+    tag = "regexpMatchValue" and
+    exists(RegexpMatchFunction fn, DataFlow::CallNode call | call = fn.getACall() |
+      call.hasLocationInfo(file, line, _, _, _) and
+      element = fn.getValue().getNode(call).toString() and
+      value = fn.getValue().getNode(call).toString()
+    )
+  }
+}
+`
+)
+
+func NewTestFile(includeBoilerplace bool) *File {
+	file := NewFile("main")
+	// Set a prefix to avoid collision between variable names and packages:
+	file.PackagePrefix = "cql"
+	// Add comment to file:
+	file.HeaderComment("Code generated by https://github.com/gagliardetto. DO NOT EDIT.")
+
+	if includeBoilerplace {
+		file.PackageComment("//go:generate depstubber --vendor --auto")
+		{
+			// main function:
+			file.Func().Id("main").Params().Block()
+		}
+		{
+			// The `source` function returns a new pattern or value:
+			code := Func().
+				Id("source").
+				Params().
+				Interface().
+				Block(Return(Nil()))
+			file.Add(code.Line())
+		}
+	}
+	return file
+}
+
+var (
+	IncludeCommentsInGeneratedGo bool
+)
+
+func (han *Handler) GenerateGo(parentDir string, mdl *x.XModel) error {
+	if err := mdl.Validate(); err != nil {
+		return err
+	}
+	if err := han.Validate(mdl); err != nil {
+		return err
+	}
+
+	// Check if there are multiple versions of a same package:
+	mods := mdl.ListModules()
+	if x.HasMultiversion(mods) {
+		Ln(RedBG("Has multiversion"))
+	}
+	// If there are no multiple versions of the same module,
+	// that means we can save all the code to one file.
+	allInOneFile := !x.HasMultiversion(mods)
+
+	// Create the directory for the tests for this model:
+	outDir := filepath.Join(parentDir, feparser.NewCodeQlName(mdl.Name))
+	MustCreateFolderIfNotExists(outDir, os.ModePerm)
+
+	// Assuming the validation has already been done:
+	mtdPattern := mdl.Methods.ByName(MethodPattern)
+	mtdMatchRegexp := mdl.Methods.ByName(MethodMatchRegexp)
+	mtdMatchValue := mdl.Methods.ByName(MethodMatchValue)
+
+	if len(mtdPattern.Selectors) == 0 && len(mtdMatchRegexp.Selectors) == 0 {
+		Infof("No selectors found for %q and %q methods.", mtdPattern.Name, mtdMatchRegexp.Name)
+		return nil
+	}
+
+	allPathVersions := mdl.ListAllPathVersions()
+
+	file := NewTestFile(GenerateBoilerplate)
+
+	b2fePattern, b2tmPattern, b2itmPattern, err := x.GroupFuncSelectors(mtdPattern)
+	if err != nil {
+		Fatalf("Error while GroupFuncSelectors: %s", err)
+	}
+	b2feRegexp, b2tmRegexp, b2itmRegexp, err := x.GroupFuncSelectors(mtdMatchRegexp)
+	if err != nil {
+		Fatalf("Error while GroupFuncSelectors: %s", err)
+	}
+	b2feValue, b2tmValue, b2itmValue, err := x.GroupFuncSelectors(mtdMatchValue)
+	if err != nil {
+		Fatalf("Error while GroupFuncSelectors: %s", err)
+	}
+
+	for _, pathVersion := range allPathVersions {
+		if !allInOneFile {
+			// Reset file:
+			file = NewTestFile(GenerateBoilerplate)
+		}
+		codez := make([]Code, 0)
+
+		// Regexp patterns:
+		codez = append(codez,
+			generateGoTestCodez(file, pathVersion, "Regexp pattern", b2fePattern, b2tmPattern, b2itmPattern,
+				func(file *File, fn x.FuncInterface, qual *x.FuncQualifier, receiverTypeID string) []Code {
+					return generateGoPatternTestBlock(file, fn, qual)
+				},
+			)...,
+		)
+		// Regexp-matching funcs:
+		codez = append(codez,
+			generateGoTestCodez(file, pathVersion, "Regexp matching", b2feRegexp, b2tmRegexp, b2itmRegexp,
+				func(file *File, fn x.FuncInterface, qual *x.FuncQualifier, receiverTypeID string) []Code {
+					var valueQual *x.FuncQualifier
+					switch fn.(type) {
+					case *feparser.FEFunc:
+						valueQual = b2feValue[pathVersion].ByBasicQualifier(qual.BasicQualifier)
+					case *feparser.FETypeMethod:
+						if _, ok := b2tmValue[pathVersion][receiverTypeID]; ok {
+							valueQual = b2tmValue[pathVersion][receiverTypeID].ByBasicQualifier(qual.BasicQualifier)
+						} else {
+							valueQual = b2itmValue[pathVersion][receiverTypeID].ByBasicQualifier(qual.BasicQualifier)
+						}
+					}
+					return generateGoMatchTestBlock(file, fn, qual, valueQual)
+				},
+			)...,
+		)
+
+		{
+			file.Commentf("Package %s", pathVersion)
+			file.Func().Id(mdl.Name + "_" + feparser.FormatCodeQlName(pathVersion)).Params().Block(codez...)
+		}
+
+		if !allInOneFile {
+			pkgDstDirpath := filepath.Join(outDir, feparser.FormatID(mdl.Name, "For", feparser.FormatCodeQlName(pathVersion)))
+			MustCreateFolderIfNotExists(pkgDstDirpath, os.ModePerm)
+
+			assetFileName := feparser.FormatID(mdl.Name, "For", feparser.FormatCodeQlName(pathVersion)) + ".go"
+			if err := x.SaveGoFile(pkgDstDirpath, assetFileName, file); err != nil {
+				Fatalf("Error while saving go file: %s", err)
+			}
+
+			if err := x.WriteGoModFile(pkgDstDirpath, pathVersion); err != nil {
+				Fatalf("Error while saving go.mod file: %s", err)
+			}
+			if err := x.WriteCodeQLTestQuery(pkgDstDirpath, mdl.Name, TestQueryContent); err != nil {
+				Fatalf("Error while saving <name>.ql file: %s", err)
+			}
+			if err := x.WriteEmptyCodeQLDotExpectedFile(pkgDstDirpath, mdl.Name); err != nil {
+				Fatalf("Error while saving <name>.expected file: %s", err)
+			}
+		}
+	}
+
+	if allInOneFile {
+		pkgDstDirpath := outDir
+		MustCreateFolderIfNotExists(pkgDstDirpath, os.ModePerm)
+
+		assetFileName := feparser.FormatID(mdl.Name) + ".go"
+		if err := x.SaveGoFile(pkgDstDirpath, assetFileName, file); err != nil {
+			Fatalf("Error while saving go file: %s", err)
+		}
+
+		if err := x.WriteGoModFile(pkgDstDirpath, allPathVersions...); err != nil {
+			Fatalf("Error while saving go.mod file: %s", err)
+		}
+		if err := x.WriteCodeQLTestQuery(pkgDstDirpath, mdl.Name, TestQueryContent); err != nil {
+			Fatalf("Error while saving <name>.ql file: %s", err)
+		}
+		if err := x.WriteEmptyCodeQLDotExpectedFile(pkgDstDirpath, mdl.Name); err != nil {
+			Fatalf("Error while saving <name>.expected file: %s", err)
+		}
+	}
+	return nil
+}
+
+// Comments adds comments to a Group (if enabled), and returns the group.
+func Comments(group *Group, comments ...string) *Group {
+	if IncludeCommentsInGeneratedGo {
+		for _, comment := range comments {
+			group.Line().Comment(comment)
+		}
+	}
+	return group
+}
+
+func newStatement() *Statement {
+	return &Statement{}
+}
+
+// generateGoTestCodez generates the test code blocks for all the funcs and methods
+// selected for the provided pathVersion, using gen to generate the single test blocks.
+func generateGoTestCodez(
+	file *File,
+	pathVersion string,
+	what string,
+	b2fe x.BasicToFEFuncs,
+	b2tm x.BasicToTypeIDToMethods,
+	b2itm x.BasicToInterfaceIDToMethods,
+	gen func(file *File, fn x.FuncInterface, qual *x.FuncQualifier, receiverTypeID string) []Code,
+) []Code {
+	codez := make([]Code, 0)
+
+	{
+		cont, ok := b2fe[pathVersion]
+		if ok && x.HasValidPos(cont...) {
+			addedCount := 0
+			code := BlockFunc(
+				func(groupCase *Group) {
+
+					for _, qual := range cont {
+						fn := x.GetFuncByQualifier(qual)
+						thing := fn.(*feparser.FEFunc)
+
+						x.AddImportsFromFunc(file, thing)
+
+						{
+							if AllFalse(qual.Pos...) {
+								continue
+							}
+							groupCase.Comment(thing.Signature)
+
+							blocksOfCases := gen(file, thing, qual, "")
+							if len(blocksOfCases) == 1 {
+								groupCase.Add(blocksOfCases...)
+							} else {
+								groupCase.Block(blocksOfCases...)
+							}
+							addedCount++
+						}
+
+					}
+				})
+			if addedCount > 0 {
+				codez = append(codez,
+					Commentf("%s via function call.", what).
+						Line().
+						Add(code),
+				)
+			}
+		}
+	}
+	{
+		codezTypeMethods := make([]Code, 0)
+		b2tm.IterValid(pathVersion,
+			func(receiverTypeID string, methodQualifiers x.FuncQualifierSlice) {
+
+				firstQual := methodQualifiers[0]
+				// Find receiver type:
+				typ := x.FindType(firstQual.Path, firstQual.Version, receiverTypeID)
+				if typ == nil {
+					Fatalf("Type not found: %q", receiverTypeID)
+				}
+
+				gogentools.ImportPackage(file, typ.PkgPath, typ.PkgName)
+
+				code := BlockFunc(
+					func(groupCase *Group) {
+
+						for _, qual := range methodQualifiers {
+							fn := x.GetFuncByQualifier(qual)
+							thing := fn.(*feparser.FETypeMethod)
+							x.AddImportsFromFunc(file, fn)
+
+							{
+								if AllFalse(qual.Pos...) {
+									continue
+								}
+								groupCase.Comment(thing.Func.Signature)
+
+								blocksOfCases := gen(file, thing, qual, receiverTypeID)
+								if len(blocksOfCases) == 1 {
+									groupCase.Add(blocksOfCases...)
+								} else {
+									groupCase.Block(blocksOfCases...)
+								}
+							}
+
+						}
+					})
+				codezTypeMethods = append(codezTypeMethods,
+					Commentf("%s via method calls on %s.", what, typ.QualifiedName).
+						Line().
+						Add(code),
+				)
+			})
+		if len(codezTypeMethods) > 0 {
+			codez = append(codez,
+				Commentf("%s via method calls.", what).
+					Line().
+					Block(codezTypeMethods...),
+			)
+		}
+	}
+
+	{
+		codezIfaceMethods := make([]Code, 0)
+		b2itm.IterValid(pathVersion,
+			func(receiverTypeID string, methodQualifiers x.FuncQualifierSlice) {
+				firstQual := methodQualifiers[0]
+				// Find receiver type:
+				typ := x.FindType(firstQual.Path, firstQual.Version, receiverTypeID)
+				if typ == nil {
+					Fatalf("Type not found: %q", receiverTypeID)
+				}
+
+				gogentools.ImportPackage(file, typ.PkgPath, typ.PkgName)
+
+				code := BlockFunc(
+					func(groupCase *Group) {
+
+						for _, qual := range methodQualifiers {
+							fn := x.GetFuncByQualifier(qual)
+							thing := fn.(*feparser.FEInterfaceMethod)
+							x.AddImportsFromFunc(file, fn)
+
+							{
+								if AllFalse(qual.Pos...) {
+									continue
+								}
+								groupCase.Comment(thing.Func.Signature)
+
+								converted := feparser.FEIToFET(thing)
+
+								blocksOfCases := gen(file, converted, qual, receiverTypeID)
+								if len(blocksOfCases) == 1 {
+									groupCase.Add(blocksOfCases...)
+								} else {
+									groupCase.Block(blocksOfCases...)
+								}
+							}
+						}
+					})
+				codezIfaceMethods = append(codezIfaceMethods,
+					Commentf("%s via method calls on %s interface.", what, typ.QualifiedName).
+						Line().
+						Add(code),
+				)
+			})
+
+		if len(codezIfaceMethods) > 0 {
+			codez = append(codez,
+				Commentf("%s via interface method calls.", what).
+					Line().
+					Block(codezIfaceMethods...),
+			)
+		}
+	}
+
+	return codez
+}
+
+func generateGoPatternTestBlock(
+	file *File,
+	fn x.FuncInterface,
+	patternQual *x.FuncQualifier,
+) []Code {
+	childBlocks := make([]Code, 0)
+
+	patternIndexes := x.MustPosToRelativeParamIndexes(fn, patternQual.Pos)
+	if len(patternIndexes) != 1 {
+		Fatalf("patternIndexes len is not 1: %v", patternQual)
+	}
+
+	childBlock := generatePattern(
+		file,
+		fn,
+		patternIndexes[0],
+	)
+	{
+		if childBlock != nil {
+			childBlocks = append(childBlocks, childBlock)
+		} else {
+			Warnf(Sf("NOTHING GENERATED; patternQual %v", patternQual))
+		}
+	}
+
+	return childBlocks
+}
+
+func generateGoMatchTestBlock(
+	file *File,
+	fn x.FuncInterface,
+	regexpQual *x.FuncQualifier,
+	valueQual *x.FuncQualifier,
+) []Code {
+	childBlocks := make([]Code, 0)
+	if valueQual == nil {
+		Warnf(Sf("NOTHING GENERATED; no value selected for %v", regexpQual))
+		return childBlocks
+	}
+
+	regexpIndex, err := onlyTrueIndex(regexpQual.Pos)
+	if err != nil {
+		Warnf(Sf("NOTHING GENERATED; regexpQual %v: %s", regexpQual, err))
+		return childBlocks
+	}
+	valueIndex, err := onlyTrueIndex(valueQual.Pos)
+	if err != nil {
+		Warnf(Sf("NOTHING GENERATED; valueQual %v: %s", valueQual, err))
+		return childBlocks
+	}
+
+	childBlock := generateMatch(
+		file,
+		fn,
+		regexpIndex,
+		valueIndex,
+	)
+	{
+		if childBlock != nil {
+			childBlocks = append(childBlocks, childBlock)
+		} else {
+			Warnf(Sf("NOTHING GENERATED; regexpQual %v, valueQual %v", regexpQual, valueQual))
+		}
+	}
+
+	return childBlocks
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+func generatePattern(file *File, fn x.FuncInterface, patternIndex int) *Statement {
+
+	patternParam := fn.GetFunc().Parameters[patternIndex]
+	patternParam.VarName = gogentools.NewNameWithPrefix(feparser.NewLowerTitleName("pattern", patternParam.TypeName))
+
+	hasReceiver := fn.GetReceiver() != nil
+
+	code := BlockFunc(
+		func(groupCase *Group) {
+
+			ComposeTypeAssertion(file, groupCase, patternParam.VarName, patternParam.GetOriginal().GetType(), patternParam.GetOriginal().IsVariadic())
+
+			if hasReceiver {
+				Comments(groupCase, "Declare medium object/interface:")
+				groupCase.Var().Id("rece").Qual(fn.GetReceiver().PkgPath, fn.GetReceiver().TypeName)
+			}
+
+			gogentools.ImportPackage(file, fn.GetFunc().PkgPath, fn.GetFunc().PkgName)
+
+			var after *Statement
+			if hasReceiver {
+				after = groupCase.Id("rece").Dot(fn.GetFunc().Name)
+			} else {
+				after = groupCase.Qual(fn.GetFunc().PkgPath, fn.GetFunc().Name)
+			}
+
+			after.CallFunc(
+				func(call *Group) {
+
+					tpFun := fn.GetFunc().GetOriginal().GetType().(*types.Signature)
+
+					zeroVals := gogentools.ScanTupleOfZeroValues(file, tpFun.Params(), fn.GetFunc().GetOriginal().IsVariadic())
+
+					for i, zero := range zeroVals {
+						if i == patternIndex {
+							call.Id(patternParam.VarName)
+						} else {
+							call.Add(zero)
+						}
+					}
+
+				},
+			).Add(TagPattern(patternParam.VarName))
+
+		})
+	return code
+}
+
+func generateMatch(file *File, fn x.FuncInterface, regexpIndex int, valueIndex int) *Statement {
+
+	regexpElem, _, regexpRelIndex, err := fn.GetRelativeElement(regexpIndex)
+	if err != nil {
+		panic(err)
+	}
+	valueElem, _, valueRelIndex, err := fn.GetRelativeElement(valueIndex)
+	if err != nil {
+		panic(err)
+	}
+
+	Receiver := feparser.ElementReceiver
+	Parameter := feparser.ElementParameter
+
+	if valueElem != Parameter {
+		Warnf("Value must be a parameter: %s", fn.GetFunc().Signature)
+		return nil
+	}
+	if regexpIndex == valueIndex {
+		return nil
+	}
+
+	fe := fn.GetFunc()
+	hasReceiver := fn.GetReceiver() != nil
+
+	if hasReceiver {
+		rece := fn.GetReceiver()
+		if regexpElem == Receiver {
+			rece.VarName = gogentools.NewNameWithPrefix(feparser.NewLowerTitleName("regexp", rece.TypeName))
+		} else {
+			rece.VarName = "rece"
+		}
+	}
+	if regexpElem == Parameter {
+		re := fe.Parameters[regexpRelIndex]
+		re.VarName = gogentools.NewNameWithPrefix(feparser.NewLowerTitleName("regexp", re.TypeName))
+	}
+	value := fe.Parameters[valueRelIndex]
+	value.VarName = gogentools.NewNameWithPrefix(feparser.NewLowerTitleName("value", value.TypeName))
+
+	code := BlockFunc(
+		func(groupCase *Group) {
+
+			if hasReceiver {
+				rece := fn.GetReceiver()
+				Comments(groupCase, "Declare medium object/interface:")
+				gogentools.ComposeVarDeclaration(file, groupCase, rece.VarName, rece.GetOriginal(), rece.Is.Variadic)
+			}
+			if regexpElem == Parameter {
+				re := fe.Parameters[regexpRelIndex]
+				Comments(groupCase, Sf("Declare `%s` variable:", re.VarName))
+				gogentools.ComposeVarDeclaration(file, groupCase, re.VarName, re.GetOriginal().GetType(), re.GetOriginal().IsVariadic())
+			}
+			ComposeTypeAssertion(file, groupCase, value.VarName, value.GetOriginal().GetType(), value.GetOriginal().IsVariadic())
+
+			gogentools.ImportPackage(file, fe.PkgPath, fe.PkgName)
+
+			var callee *Statement
+			if hasReceiver {
+				callee = groupCase.Id(fn.GetReceiver().VarName).Dot(fe.Name)
+			} else {
+				callee = groupCase.Qual(fe.PkgPath, fe.Name)
+			}
+			callee.CallFunc(
+				func(call *Group) {
+
+					tpFun := fe.GetOriginal().GetType().(*types.Signature)
+
+					zeroVals := gogentools.ScanTupleOfZeroValues(file, tpFun.Params(), fe.GetOriginal().IsVariadic())
+
+					for i, zero := range zeroVals {
+						isConsidered := (regexpElem == Parameter && i == regexpRelIndex) || i == valueRelIndex
+						if isConsidered {
+							call.Id(fe.Parameters[i].VarName)
+						} else {
+							call.Add(zero)
+						}
+					}
+
+				},
+			).Add(TagValue(value.VarName))
+		})
+	return code
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// declare `name := source(1).(Type)`
+func ComposeTypeAssertion(file *File, group *Group, varName string, typ types.Type, isVariadic bool) {
+	assertContent := newStatement()
+	if isVariadic {
+		if slice, ok := typ.(*types.Slice); ok {
+			gogentools.ComposeTypeDeclaration(file, assertContent, slice.Elem())
+		} else {
+			gogentools.ComposeTypeDeclaration(file, assertContent, typ)
+		}
+	} else {
+		gogentools.ComposeTypeDeclaration(file, assertContent, typ)
+	}
+	group.Id(varName).Op(":=").Id("source").Call().Assert(assertContent)
+}
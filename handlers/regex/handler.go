@@ -0,0 +1,106 @@
+package regex
+
+import (
+	"fmt"
+
+	"github.com/gagliardetto/codemill/x"
+	"github.com/gagliardetto/feparser"
+)
+
+// NOTE:
+// - Each func that you add to one of the MethodMatch* methods must also
+//   be added to the other two MethodMatch* methods.
+// - Each MethodMatch* selector must select exactly one element.
+
+const (
+	Kind x.ModelKind = "Regexp"
+)
+
+type Handler struct{}
+
+const (
+	MethodPattern = "{pattern:Param} <- $pattern" // The pattern parameter of a func that parses a regexp.
+
+	MethodMatchRegexp = "{regexp:Elem, value:Elem, result:Elem} <- $regexp" // The regexp (receiver or parameter) used by a matching func.
+	MethodMatchValue  = "{regexp:Elem, value:Elem, result:Elem} <- $value"  // The string being matched.
+	MethodMatchResult = "{regexp:Elem, value:Elem, result:Elem} <- $result" // The result of the match.
+)
+
+//
+func (han *Handler) ScavengeMethods() []*x.XMethod {
+	return x.ScavengeMethods(
+		MethodPattern, // "Select the pattern parameter of any function that parses (compiles) a regexp.",
+
+		// For regexp-matching funcs.
+		// Each function that you add to MethodMatchRegexp,
+		// you must also add it to MethodMatchValue and MethodMatchResult.
+		MethodMatchRegexp, // "Coupled 1/3: Select the regexp element (receiver/param) of a matching function.",
+		MethodMatchValue,  // "Coupled 2/3: Select the element (param) that is being matched.",
+		MethodMatchResult, // "Coupled 3/3: Select the element (result) that holds the outcome of the match.",
+	)
+}
+func (han *Handler) Validate(mdl *x.XModel) error {
+	defaultMthNum := len(han.ScavengeMethods())
+	if len(mdl.Methods) != defaultMthNum {
+		return fmt.Errorf("wrong number of methods; expected %v, got %v", defaultMthNum, len(mdl.Methods))
+	}
+	{
+		for i, must := range han.ScavengeMethods() {
+			if mdl.Methods[i].Name != must.Name {
+				return fmt.Errorf("#%v method is not called %s", i, must.Name)
+			}
+		}
+	}
+	mtdMatchRegexp := mdl.Methods.ByName(MethodMatchRegexp)
+	mtdMatchValue := mdl.Methods.ByName(MethodMatchValue)
+	mtdMatchResult := mdl.Methods.ByName(MethodMatchResult)
+	{
+		if err := x.ValidateParams(mdl.Methods.ByName(MethodPattern), true); err != nil {
+			return err
+		}
+		// Each match method must select exactly one element of the expected type:
+		if err := x.ValidateSingleElement(mtdMatchRegexp, feparser.ElementReceiver, feparser.ElementParameter); err != nil {
+			return err
+		}
+		if err := x.ValidateSingleElement(mtdMatchValue, feparser.ElementParameter); err != nil {
+			return err
+		}
+		if err := x.ValidateSingleElement(mtdMatchResult, feparser.ElementResult); err != nil {
+			return err
+		}
+	}
+	{
+		// Each match selector must have a corresponding selector in the other coupled methods:
+		coupled := []*x.XMethod{
+			mtdMatchRegexp,
+			mtdMatchValue,
+			mtdMatchResult,
+		}
+		for _, mtd := range coupled {
+			for _, other := range coupled {
+				if err := x.ValidateCoupled(mtd, other); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// onlyTrueIndex returns the index of the only true value.
+func onlyTrueIndex(vals []bool) (int, error) {
+	index := -1
+	for i, v := range vals {
+		if !v {
+			continue
+		}
+		if index != -1 {
+			return -1, fmt.Errorf("more than one true value in %v", vals)
+		}
+		index = i
+	}
+	if index == -1 {
+		return -1, fmt.Errorf("no true value in %v", vals)
+	}
+	return index, nil
+}
@@ -0,0 +1,91 @@
+package sanitizerguard
+
+import (
+	"github.com/gagliardetto/codemill/x"
+	. "github.com/gagliardetto/cqlgen/jen"
+	"github.com/gagliardetto/feparser"
+	. "github.com/gagliardetto/utilz"
+)
+
+func (han *Handler) GenerateCodeQL(impAdder x.ImportAdder, mdl *x.XModel, rootModuleGroup *Group) error {
+	if err := mdl.Validate(); err != nil {
+		return err
+	}
+	if err := han.Validate(mdl); err != nil {
+		return err
+	}
+
+	// Assuming the validation has already been done:
+	mtdCheckedOnTrue := mdl.Methods.ByName(MethodCheckedOnTrue)
+	mtdCheckedOnFalse := mdl.Methods.ByName(MethodCheckedOnFalse)
+
+	{
+		// Add imports:
+		//impAdder.Import("DataFlow::PathGraph")
+	}
+
+	className := mdl.Name
+	allPathVersions := mdl.ListAllPathVersions()
+
+	cases := make([]Code, 0)
+	for _, branch := range []struct {
+		mtd     *x.XMethod
+		outcome string
+	}{
+		{mtdCheckedOnTrue, "true"},
+		{mtdCheckedOnFalse, "false"},
+	} {
+		if len(branch.mtd.Selectors) == 0 {
+			Infof("No selectors found for %q method.", branch.mtd.Name)
+			continue
+		}
+		code, count := x.CqlCallTargets(allPathVersions, branch.mtd, "this", "Sanitizer guard models", x.CqlBindArgument("this", "checkedNode"))
+		if count > 0 {
+			cases = append(cases,
+				Parens(
+					Parens(code).And().Id("branch").Eq().Id(branch.outcome),
+				),
+			)
+		}
+	}
+	if len(cases) == 0 {
+		return nil
+	}
+
+	{
+		funcModelsClassName := feparser.NewCodeQlName(className)
+
+		tmp := DoGroup(func(tempFuncsModel *Group) {
+			tempFuncsModel.Doc(
+				"Models sanitizer guards.",
+				"The guard is a call that validates one of its arguments, and returns a boolean.",
+			)
+			tempFuncsModel.Private().Class().Id(funcModelsClassName).Extends().List(
+				Id("TaintTracking::SanitizerGuard"),
+				Id("DataFlow::CallNode"),
+			).BlockFunc(
+				func(funcModelsClassGroup *Group) {
+					funcModelsClassGroup.String().Id("package").Semicolon().Line()
+					funcModelsClassGroup.Id("DataFlow::Node").Id("checkedNode").Semicolon().Line()
+					funcModelsClassGroup.Id("boolean").Id("branch").Semicolon().Line()
+
+					funcModelsClassGroup.Id(funcModelsClassName).Call().Block(
+						Join(
+							Or(),
+							cases...,
+						),
+					)
+
+					funcModelsClassGroup.Override().Predicate().Id("checks").Call(Id("Expr").Id("e"), Id("boolean").Id("outcome")).BlockFunc(
+						func(overrideBlockGroup *Group) {
+							overrideBlockGroup.Id("e").Eq().Id("checkedNode").Dot("asExpr").Call()
+							overrideBlockGroup.And()
+							overrideBlockGroup.Id("outcome").Eq().Id("branch")
+						})
+				})
+		})
+		rootModuleGroup.Add(tmp)
+	}
+
+	return nil
+}
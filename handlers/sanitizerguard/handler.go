@@ -0,0 +1,80 @@
+package sanitizerguard
+
+import (
+	"fmt"
+	"go/types"
+
+	"github.com/gagliardetto/codemill/x"
+)
+
+// NOTE:
+// - The selected funcs must return a single boolean.
+// - The checked element must be exactly one parameter.
+
+const (
+	Kind x.ModelKind = "TaintTracking::SanitizerGuard"
+)
+
+type Handler struct{}
+
+const (
+	MethodCheckedOnTrue  = "{checked:Param, branch:true} <- $checked"  // The parameter that is validated when the func returns true.
+	MethodCheckedOnFalse = "{checked:Param, branch:false} <- $checked" // The parameter that is validated when the func returns false.
+)
+
+//
+func (han *Handler) ScavengeMethods() []*x.XMethod {
+	return x.ScavengeMethods(
+		MethodCheckedOnTrue,  // "Select the parameter that is validated; the guard holds when the func returns true (e.g. `IsURL`).",
+		MethodCheckedOnFalse, // "Select the parameter that is validated; the guard holds when the func returns false (e.g. `IsUnsafe`).",
+	)
+}
+func (han *Handler) Validate(mdl *x.XModel) error {
+	defaultMthNum := len(han.ScavengeMethods())
+	if len(mdl.Methods) != defaultMthNum {
+		return fmt.Errorf("wrong number of methods; expected %v, got %v", defaultMthNum, len(mdl.Methods))
+	}
+	{
+		for i, must := range han.ScavengeMethods() {
+			if mdl.Methods[i].Name != must.Name {
+				return fmt.Errorf("#%v method is not called %s", i, must.Name)
+			}
+		}
+	}
+	{
+		// The checked element must be exactly one parameter,
+		// and the func must return a single boolean:
+		for _, mtd := range mdl.Methods {
+			if err := x.ValidateParams(mtd, true); err != nil {
+				return err
+			}
+			for _, sel := range mtd.Selectors {
+				qual := sel.GetFuncQualifier()
+				if !returnsSingleBool(x.GetFuncByQualifier(qual)) {
+					return fmt.Errorf("%s: the func selected in %s must return a single boolean", qual.ID, mtd.Name)
+				}
+			}
+		}
+	}
+	{
+		// A func cannot be a guard on both branches:
+		mtdOnFalse := mdl.Methods.ByName(MethodCheckedOnFalse)
+		for _, sel := range mdl.Methods.ByName(MethodCheckedOnTrue).Selectors {
+			qual := sel.GetFuncQualifier()
+			if mtdOnFalse.GetFuncSelector(qual.Path, qual.Version, qual.ID) != nil {
+				return fmt.Errorf("%s: selected in both %s and %s", qual.ID, MethodCheckedOnTrue, MethodCheckedOnFalse)
+			}
+		}
+	}
+	return nil
+}
+
+// returnsSingleBool returns true if the func has exactly one result, of boolean type.
+func returnsSingleBool(fn x.FuncInterface) bool {
+	sig, ok := fn.GetFunc().GetOriginal().GetType().(*types.Signature)
+	if !ok || sig.Results().Len() != 1 {
+		return false
+	}
+	basic, ok := sig.Results().At(0).Type().Underlying().(*types.Basic)
+	return ok && basic.Info()&types.IsBoolean != 0
+}
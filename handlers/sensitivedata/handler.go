@@ -0,0 +1,99 @@
+package sensitivedata
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gagliardetto/codemill/x"
+)
+
+// NOTE:
+// - The selectors are the same as the ones of untrustedflowsource.Handler,
+//   but only func results (and not parameters or receivers) can be selected.
+// - Only funcs with a single result can be selected.
+
+const (
+	Kind x.ModelKind = "SensitiveData"
+)
+
+type Handler struct{}
+
+const (
+	MethodSelf = "{sensitive:[](Result|Fields|Type)} <- $sensitive"
+)
+
+const (
+	OptionClassification = "Classification" // One of `password`, `secret`, `id`, `certificate`.
+)
+
+// Classifications are the supported classifications of sensitive data
+// (see `SensitiveExpr::Classification`).
+var Classifications = []string{
+	"password",
+	"secret",
+	"id",
+	"certificate",
+}
+
+//
+func (han *Handler) ScavengeMethods() []*x.XMethod {
+	return []*x.XMethod{
+		{
+			Name:      MethodSelf,
+			Selectors: []*x.XSelector{},
+		},
+	}
+}
+
+//
+func (han *Handler) ScavengeOptions() map[string]string {
+	return map[string]string{
+		OptionClassification: "",
+	}
+}
+func (han *Handler) Validate(mdl *x.XModel) error {
+	if len(mdl.Methods) != 1 {
+		return fmt.Errorf("wrong number of methods; expected 1, got %v", len(mdl.Methods))
+	}
+	if mdl.Methods[0].Name != MethodSelf {
+		return fmt.Errorf("First method is not called %s", MethodSelf)
+	}
+	classification := GetClassification(mdl)
+	if classification == "" {
+		return fmt.Errorf("option %s is not set", OptionClassification)
+	}
+	if !isValidClassification(classification) {
+		return fmt.Errorf("unknown classification %q; supported: %s", classification, strings.Join(Classifications, ", "))
+	}
+	for _, sel := range mdl.Methods[0].Selectors {
+		qual := sel.GetFuncQualifier()
+		if qual == nil {
+			continue
+		}
+		fn := x.GetFuncByQualifier(qual)
+		receiver, parameterIndexes, _ := x.PosToRelativeIndexes(fn, qual.Pos)
+		if receiver || len(parameterIndexes) > 0 {
+			return fmt.Errorf("%s: only results can be selected", fn.GetFunc().Name)
+		}
+		// A SensitiveExpr is an expression, and the results of a call
+		// returning multiple values are not expressions:
+		if len(fn.GetFunc().Results) > 1 {
+			return fmt.Errorf("%s: only funcs with a single result can be selected", fn.GetFunc().Name)
+		}
+	}
+	return nil
+}
+
+// GetClassification returns the classification set in the model option.
+func GetClassification(mdl *x.XModel) string {
+	return strings.TrimSpace(mdl.GetOption(OptionClassification))
+}
+
+func isValidClassification(classification string) bool {
+	for _, v := range Classifications {
+		if v == classification {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,54 @@
+package querystring
+
+import (
+	"github.com/gagliardetto/codemill/x"
+)
+
+var (
+	GenerateBoilerplate bool
+)
+
+const (
+	// NOTE: hardcoded inside TestQueryContent const.
+	InlineExpectationsTestTag = "$querystring" // Must start with a $ sign.
+)
+
+const (
+	TestQueryContent = `
+import go
+import TestUtilities.InlineExpectationsTest
+
+class SqlQueryStringTest extends InlineExpectationsTest {
+  SqlQueryStringTest() { this = "SqlQueryStringTest" }
+
+  override string getARelevantTag() { result = "querystring" }
+
+  override predicate hasActualResult(string file, int line, string element, string tag, string value) {
+    tag = "querystring" and
+    exists(SQL::QueryString qs |
+      qs.hasLocationInfo(file, line, _, _, _) and
+      element = qs.toString() and
+      value = qs.toString()
+    )
+  }
+}
+`
+)
+
+var (
+	IncludeCommentsInGeneratedGo bool
+)
+
+func (han *Handler) GenerateGo(parentDir string, mdl *x.XModel) error {
+	if err := mdl.Validate(); err != nil {
+		return err
+	}
+	if err := han.Validate(mdl); err != nil {
+		return err
+	}
+
+	sink := *paramSink
+	sink.GenerateBoilerplate = GenerateBoilerplate
+	sink.IncludeComments = IncludeCommentsInGeneratedGo
+	return sink.GenerateGo(parentDir, mdl, mdl.Methods.ByName(MethodQueryString))
+}
@@ -0,0 +1,48 @@
+package querystring
+
+import (
+	"fmt"
+
+	"github.com/gagliardetto/codemill/x"
+)
+
+const (
+	Kind x.ModelKind = "SQL::QueryString"
+)
+
+type Handler struct{}
+
+const (
+	MethodQueryString = "{querystring:Param} <- $querystring"
+)
+
+//
+func (han *Handler) ScavengeMethods() []*x.XMethod {
+	return []*x.XMethod{
+		{
+			Name:      MethodQueryString,
+			Selectors: []*x.XSelector{},
+		},
+	}
+}
+func (han *Handler) Validate(mdl *x.XModel) error {
+	if len(mdl.Methods) != 1 {
+		return fmt.Errorf("wrong number of methods; expected 1, got %v", len(mdl.Methods))
+	}
+	{
+		if mdl.Methods[0].Name != MethodQueryString {
+			return fmt.Errorf("#0 method is not called %s", MethodQueryString)
+		}
+	}
+	return nil
+}
+
+// paramSink generates the models and the tests for the MethodQueryString selectors.
+var paramSink = &x.ParamSink{
+	Doc:              "Models SQL query strings.",
+	Extends:          "SQL::QueryString::Range",
+	Comment:          "SQL query string",
+	TestTag:          InlineExpectationsTestTag,
+	TestQueryContent: TestQueryContent,
+	VarPrefix:        "query",
+}
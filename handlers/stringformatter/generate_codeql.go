@@ -0,0 +1,240 @@
+package stringformatter
+
+import (
+	"github.com/gagliardetto/codemill/x"
+	. "github.com/gagliardetto/cqlgen/jen"
+	"github.com/gagliardetto/feparser"
+	. "github.com/gagliardetto/utilz"
+)
+
+func (han *Handler) GenerateCodeQL(impAdder x.ImportAdder, mdl *x.XModel, rootModuleGroup *Group) error {
+	if err := mdl.Validate(); err != nil {
+		return err
+	}
+	if err := han.Validate(mdl); err != nil {
+		return err
+	}
+
+	// Assuming the validation has already been done:
+	mtdFormatString := mdl.Methods.ByName(MethodFormatString)
+	mtdFirstFormatted := mdl.Methods.ByName(MethodFirstFormatted)
+
+	if len(mtdFormatString.Selectors) == 0 {
+		Infof("No selectors found for %q method.", mtdFormatString.Name)
+		return nil
+	}
+
+	{
+		// Add imports:
+		//impAdder.Import("DataFlow::PathGraph")
+	}
+
+	className := mdl.Name
+	allPathVersions := mdl.ListAllPathVersions()
+
+	b2fe, b2tm, b2itm, err := x.GroupFuncSelectors(mtdFormatString)
+	if err != nil {
+		Fatalf("Error while GroupFuncSelectors: %s", err)
+	}
+
+	// formatterCode composes the conditions on the format string index,
+	// on the first formatted argument index, and on the output of the func.
+	formatterCode := func(fn x.FuncInterface, formatQual *x.FuncQualifier) Code {
+		firstFormattedQual := mtdFirstFormatted.GetFuncSelector(formatQual.Path, formatQual.Version, formatQual.ID)
+		formatIndex, firstFormattedIndex := getIndexes(fn, formatQual, firstFormattedQual)
+
+		code := Id("formatIndex").Eq().Lit(formatIndex).
+			And().
+			Id("firstFormattedIndex").Eq().Lit(firstFormattedIndex).
+			And()
+		if len(fn.GetFunc().Results) > 1 {
+			code.Id("outp").Dot("isResult").Call(Lit(0))
+		} else {
+			code.Id("outp").Dot("isResult").Call()
+		}
+		return code
+	}
+
+	addOverrides := func(classGroup *Group) {
+		classGroup.Override().Int().Id("getFormatStringIndex").Call().Block(
+			Id("result").Eq().Id("formatIndex"),
+		)
+		classGroup.Override().Int().Id("getFirstFormattedParameterIndex").Call().Block(
+			Id("result").Eq().Id("firstFormattedIndex"),
+		)
+		classGroup.Override().Predicate().Id("hasTaintFlow").Call(Id("FunctionInput").Id("input"), Id("FunctionOutput").Id("output")).BlockFunc(
+			func(overrideBlockGroup *Group) {
+				overrideBlockGroup.Id("input").Dot("isParameter").Call(
+					Any(
+						Add(Int(), Id("i")),
+						Add(Id("i").Eq().Id("formatIndex").Or().Id("i").Gte().Id("firstFormattedIndex")),
+						nil,
+					),
+				).And().Id("output").Eq().Id("outp")
+			})
+	}
+
+	addFields := func(classGroup *Group) {
+		classGroup.Int().Id("formatIndex").Semicolon().Line()
+		classGroup.Int().Id("firstFormattedIndex").Semicolon().Line()
+		classGroup.Id("FunctionOutput").Id("outp").Semicolon().Line()
+	}
+
+	{
+		funcModelsClassName := feparser.NewCodeQlName(className, "FunctionModels")
+		addedCount := 0
+		tmp := DoGroup(func(tempFuncsModel *Group) {
+			tempFuncsModel.Doc(
+				"Models printf-style string formatting functions;",
+				"the format string and the formatted arguments flow to the result.",
+			)
+			tempFuncsModel.Private().Class().Id(funcModelsClassName).Extends().List(
+				Id("StringOps::Formatting::StringFormatter::Range"),
+				Id("TaintTracking::FunctionModel"),
+			).BlockFunc(
+				func(funcModelsClassGroup *Group) {
+					addFields(funcModelsClassGroup)
+
+					funcModelsClassGroup.Id(funcModelsClassName).Call().BlockFunc(
+						func(funcModelsSelfMethodGroup *Group) {
+							funcModelsSelfMethodGroup.DoGroup(
+								func(groupCase *Group) {
+									for _, pathVersion := range allPathVersions {
+										cont, ok := b2fe[pathVersion]
+										if !ok {
+											continue
+										}
+										pathCodez := make([]Code, 0)
+										for _, funcQual := range cont {
+											if AllFalse(funcQual.Pos...) {
+												continue
+											}
+											fn := x.GetFuncByQualifier(funcQual)
+											thing := fn.(*feparser.FEFunc)
+											pathCodez = append(pathCodez,
+												ParensFunc(
+													func(par *Group) {
+														par.Commentf("signature: %s", thing.Signature)
+														par.This().Dot("hasQualifiedName").Call(x.CqlFormatPackagePath(funcQual.Path), Lit(thing.Name))
+														par.And()
+														par.Add(formatterCode(fn, funcQual))
+													},
+												),
+											)
+										}
+
+										if len(pathCodez) > 0 {
+											if addedCount > 0 {
+												groupCase.Or()
+											}
+											groupCase.Commentf("String formatter models for package: %s", pathVersion).Parens(
+												Join(
+													Or(),
+													pathCodez...,
+												),
+											)
+											addedCount++
+										}
+									}
+								})
+						})
+
+					addOverrides(funcModelsClassGroup)
+				})
+		})
+		if addedCount > 0 {
+			rootModuleGroup.Add(tmp)
+		}
+	}
+
+	{
+		methodModelsClassName := feparser.NewCodeQlName(className, "MethodModels")
+		addedCount := 0
+		tmp := DoGroup(func(tempMethodsModel *Group) {
+			tempMethodsModel.Doc(
+				"Models printf-style string formatting methods;",
+				"the format string and the formatted arguments flow to the result.",
+			)
+			tempMethodsModel.Private().Class().Id(methodModelsClassName).Extends().List(
+				Id("Method"),
+				Id("StringOps::Formatting::StringFormatter::Range"),
+				Id("TaintTracking::FunctionModel"),
+			).BlockFunc(
+				func(methodModelsClassGroup *Group) {
+					addFields(methodModelsClassGroup)
+
+					methodModelsClassGroup.Id(methodModelsClassName).Call().BlockFunc(
+						func(methodModelsSelfMethodGroup *Group) {
+							methodModelsSelfMethodGroup.DoGroup(
+								func(groupCase *Group) {
+									for _, pathVersion := range allPathVersions {
+										pathCodez := make([]Code, 0)
+
+										b2tm.IterValid(pathVersion,
+											func(receiverTypeID string, methodQualifiers x.FuncQualifierSlice) {
+												for _, methodQual := range methodQualifiers {
+													if AllFalse(methodQual.Pos...) {
+														continue
+													}
+													fn := x.GetFuncByQualifier(methodQual)
+													thing := fn.(*feparser.FETypeMethod)
+													pathCodez = append(pathCodez,
+														ParensFunc(
+															func(par *Group) {
+																par.Commentf("signature: %s", thing.Func.Signature)
+																par.This().Dot("hasQualifiedName").Call(x.CqlFormatPackagePath(methodQual.Path), Lit(thing.Receiver.TypeName), Lit(thing.Func.Name))
+																par.And()
+																par.Add(formatterCode(fn, methodQual))
+															},
+														),
+													)
+												}
+											})
+
+										b2itm.IterValid(pathVersion,
+											func(receiverTypeID string, methodQualifiers x.FuncQualifierSlice) {
+												for _, methodQual := range methodQualifiers {
+													if AllFalse(methodQual.Pos...) {
+														continue
+													}
+													fn := x.GetFuncByQualifier(methodQual)
+													thing := fn.(*feparser.FEInterfaceMethod)
+													pathCodez = append(pathCodez,
+														ParensFunc(
+															func(par *Group) {
+																par.Commentf("signature: %s", thing.Func.Signature)
+																par.This().Dot("implements").Call(x.CqlFormatPackagePath(methodQual.Path), Lit(thing.Receiver.TypeName), Lit(thing.Func.Name))
+																par.And()
+																par.Add(formatterCode(fn, methodQual))
+															},
+														),
+													)
+												}
+											})
+
+										if len(pathCodez) > 0 {
+											if addedCount > 0 {
+												groupCase.Or()
+											}
+											groupCase.Commentf("String formatter models for package: %s", pathVersion).Parens(
+												Join(
+													Or(),
+													pathCodez...,
+												),
+											)
+											addedCount++
+										}
+									}
+								})
+						})
+
+					addOverrides(methodModelsClassGroup)
+				})
+		})
+		if addedCount > 0 {
+			rootModuleGroup.Add(tmp)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,81 @@
+package stringformatter
+
+import (
+	"fmt"
+
+	"github.com/gagliardetto/codemill/x"
+)
+
+// NOTE:
+// - Each func that you add to MethodFormatString must also be added to MethodFirstFormatted (and vice versa).
+// - Both the format string and the first formatted argument must be exactly one parameter,
+//   and the format string must come before the first formatted argument.
+// - The format string and all the arguments starting from the first formatted one
+//   (e.g. all the variadic arguments) flow to the result.
+
+const (
+	Kind x.ModelKind = "StringOps::Formatting::StringFormatter"
+)
+
+type Handler struct{}
+
+const (
+	MethodFormatString   = "{format:Param, args:Param} <- $format" // The format string.
+	MethodFirstFormatted = "{format:Param, args:Param} <- $args"   // The first formatted argument.
+)
+
+//
+func (han *Handler) ScavengeMethods() []*x.XMethod {
+	return x.ScavengeMethods(
+		// Each func that you add to MethodFormatString,
+		// you must also add it to MethodFirstFormatted.
+		MethodFormatString,   // "Coupled 1/2: Select the parameter that specifies the format string.",
+		MethodFirstFormatted, // "Coupled 2/2: Select the parameter that specifies the first formatted argument.",
+	)
+}
+func (han *Handler) Validate(mdl *x.XModel) error {
+	defaultMthNum := len(han.ScavengeMethods())
+	if len(mdl.Methods) != defaultMthNum {
+		return fmt.Errorf("wrong number of methods; expected %v, got %v", defaultMthNum, len(mdl.Methods))
+	}
+	{
+		for i, must := range han.ScavengeMethods() {
+			if mdl.Methods[i].Name != must.Name {
+				return fmt.Errorf("#%v method is not called %s", i, must.Name)
+			}
+		}
+	}
+	{
+		// Each format string selector must have a corresponding first formatted argument selector (and vice versa):
+		mtdFormatString := mdl.Methods.ByName(MethodFormatString)
+		mtdFirstFormatted := mdl.Methods.ByName(MethodFirstFormatted)
+		for _, mtd := range []*x.XMethod{mtdFormatString, mtdFirstFormatted} {
+			if err := x.ValidateParams(mtd, true); err != nil {
+				return err
+			}
+		}
+		if err := x.ValidateCoupled(mtdFormatString, mtdFirstFormatted); err != nil {
+			return err
+		}
+		if err := x.ValidateCoupled(mtdFirstFormatted, mtdFormatString); err != nil {
+			return err
+		}
+		// The format string must come before the first formatted argument:
+		for _, sel := range mtdFormatString.Selectors {
+			formatQual := sel.GetFuncQualifier()
+			firstFormattedQual := mtdFirstFormatted.GetFuncSelector(formatQual.Path, formatQual.Version, formatQual.ID)
+			formatIndex, firstFormattedIndex := getIndexes(x.GetFuncByQualifier(formatQual), formatQual, firstFormattedQual)
+			if formatIndex >= firstFormattedIndex {
+				return fmt.Errorf("%s: the format string (%v) must come before the first formatted argument (%v)", formatQual.ID, formatIndex, firstFormattedIndex)
+			}
+		}
+	}
+	return nil
+}
+
+// getIndexes returns the index of the format string parameter,
+// and the index of the first formatted argument parameter;
+// validation must have already made sure that exactly one parameter is selected in each.
+func getIndexes(fn x.FuncInterface, formatQual *x.FuncQualifier, firstFormattedQual *x.FuncQualifier) (int, int) {
+	return x.MustPosToRelativeParamIndexes(fn, formatQual.Pos)[0], x.MustPosToRelativeParamIndexes(fn, firstFormattedQual.Pos)[0]
+}
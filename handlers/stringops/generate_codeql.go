@@ -0,0 +1,149 @@
+package stringops
+
+import (
+	"github.com/gagliardetto/codemill/x"
+	. "github.com/gagliardetto/cqlgen/jen"
+	"github.com/gagliardetto/feparser"
+	. "github.com/gagliardetto/utilz"
+)
+
+func (han *Handler) GenerateCodeQL(impAdder x.ImportAdder, mdl *x.XModel, rootModuleGroup *Group) error {
+	if err := mdl.Validate(); err != nil {
+		return err
+	}
+	if err := han.Validate(mdl); err != nil {
+		return err
+	}
+
+	// Assuming the validation has already been done:
+	mtdHasPrefixBase := mdl.Methods.ByName(MethodHasPrefixBase)
+	mtdHasPrefixPrefix := mdl.Methods.ByName(MethodHasPrefixPrefix)
+	mtdConcatenationOperands := mdl.Methods.ByName(MethodConcatenationOperands)
+
+	{
+		// Add imports:
+		//impAdder.Import("DataFlow::PathGraph")
+	}
+
+	className := mdl.Name
+	allPathVersions := mdl.ListAllPathVersions()
+
+	if len(mtdHasPrefixBase.Selectors) == 0 {
+		Infof("No selectors found for %q method.", mtdHasPrefixBase.Name)
+	} else {
+		funcModelsClassName := feparser.NewCodeQlName(className, "HasPrefix")
+
+		hasPrefixCode, hasPrefixCount := x.CqlCallTargets(allPathVersions, mtdHasPrefixBase, "call", "String operation models",
+			func(fn x.FuncInterface, baseQual *x.FuncQualifier) Code {
+				prefixQual := mtdHasPrefixPrefix.GetFuncSelector(baseQual.Path, baseQual.Version, baseQual.ID)
+				_, baseCode := x.CqlParamQualToCode("call", "getArgument", baseQual)
+				_, prefixCode := x.CqlParamQualToCode("call", "getArgument", prefixQual)
+
+				return cql_ThisIsResult(fn, "call").
+					And().
+					Id("baseNode").Eq().Add(baseCode).
+					And().
+					Id("prefixNode").Eq().Add(prefixCode)
+			},
+		)
+
+		tmp := DoGroup(func(tempFuncsModel *Group) {
+			tempFuncsModel.Doc("Models calls that check whether a string has a prefix.")
+			tempFuncsModel.Private().Class().Id(funcModelsClassName).Extends().List(
+				Id("StringOps::HasPrefix::Range"),
+			).BlockFunc(
+				func(blockBody *Group) {
+					blockBody.String().Id("package").Semicolon().Line()
+					blockBody.Id("DataFlow::CallNode").Id("call").Semicolon().Line()
+					blockBody.Id("DataFlow::Node").Id("baseNode").Semicolon().Line()
+					blockBody.Id("DataFlow::Node").Id("prefixNode").Semicolon().Line()
+
+					blockBody.Id(funcModelsClassName).Call().Block(
+						hasPrefixCode,
+					)
+
+					blockBody.Override().Id("DataFlow::Node").Id("getBaseString").Call().Block(
+						Id("result").Eq().Id("baseNode"),
+					)
+					blockBody.Override().Id("DataFlow::Node").Id("getSubstring").Call().Block(
+						Id("result").Eq().Id("prefixNode"),
+					)
+				})
+		})
+		if hasPrefixCount > 0 {
+			rootModuleGroup.Add(tmp)
+		}
+	}
+
+	if len(mtdConcatenationOperands.Selectors) == 0 {
+		Infof("No selectors found for %q method.", mtdConcatenationOperands.Name)
+	} else {
+		funcModelsClassName := feparser.NewCodeQlName(className, "Concatenation")
+
+		concatenationCode, concatenationCount := x.CqlCallTargets(allPathVersions, mtdConcatenationOperands, "call", "String operation models",
+			func(fn x.FuncInterface, qual *x.FuncQualifier) Code {
+				indexes := x.MustPosToRelativeParamIndexes(fn, qual.Pos)
+				firstOperand := indexes[0]
+				lastOperand := indexes[len(indexes)-1]
+				_, lenParams, _ := fn.Lengths()
+				if fn.GetFunc().GetOriginal().Variadic && lastOperand == lenParams-1 {
+					// All the variadic arguments are operands:
+					lastOperand = -1
+				}
+
+				return cql_ThisIsResult(fn, "call").
+					And().
+					Id("firstOperand").Eq().Lit(firstOperand).
+					And().
+					Id("lastOperand").Eq().Lit(lastOperand)
+			},
+		)
+
+		tmp := DoGroup(func(tempFuncsModel *Group) {
+			tempFuncsModel.Doc(
+				"Models calls that concatenate (join) their operands.",
+				"The operands are the arguments from `firstOperand` to `lastOperand`;",
+				"if `lastOperand` is -1, all the arguments starting from `firstOperand` are operands.",
+			)
+			tempFuncsModel.Private().Class().Id(funcModelsClassName).Extends().List(
+				Id("StringOps::Concatenation::Range"),
+			).BlockFunc(
+				func(blockBody *Group) {
+					blockBody.String().Id("package").Semicolon().Line()
+					blockBody.Id("DataFlow::CallNode").Id("call").Semicolon().Line()
+					blockBody.Int().Id("firstOperand").Semicolon().Line()
+					blockBody.Int().Id("lastOperand").Semicolon().Line()
+
+					blockBody.Id(funcModelsClassName).Call().Block(
+						concatenationCode,
+					)
+
+					blockBody.Override().Id("DataFlow::Node").Id("getOperand").Call(Int().Id("n")).BlockFunc(
+						func(overrideBlockGroup *Group) {
+							overrideBlockGroup.Id("n").Gte().Lit(0)
+							overrideBlockGroup.And()
+							overrideBlockGroup.Id("result").Eq().Id("call").Dot("getArgument").Call(Id("firstOperand").Op("+").Id("n"))
+							overrideBlockGroup.And()
+							overrideBlockGroup.Parens(
+								Id("lastOperand").Eq().Lit(-1).
+									Or().
+									Id("firstOperand").Op("+").Id("n").Op("<=").Id("lastOperand"),
+							)
+						})
+				})
+		})
+		if concatenationCount > 0 {
+			rootModuleGroup.Add(tmp)
+		}
+	}
+
+	return nil
+}
+
+// cql_ThisIsResult binds `this` to the (first) result of the call.
+func cql_ThisIsResult(fn x.FuncInterface, callName string) *Statement {
+	if len(fn.GetFunc().Results) > 1 {
+		return This().Eq().Id(callName).Dot("getResult").Call(Lit(0))
+	}
+	return This().Eq().Id(callName).Dot("getResult").Call()
+}
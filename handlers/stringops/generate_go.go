@@ -0,0 +1,598 @@
+package stringops
+
+import (
+	"go/types"
+	"os"
+	"path/filepath"
+
+	. "github.com/dave/jennifer/jen"
+	"github.com/gagliardetto/codebox/gogentools"
+	"github.com/gagliardetto/codemill/x"
+	"github.com/gagliardetto/feparser"
+	. "github.com/gagliardetto/utilz"
+)
+
+var (
+	GenerateBoilerplate bool
+)
+
+const (
+	// NOTE: hardcoded inside TestQueryContent const.
+	InlineExpectationsTestTagHasPrefix     = "$hasPrefix"     // Must start with a $ sign.
+	InlineExpectationsTestTagConcatOperand = "$concatOperand" // Must start with a $ sign.
+	InlineExpectationsTestTagTaintSink     = "$taintSink"     // Must start with a $ sign.
+)
+
+// Tag composes a comment with the provided tag, followed by each one of the values (if any).
+func Tag(tag string, values ...string) Code {
+	if len(values) == 0 {
+		return Comment(tag)
+	}
+	tg := ""
+	for i, v := range values {
+		if i > 0 {
+			tg += " "
+		}
+		tg += tag + "=" + v
+	}
+	return Comment(tg)
+}
+
+const (
+	TestQueryContent = `
+import go
+import TestUtilities.InlineExpectationsTest
+
+class PrefixCheck extends TaintTracking::SanitizerGuard {
+  StringOps::HasPrefix hp;
+
+  PrefixCheck() { this = hp }
+
+  override predicate checks(Expr e, boolean outcome) {
+    e = hp.getBaseString().asExpr() and outcome = hp.getPolarity()
+  }
+}
+
+class Configuration extends TaintTracking::Configuration {
+  Configuration() { this = "test-configuration" }
+
+  override predicate isSource(DataFlow::Node source) {
+    exists(Function fn | fn.hasQualifiedName(_, "source") | source = fn.getACall().getResult())
+  }
+
+  override predicate isSink(DataFlow::Node sink) {
+    exists(Function fn | fn.hasQualifiedName(_, "sink") | sink = fn.getACall().getAnArgument())
+  }
+
+  override predicate isSanitizerGuard(DataFlow::BarrierGuard guard) { guard instanceof PrefixCheck }
+}
+
+class StringOpsTest extends InlineExpectationsTest {
+  StringOpsTest() { this = "StringOpsTest" }
+
+  override string getARelevantTag() { result = ["hasPrefix", "concatOperand", "taintSink"] }
+
+  override predicate hasActualResult(string file, int line, string element, string tag, string value) {
+    tag = "hasPrefix" and
+    exists(StringOps::HasPrefix hp |
+      hp.hasLocationInfo(file, line, _, _, _) and
+      element = hp.getBaseString().toString() and
+      value = hp.getBaseString().toString()
+    )
+    or
+    tag = "concatOperand" and
+    exists(StringOps::Concatenation c, DataFlow::Node operand | operand = c.getOperand(_) |
+      c.hasLocationInfo(file, line, _, _, _) and
+      element = operand.toString() and
+      value = operand.toString()
+    )
+    or
+    tag = "taintSink" and
+    exists(DataFlow::Node sink | any(Configuration c).hasFlow(_, sink) |
+      element = sink.toString() and
+      value = "" and
+      sink.hasLocationInfo(file, line, _, _, _)
+    )
+  }
+}
+`
+)
+
+func NewTestFile(includeBoilerplace bool) *File {
+	file := NewFile("main")
+	// Set a prefix to avoid collision between variable names and packages:
+	file.PackagePrefix = "cql"
+	// Add comment to file:
+	file.HeaderComment("Code generated by https://github.com/gagliardetto. DO NOT EDIT.")
+
+	if includeBoilerplace {
+		file.PackageComment("//go:generate depstubber --vendor --auto")
+		{
+			// main function:
+			file.Func().Id("main").Params().Block()
+		}
+		{
+			// sink function:
+			code := Func().
+				Id("sink").
+				Params(Id("v").Op("...").Interface()).
+				Block()
+			file.Add(code.Line())
+		}
+		{
+			// The `source` function returns a new tainted thing:
+			code := Func().
+				Id("source").
+				Params().
+				Interface().
+				Block(Return(Nil()))
+			file.Add(code.Line())
+		}
+	}
+	return file
+}
+
+var (
+	IncludeCommentsInGeneratedGo bool
+)
+
+func (han *Handler) GenerateGo(parentDir string, mdl *x.XModel) error {
+	if err := mdl.Validate(); err != nil {
+		return err
+	}
+	if err := han.Validate(mdl); err != nil {
+		return err
+	}
+
+	// Check if there are multiple versions of a same package:
+	mods := mdl.ListModules()
+	if x.HasMultiversion(mods) {
+		Ln(RedBG("Has multiversion"))
+	}
+	// If there are no multiple versions of the same module,
+	// that means we can save all the code to one file.
+	allInOneFile := !x.HasMultiversion(mods)
+
+	// Create the directory for the tests for this model:
+	outDir := filepath.Join(parentDir, feparser.NewCodeQlName(mdl.Name))
+	MustCreateFolderIfNotExists(outDir, os.ModePerm)
+
+	// Assuming the validation has already been done:
+	mtdHasPrefixBase := mdl.Methods.ByName(MethodHasPrefixBase)
+	mtdHasPrefixPrefix := mdl.Methods.ByName(MethodHasPrefixPrefix)
+	mtdConcatenationOperands := mdl.Methods.ByName(MethodConcatenationOperands)
+
+	if len(mtdHasPrefixBase.Selectors) == 0 && len(mtdConcatenationOperands.Selectors) == 0 {
+		Infof("No selectors found for %q and %q methods.", mtdHasPrefixBase.Name, mtdConcatenationOperands.Name)
+		return nil
+	}
+
+	allPathVersions := mdl.ListAllPathVersions()
+
+	file := NewTestFile(GenerateBoilerplate)
+
+	b2feHasPrefix, b2tmHasPrefix, b2itmHasPrefix, err := x.GroupFuncSelectors(mtdHasPrefixBase)
+	if err != nil {
+		Fatalf("Error while GroupFuncSelectors: %s", err)
+	}
+	b2feConcatenation, b2tmConcatenation, b2itmConcatenation, err := x.GroupFuncSelectors(mtdConcatenationOperands)
+	if err != nil {
+		Fatalf("Error while GroupFuncSelectors: %s", err)
+	}
+
+	for _, pathVersion := range allPathVersions {
+		if !allInOneFile {
+			// Reset file:
+			file = NewTestFile(GenerateBoilerplate)
+		}
+		codez := make([]Code, 0)
+
+		// Prefix checks:
+		codez = append(codez,
+			generateGoTestCodez(file, pathVersion, "String prefix check", b2feHasPrefix, b2tmHasPrefix, b2itmHasPrefix,
+				func(file *File, fn x.FuncInterface, baseQual *x.FuncQualifier, receiverTypeID string) []Code {
+					prefixQual := mtdHasPrefixPrefix.GetFuncSelector(baseQual.Path, baseQual.Version, baseQual.ID)
+					return wrapBlock(
+						fn,
+						generateHasPrefix(
+							file,
+							fn,
+							x.MustPosToRelativeParamIndexes(fn, baseQual.Pos)[0],
+							x.MustPosToRelativeParamIndexes(fn, prefixQual.Pos)[0],
+						),
+					)
+				},
+			)...,
+		)
+		// Concatenations:
+		codez = append(codez,
+			generateGoTestCodez(file, pathVersion, "String concatenation", b2feConcatenation, b2tmConcatenation, b2itmConcatenation,
+				func(file *File, fn x.FuncInterface, qual *x.FuncQualifier, receiverTypeID string) []Code {
+					return wrapBlock(
+						fn,
+						generateConcatenation(
+							file,
+							fn,
+							x.MustPosToRelativeParamIndexes(fn, qual.Pos),
+						),
+					)
+				},
+			)...,
+		)
+
+		{
+			file.Commentf("Package %s", pathVersion)
+			file.Func().Id(mdl.Name + "_" + feparser.FormatCodeQlName(pathVersion)).Params().Block(codez...)
+		}
+
+		if !allInOneFile {
+			pkgDstDirpath := filepath.Join(outDir, feparser.FormatID(mdl.Name, "For", feparser.FormatCodeQlName(pathVersion)))
+			MustCreateFolderIfNotExists(pkgDstDirpath, os.ModePerm)
+
+			assetFileName := feparser.FormatID(mdl.Name, "For", feparser.FormatCodeQlName(pathVersion)) + ".go"
+			if err := x.SaveGoFile(pkgDstDirpath, assetFileName, file); err != nil {
+				Fatalf("Error while saving go file: %s", err)
+			}
+
+			if err := x.WriteGoModFile(pkgDstDirpath, pathVersion); err != nil {
+				Fatalf("Error while saving go.mod file: %s", err)
+			}
+			if err := x.WriteCodeQLTestQuery(pkgDstDirpath, mdl.Name, TestQueryContent); err != nil {
+				Fatalf("Error while saving <name>.ql file: %s", err)
+			}
+			if err := x.WriteEmptyCodeQLDotExpectedFile(pkgDstDirpath, mdl.Name); err != nil {
+				Fatalf("Error while saving <name>.expected file: %s", err)
+			}
+		}
+	}
+
+	if allInOneFile {
+		pkgDstDirpath := outDir
+		MustCreateFolderIfNotExists(pkgDstDirpath, os.ModePerm)
+
+		assetFileName := feparser.FormatID(mdl.Name) + ".go"
+		if err := x.SaveGoFile(pkgDstDirpath, assetFileName, file); err != nil {
+			Fatalf("Error while saving go file: %s", err)
+		}
+
+		if err := x.WriteGoModFile(pkgDstDirpath, allPathVersions...); err != nil {
+			Fatalf("Error while saving go.mod file: %s", err)
+		}
+		if err := x.WriteCodeQLTestQuery(pkgDstDirpath, mdl.Name, TestQueryContent); err != nil {
+			Fatalf("Error while saving <name>.ql file: %s", err)
+		}
+		if err := x.WriteEmptyCodeQLDotExpectedFile(pkgDstDirpath, mdl.Name); err != nil {
+			Fatalf("Error while saving <name>.expected file: %s", err)
+		}
+	}
+	return nil
+}
+
+// Comments adds comments to a Group (if enabled), and returns the group.
+func Comments(group *Group, comments ...string) *Group {
+	if IncludeCommentsInGeneratedGo {
+		for _, comment := range comments {
+			group.Line().Comment(comment)
+		}
+	}
+	return group
+}
+
+func newStatement() *Statement {
+	return &Statement{}
+}
+
+// generateGoTestCodez generates the test code blocks for all the funcs and methods
+// selected for the provided pathVersion, using gen to generate the single test blocks.
+func generateGoTestCodez(
+	file *File,
+	pathVersion string,
+	what string,
+	b2fe x.BasicToFEFuncs,
+	b2tm x.BasicToTypeIDToMethods,
+	b2itm x.BasicToInterfaceIDToMethods,
+	gen func(file *File, fn x.FuncInterface, qual *x.FuncQualifier, receiverTypeID string) []Code,
+) []Code {
+	codez := make([]Code, 0)
+
+	{
+		cont, ok := b2fe[pathVersion]
+		if ok && x.HasValidPos(cont...) {
+			addedCount := 0
+			code := BlockFunc(
+				func(groupCase *Group) {
+
+					for _, qual := range cont {
+						fn := x.GetFuncByQualifier(qual)
+						thing := fn.(*feparser.FEFunc)
+
+						x.AddImportsFromFunc(file, thing)
+
+						{
+							if AllFalse(qual.Pos...) {
+								continue
+							}
+							groupCase.Comment(thing.Signature)
+
+							blocksOfCases := gen(file, thing, qual, "")
+							if len(blocksOfCases) == 1 {
+								groupCase.Add(blocksOfCases...)
+							} else {
+								groupCase.Block(blocksOfCases...)
+							}
+							addedCount++
+						}
+
+					}
+				})
+			if addedCount > 0 {
+				codez = append(codez,
+					Commentf("%s via function call.", what).
+						Line().
+						Add(code),
+				)
+			}
+		}
+	}
+	{
+		codezTypeMethods := make([]Code, 0)
+		b2tm.IterValid(pathVersion,
+			func(receiverTypeID string, methodQualifiers x.FuncQualifierSlice) {
+
+				firstQual := methodQualifiers[0]
+				// Find receiver type:
+				typ := x.FindType(firstQual.Path, firstQual.Version, receiverTypeID)
+				if typ == nil {
+					Fatalf("Type not found: %q", receiverTypeID)
+				}
+
+				gogentools.ImportPackage(file, typ.PkgPath, typ.PkgName)
+
+				code := BlockFunc(
+					func(groupCase *Group) {
+
+						for _, qual := range methodQualifiers {
+							fn := x.GetFuncByQualifier(qual)
+							thing := fn.(*feparser.FETypeMethod)
+							x.AddImportsFromFunc(file, fn)
+
+							{
+								if AllFalse(qual.Pos...) {
+									continue
+								}
+								groupCase.Comment(thing.Func.Signature)
+
+								blocksOfCases := gen(file, thing, qual, receiverTypeID)
+								if len(blocksOfCases) == 1 {
+									groupCase.Add(blocksOfCases...)
+								} else {
+									groupCase.Block(blocksOfCases...)
+								}
+							}
+
+						}
+					})
+				codezTypeMethods = append(codezTypeMethods,
+					Commentf("%s via method calls on %s.", what, typ.QualifiedName).
+						Line().
+						Add(code),
+				)
+			})
+		if len(codezTypeMethods) > 0 {
+			codez = append(codez,
+				Commentf("%s via method calls.", what).
+					Line().
+					Block(codezTypeMethods...),
+			)
+		}
+	}
+
+	{
+		codezIfaceMethods := make([]Code, 0)
+		b2itm.IterValid(pathVersion,
+			func(receiverTypeID string, methodQualifiers x.FuncQualifierSlice) {
+				firstQual := methodQualifiers[0]
+				// Find receiver type:
+				typ := x.FindType(firstQual.Path, firstQual.Version, receiverTypeID)
+				if typ == nil {
+					Fatalf("Type not found: %q", receiverTypeID)
+				}
+
+				gogentools.ImportPackage(file, typ.PkgPath, typ.PkgName)
+
+				code := BlockFunc(
+					func(groupCase *Group) {
+
+						for _, qual := range methodQualifiers {
+							fn := x.GetFuncByQualifier(qual)
+							thing := fn.(*feparser.FEInterfaceMethod)
+							x.AddImportsFromFunc(file, fn)
+
+							{
+								if AllFalse(qual.Pos...) {
+									continue
+								}
+								groupCase.Comment(thing.Func.Signature)
+
+								converted := feparser.FEIToFET(thing)
+
+								blocksOfCases := gen(file, converted, qual, receiverTypeID)
+								if len(blocksOfCases) == 1 {
+									groupCase.Add(blocksOfCases...)
+								} else {
+									groupCase.Block(blocksOfCases...)
+								}
+							}
+						}
+					})
+				codezIfaceMethods = append(codezIfaceMethods,
+					Commentf("%s via method calls on %s interface.", what, typ.QualifiedName).
+						Line().
+						Add(code),
+				)
+			})
+
+		if len(codezIfaceMethods) > 0 {
+			codez = append(codez,
+				Commentf("%s via interface method calls.", what).
+					Line().
+					Block(codezIfaceMethods...),
+			)
+		}
+	}
+
+	return codez
+}
+
+func wrapBlock(fn x.FuncInterface, childBlock *Statement) []Code {
+	childBlocks := make([]Code, 0)
+	if childBlock != nil {
+		childBlocks = append(childBlocks, childBlock)
+	} else {
+		Warnf(Sf("NOTHING GENERATED; %s", fn.GetFunc().Signature))
+	}
+	return childBlocks
+}
+
+// composeCall composes the call to the func, passing the variables of the considered parameters,
+// and the zero value for all the others.
+func composeCall(file *File, fn x.FuncInterface, isConsidered func(index int) bool) *Statement {
+	callCode := newStatement()
+	if fn.GetReceiver() != nil {
+		callCode.Id("rece").Dot(fn.GetFunc().Name)
+	} else {
+		callCode.Qual(fn.GetFunc().PkgPath, fn.GetFunc().Name)
+	}
+
+	callCode.CallFunc(
+		func(call *Group) {
+
+			tpFun := fn.GetFunc().GetOriginal().GetType().(*types.Signature)
+
+			zeroVals := gogentools.ScanTupleOfZeroValues(file, tpFun.Params(), fn.GetFunc().GetOriginal().IsVariadic())
+
+			for i, zero := range zeroVals {
+				if isConsidered(i) {
+					call.Id(fn.GetFunc().Parameters[i].VarName)
+				} else {
+					call.Add(zero)
+				}
+			}
+
+		},
+	)
+	return callCode
+}
+
+// returnsSingleBool tells whether the func returns exactly one bool.
+func returnsSingleBool(fn x.FuncInterface) bool {
+	results := fn.GetFunc().Results
+	if len(results) != 1 {
+		return false
+	}
+	basic, ok := results[0].GetOriginal().GetType().Underlying().(*types.Basic)
+	return ok && basic.Info()&types.IsBoolean != 0
+}
+
+// generateHasPrefix generates a prefix check on a tainted string;
+// if the check returns a bool, the string is passed to a sink both
+// in the guarded branch (no flow), and in the unguarded branch.
+func generateHasPrefix(file *File, fn x.FuncInterface, baseIndex int, prefixIndex int) *Statement {
+	if baseIndex == prefixIndex {
+		return nil
+	}
+
+	baseParam := fn.GetFunc().Parameters[baseIndex]
+	baseParam.VarName = gogentools.NewNameWithPrefix(feparser.NewLowerTitleName("base", baseParam.TypeName))
+
+	prefixParam := fn.GetFunc().Parameters[prefixIndex]
+	prefixParam.VarName = gogentools.NewNameWithPrefix(feparser.NewLowerTitleName("prefix", prefixParam.TypeName))
+
+	code := BlockFunc(
+		func(groupCase *Group) {
+
+			ComposeTypeAssertion(file, groupCase, baseParam.VarName, baseParam.GetOriginal().GetType(), baseParam.GetOriginal().IsVariadic())
+			ComposeConstantDeclaration(file, groupCase, prefixParam.VarName, prefixParam.GetOriginal().GetType(), "/")
+
+			if fn.GetReceiver() != nil {
+				Comments(groupCase, "Declare medium object/interface:")
+				groupCase.Var().Id("rece").Qual(fn.GetReceiver().PkgPath, fn.GetReceiver().TypeName)
+			}
+
+			gogentools.ImportPackage(file, fn.GetFunc().PkgPath, fn.GetFunc().PkgName)
+
+			callCode := composeCall(file, fn, func(i int) bool { return i == baseIndex || i == prefixIndex })
+
+			if returnsSingleBool(fn) {
+				groupCase.If(callCode).Block(
+					Tag(InlineExpectationsTestTagHasPrefix, baseParam.VarName),
+					Id("sink").Call(Id(baseParam.VarName)),
+				).Else().Block(
+					Id("sink").Call(Id(baseParam.VarName)).Add(Tag(InlineExpectationsTestTagTaintSink)),
+				)
+			} else {
+				groupCase.Add(callCode).Add(Tag(InlineExpectationsTestTagHasPrefix, baseParam.VarName))
+			}
+		})
+	return code
+}
+
+// generateConcatenation generates a concatenation of tainted operands.
+func generateConcatenation(file *File, fn x.FuncInterface, operandIndexes []int) *Statement {
+	operandVarNames := make([]string, 0)
+	for _, index := range operandIndexes {
+		in := fn.GetFunc().Parameters[index]
+		in.VarName = gogentools.NewNameWithPrefix(feparser.NewLowerTitleName("operand", in.TypeName))
+		operandVarNames = append(operandVarNames, in.VarName)
+	}
+
+	code := BlockFunc(
+		func(groupCase *Group) {
+
+			for _, index := range operandIndexes {
+				in := fn.GetFunc().Parameters[index]
+
+				ComposeTypeAssertion(file, groupCase, in.VarName, in.GetOriginal().GetType(), in.GetOriginal().IsVariadic())
+			}
+
+			if fn.GetReceiver() != nil {
+				Comments(groupCase, "Declare medium object/interface:")
+				groupCase.Var().Id("rece").Qual(fn.GetReceiver().PkgPath, fn.GetReceiver().TypeName)
+			}
+
+			gogentools.ImportPackage(file, fn.GetFunc().PkgPath, fn.GetFunc().PkgName)
+
+			callCode := composeCall(file, fn, func(i int) bool { return IntSliceContains(operandIndexes, i) })
+			groupCase.Add(callCode).Add(Tag(InlineExpectationsTestTagConcatOperand, operandVarNames...))
+		})
+	return code
+}
+
+// ComposeConstantDeclaration declares a constant:
+// `var name Type = "value"` if the type is a string, or `var name Type` otherwise.
+func ComposeConstantDeclaration(file *File, group *Group, varName string, typ types.Type, value string) {
+	if basic, ok := typ.Underlying().(*types.Basic); ok && basic.Info()&types.IsString != 0 {
+		typeContent := newStatement()
+		gogentools.ComposeTypeDeclaration(file, typeContent, typ)
+		group.Var().Id(varName).Add(typeContent).Op("=").Lit(value)
+		return
+	}
+	gogentools.ComposeVarDeclaration(file, group, varName, typ, false)
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// declare `name := source(1).(Type)`
+func ComposeTypeAssertion(file *File, group *Group, varName string, typ types.Type, isVariadic bool) {
+	assertContent := newStatement()
+	if isVariadic {
+		if slice, ok := typ.(*types.Slice); ok {
+			gogentools.ComposeTypeDeclaration(file, assertContent, slice.Elem())
+		} else {
+			gogentools.ComposeTypeDeclaration(file, assertContent, typ)
+		}
+	} else {
+		gogentools.ComposeTypeDeclaration(file, assertContent, typ)
+	}
+	group.Id(varName).Op(":=").Id("source").Call().Assert(assertContent)
+}
@@ -0,0 +1,85 @@
+package stringops
+
+import (
+	"fmt"
+
+	"github.com/gagliardetto/codemill/x"
+)
+
+// NOTE:
+// - Each func that you add to MethodHasPrefixBase must also be added to MethodHasPrefixPrefix (and vice versa).
+// - Both the base string and the prefix must be exactly one parameter.
+// - The operands of a concatenation must be contiguous parameters;
+//   if the last one is variadic, all the variadic arguments are operands.
+
+const (
+	Kind x.ModelKind = "StringOps"
+)
+
+type Handler struct{}
+
+const (
+	MethodHasPrefixBase         = "{base:Param, prefix:Param} <- $base"   // The string that is checked.
+	MethodHasPrefixPrefix       = "{base:Param, prefix:Param} <- $prefix" // The prefix that the string is checked against.
+	MethodConcatenationOperands = "{operands:[]Param} <- $operands"       // The operands that are concatenated (joined) in the result.
+)
+
+//
+func (han *Handler) ScavengeMethods() []*x.XMethod {
+	return x.ScavengeMethods(
+		// Each func that you add to MethodHasPrefixBase,
+		// you must also add it to MethodHasPrefixPrefix.
+		MethodHasPrefixBase,         // "Coupled 1/2: Select the parameter that specifies the string that is checked for a prefix.",
+		MethodHasPrefixPrefix,       // "Coupled 2/2: Select the parameter that specifies the prefix.",
+		MethodConcatenationOperands, // "Select the parameters that are concatenated (joined) in the result.",
+	)
+}
+func (han *Handler) Validate(mdl *x.XModel) error {
+	defaultMthNum := len(han.ScavengeMethods())
+	if len(mdl.Methods) != defaultMthNum {
+		return fmt.Errorf("wrong number of methods; expected %v, got %v", defaultMthNum, len(mdl.Methods))
+	}
+	{
+		for i, must := range han.ScavengeMethods() {
+			if mdl.Methods[i].Name != must.Name {
+				return fmt.Errorf("#%v method is not called %s", i, must.Name)
+			}
+		}
+	}
+	{
+		// Each base selector must have a corresponding prefix selector (and vice versa):
+		mtdBase := mdl.Methods.ByName(MethodHasPrefixBase)
+		mtdPrefix := mdl.Methods.ByName(MethodHasPrefixPrefix)
+		for _, mtd := range []*x.XMethod{mtdBase, mtdPrefix} {
+			if err := x.ValidateParams(mtd, true); err != nil {
+				return err
+			}
+		}
+		if err := x.ValidateCoupled(mtdBase, mtdPrefix); err != nil {
+			return err
+		}
+		if err := x.ValidateCoupled(mtdPrefix, mtdBase); err != nil {
+			return err
+		}
+	}
+	{
+		mtdOperands := mdl.Methods.ByName(MethodConcatenationOperands)
+		if err := x.ValidateParams(mtdOperands, false); err != nil {
+			return err
+		}
+		for _, sel := range mtdOperands.Selectors {
+			qual := sel.GetFuncQualifier()
+			fn := x.GetFuncByQualifier(qual)
+			indexes := x.MustPosToRelativeParamIndexes(fn, qual.Pos)
+			if len(indexes) == 0 {
+				return fmt.Errorf("%s: no operands selected in %s", qual.ID, mtdOperands.Name)
+			}
+			for i := 1; i < len(indexes); i++ {
+				if indexes[i] != indexes[i-1]+1 {
+					return fmt.Errorf("%s: the operands selected in %s are not contiguous: %v", qual.ID, mtdOperands.Name, indexes)
+				}
+			}
+		}
+	}
+	return nil
+}
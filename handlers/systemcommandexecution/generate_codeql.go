@@ -0,0 +1,75 @@
+package systemcommandexecution
+
+import (
+	"github.com/gagliardetto/codemill/x"
+	. "github.com/gagliardetto/cqlgen/jen"
+	"github.com/gagliardetto/feparser"
+	. "github.com/gagliardetto/utilz"
+)
+
+func (han *Handler) GenerateCodeQL(impAdder x.ImportAdder, mdl *x.XModel, rootModuleGroup *Group) error {
+	if err := mdl.Validate(); err != nil {
+		return err
+	}
+	if err := han.Validate(mdl); err != nil {
+		return err
+	}
+
+	// Assuming the validation has already been done:
+	mtdCommandName := mdl.Methods.ByName(MethodCommandName)
+	mtdCommandArgs := mdl.Methods.ByName(MethodCommandArgs)
+
+	if len(mtdCommandName.Selectors) == 0 {
+		Infof("No selectors found for %q method.", mtdCommandName.Name)
+		return nil
+	}
+
+	{
+		// Add imports:
+		//impAdder.Import("DataFlow::PathGraph")
+	}
+
+	className := mdl.Name
+	allPathVersions := mdl.ListAllPathVersions()
+
+	{
+		funcModelsClassName := feparser.NewCodeQlName(className)
+
+		commandNameCode, commandNameCount := x.CqlCallTargets(allPathVersions, mtdCommandName, "this", "System command execution models", x.CqlBindArgument("this", "commandNameNode"))
+		commandArgsCode, commandArgsCount := x.CqlCallTargets(allPathVersions, mtdCommandArgs, "this", "System command execution models", x.CqlBindArgument("this", "result"))
+
+		tmp := DoGroup(func(tempFuncsModel *Group) {
+			tempFuncsModel.Doc("Models system command executions.")
+			tempFuncsModel.Private().Class().Id(funcModelsClassName).Extends().List(
+				Id("SystemCommandExecution::Range"),
+				Id("DataFlow::CallNode"),
+			).BlockFunc(
+				func(funcModelsClassGroup *Group) {
+					funcModelsClassGroup.String().Id("package").Semicolon().Line()
+					funcModelsClassGroup.Id("DataFlow::Node").Id("commandNameNode").Semicolon().Line()
+
+					funcModelsClassGroup.Id(funcModelsClassName).Call().Block(
+						commandNameCode,
+					)
+
+					funcModelsClassGroup.Override().Id("DataFlow::Node").Id("getCommandName").Call().Block(
+						Id("result").Eq().Id("commandNameNode"),
+					)
+
+					if commandArgsCount > 0 {
+						// SystemCommandExecution::Range has no member for the arguments,
+						// so they are exposed separately (and not as command names):
+						funcModelsClassGroup.Comment("Gets an argument passed to the executed program.")
+						funcModelsClassGroup.Id("DataFlow::Node").Id("getAnArgumentNode").Call().Block(
+							commandArgsCode,
+						)
+					}
+				})
+		})
+		if commandNameCount > 0 {
+			rootModuleGroup.Add(tmp)
+		}
+	}
+
+	return nil
+}
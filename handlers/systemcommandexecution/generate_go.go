@@ -0,0 +1,477 @@
+package systemcommandexecution
+
+import (
+	"go/types"
+	"os"
+	"path/filepath"
+
+	. "github.com/dave/jennifer/jen"
+	"github.com/gagliardetto/codebox/gogentools"
+	"github.com/gagliardetto/codemill/x"
+	"github.com/gagliardetto/feparser"
+	. "github.com/gagliardetto/utilz"
+)
+
+var (
+	GenerateBoilerplate bool
+)
+
+const (
+	// NOTE: hardcoded inside TestQueryContent const.
+	InlineExpectationsTestTag = "$commandName" // Must start with a $ sign.
+)
+
+func Tag(vals ...string) Code {
+	tg := ""
+	for i, v := range vals {
+		if i > 0 {
+			tg += " "
+		}
+		tg += InlineExpectationsTestTag + "=" + v
+	}
+	return Comment(tg)
+}
+
+const (
+	TestQueryContent = `
+import go
+import TestUtilities.InlineExpectationsTest
+
+class SystemCommandExecutionTest extends InlineExpectationsTest {
+  SystemCommandExecutionTest() { this = "SystemCommandExecutionTest" }
+
+  override string getARelevantTag() { result = "commandName" }
+
+  override predicate hasActualResult(string file, int line, string element, string tag, string value) {
+    tag = "commandName" and
+    exists(SystemCommandExecution ce |
+      ce.hasLocationInfo(file, line, _, _, _) and
+      element = ce.getCommandName().toString() and
+      value = ce.getCommandName().toString()
+    )
+  }
+}
+`
+)
+
+func NewTestFile(includeBoilerplace bool) *File {
+	file := NewFile("main")
+	// Set a prefix to avoid collision between variable names and packages:
+	file.PackagePrefix = "cql"
+	// Add comment to file:
+	file.HeaderComment("Code generated by https://github.com/gagliardetto. DO NOT EDIT.")
+
+	if includeBoilerplace {
+		file.PackageComment("//go:generate depstubber --vendor --auto")
+		{
+			// main function:
+			file.Func().Id("main").Params().Block()
+		}
+		{
+			// The `source` function returns a new command name or argument:
+			code := Func().
+				Id("source").
+				Params().
+				Interface().
+				Block(Return(Nil()))
+			file.Add(code.Line())
+		}
+	}
+	return file
+}
+
+var (
+	IncludeCommentsInGeneratedGo bool
+)
+
+func (han *Handler) GenerateGo(parentDir string, mdl *x.XModel) error {
+	if err := mdl.Validate(); err != nil {
+		return err
+	}
+	if err := han.Validate(mdl); err != nil {
+		return err
+	}
+
+	// Check if there are multiple versions of a same package:
+	mods := mdl.ListModules()
+	if x.HasMultiversion(mods) {
+		Ln(RedBG("Has multiversion"))
+	}
+	// If there are no multiple versions of the same module,
+	// that means we can save all the code to one file.
+	allInOneFile := !x.HasMultiversion(mods)
+
+	// Create the directory for the tests for this model:
+	outDir := filepath.Join(parentDir, feparser.NewCodeQlName(mdl.Name))
+	MustCreateFolderIfNotExists(outDir, os.ModePerm)
+
+	// Assuming the validation has already been done:
+	mtdCommandName := mdl.Methods.ByName(MethodCommandName)
+	mtdCommandArgs := mdl.Methods.ByName(MethodCommandArgs)
+
+	if len(mtdCommandName.Selectors) == 0 {
+		Infof("No selectors found for %q method.", mtdCommandName.Name)
+		return nil
+	}
+
+	allPathVersions := mdl.ListAllPathVersions()
+
+	file := NewTestFile(GenerateBoilerplate)
+
+	b2feCmd, b2tmCmd, b2itmCmd, err := x.GroupFuncSelectors(mtdCommandName)
+	if err != nil {
+		Fatalf("Error while GroupFuncSelectors: %s", err)
+	}
+	b2feArgs, b2tmArgs, b2itmArgs, err := x.GroupFuncSelectors(mtdCommandArgs)
+	if err != nil {
+		Fatalf("Error while GroupFuncSelectors: %s", err)
+	}
+
+	for _, pathVersion := range allPathVersions {
+		if !allInOneFile {
+			// Reset file:
+			file = NewTestFile(GenerateBoilerplate)
+		}
+		codez := make([]Code, 0)
+
+		{
+			cont, ok := b2feCmd[pathVersion]
+			if ok && x.HasValidPos(cont...) {
+				addedCount := 0
+				code := BlockFunc(
+					func(groupCase *Group) {
+
+						for _, cmdQual := range cont {
+							fn := x.GetFuncByQualifier(cmdQual)
+							thing := fn.(*feparser.FEFunc)
+
+							x.AddImportsFromFunc(file, thing)
+
+							{
+								if AllFalse(cmdQual.Pos...) {
+									continue
+								}
+								groupCase.Comment(thing.Signature)
+
+								argsQual := b2feArgs[pathVersion].ByBasicQualifier(cmdQual.BasicQualifier)
+
+								blocksOfCases := generateGoTestBlock(
+									file,
+									thing,
+									cmdQual,
+									argsQual,
+								)
+								if len(blocksOfCases) == 1 {
+									groupCase.Add(blocksOfCases...)
+								} else {
+									groupCase.Block(blocksOfCases...)
+								}
+								addedCount++
+							}
+
+						}
+					})
+				if addedCount > 0 {
+					codez = append(codez,
+						Comment("Command execution via function call.").
+							Line().
+							Add(code),
+					)
+				}
+			}
+		}
+		{
+			codezTypeMethods := make([]Code, 0)
+			b2tmCmd.IterValid(pathVersion,
+				func(receiverTypeID string, methodQualifiers x.FuncQualifierSlice) {
+
+					qual := methodQualifiers[0]
+					// Find receiver type:
+					typ := x.FindType(qual.Path, qual.Version, receiverTypeID)
+					if typ == nil {
+						Fatalf("Type not found: %q", receiverTypeID)
+					}
+
+					gogentools.ImportPackage(file, typ.PkgPath, typ.PkgName)
+
+					code := BlockFunc(
+						func(groupCase *Group) {
+
+							for _, cmdQual := range methodQualifiers {
+								fn := x.GetFuncByQualifier(cmdQual)
+								thing := fn.(*feparser.FETypeMethod)
+								x.AddImportsFromFunc(file, fn)
+
+								{
+									if AllFalse(cmdQual.Pos...) {
+										continue
+									}
+									groupCase.Comment(thing.Func.Signature)
+
+									argsQual := b2tmArgs[pathVersion][receiverTypeID].ByBasicQualifier(cmdQual.BasicQualifier)
+
+									blocksOfCases := generateGoTestBlock(
+										file,
+										thing,
+										cmdQual,
+										argsQual,
+									)
+									if len(blocksOfCases) == 1 {
+										groupCase.Add(blocksOfCases...)
+									} else {
+										groupCase.Block(blocksOfCases...)
+									}
+								}
+
+							}
+						})
+					codezTypeMethods = append(codezTypeMethods,
+						Commentf("Command execution via method calls on %s.", typ.QualifiedName).
+							Line().
+							Add(code),
+					)
+				})
+			if len(codezTypeMethods) > 0 {
+				codez = append(codez,
+					Comment("Command execution via method calls.").
+						Line().
+						Block(codezTypeMethods...),
+				)
+			}
+		}
+
+		{
+			codezIfaceMethods := make([]Code, 0)
+			b2itmCmd.IterValid(pathVersion,
+				func(receiverTypeID string, methodQualifiers x.FuncQualifierSlice) {
+					qual := methodQualifiers[0]
+					// Find receiver type:
+					typ := x.FindType(qual.Path, qual.Version, receiverTypeID)
+					if typ == nil {
+						Fatalf("Type not found: %q", receiverTypeID)
+					}
+
+					gogentools.ImportPackage(file, typ.PkgPath, typ.PkgName)
+
+					code := BlockFunc(
+						func(groupCase *Group) {
+
+							for _, cmdQual := range methodQualifiers {
+								fn := x.GetFuncByQualifier(cmdQual)
+								thing := fn.(*feparser.FEInterfaceMethod)
+								x.AddImportsFromFunc(file, fn)
+
+								{
+									if AllFalse(cmdQual.Pos...) {
+										continue
+									}
+									groupCase.Comment(thing.Func.Signature)
+
+									converted := feparser.FEIToFET(thing)
+
+									argsQual := b2itmArgs[pathVersion][receiverTypeID].ByBasicQualifier(cmdQual.BasicQualifier)
+
+									blocksOfCases := generateGoTestBlock(
+										file,
+										converted,
+										cmdQual,
+										argsQual,
+									)
+									if len(blocksOfCases) == 1 {
+										groupCase.Add(blocksOfCases...)
+									} else {
+										groupCase.Block(blocksOfCases...)
+									}
+								}
+							}
+						})
+					codezIfaceMethods = append(codezIfaceMethods,
+						Commentf("Command execution via method calls on %s interface.", typ.QualifiedName).
+							Line().
+							Add(code),
+					)
+				})
+
+			if len(codezIfaceMethods) > 0 {
+				codez = append(codez,
+					Comment("Command execution via interface method calls.").
+						Line().
+						Block(codezIfaceMethods...),
+				)
+			}
+		}
+
+		{
+			file.Commentf("Package %s", pathVersion)
+			file.Func().Id(mdl.Name + "_" + feparser.FormatCodeQlName(pathVersion)).Params().Block(codez...)
+		}
+
+		if !allInOneFile {
+			pkgDstDirpath := filepath.Join(outDir, feparser.FormatID(mdl.Name, "For", feparser.FormatCodeQlName(pathVersion)))
+			MustCreateFolderIfNotExists(pkgDstDirpath, os.ModePerm)
+
+			assetFileName := feparser.FormatID(mdl.Name, "For", feparser.FormatCodeQlName(pathVersion)) + ".go"
+			if err := x.SaveGoFile(pkgDstDirpath, assetFileName, file); err != nil {
+				Fatalf("Error while saving go file: %s", err)
+			}
+
+			if err := x.WriteGoModFile(pkgDstDirpath, pathVersion); err != nil {
+				Fatalf("Error while saving go.mod file: %s", err)
+			}
+			if err := x.WriteCodeQLTestQuery(pkgDstDirpath, mdl.Name, TestQueryContent); err != nil {
+				Fatalf("Error while saving <name>.ql file: %s", err)
+			}
+			if err := x.WriteEmptyCodeQLDotExpectedFile(pkgDstDirpath, mdl.Name); err != nil {
+				Fatalf("Error while saving <name>.expected file: %s", err)
+			}
+		}
+	}
+
+	if allInOneFile {
+		pkgDstDirpath := outDir
+		MustCreateFolderIfNotExists(pkgDstDirpath, os.ModePerm)
+
+		assetFileName := feparser.FormatID(mdl.Name) + ".go"
+		if err := x.SaveGoFile(pkgDstDirpath, assetFileName, file); err != nil {
+			Fatalf("Error while saving go file: %s", err)
+		}
+
+		if err := x.WriteGoModFile(pkgDstDirpath, allPathVersions...); err != nil {
+			Fatalf("Error while saving go.mod file: %s", err)
+		}
+		if err := x.WriteCodeQLTestQuery(pkgDstDirpath, mdl.Name, TestQueryContent); err != nil {
+			Fatalf("Error while saving <name>.ql file: %s", err)
+		}
+		if err := x.WriteEmptyCodeQLDotExpectedFile(pkgDstDirpath, mdl.Name); err != nil {
+			Fatalf("Error while saving <name>.expected file: %s", err)
+		}
+	}
+	return nil
+}
+
+// Comments adds comments to a Group (if enabled), and returns the group.
+func Comments(group *Group, comments ...string) *Group {
+	if IncludeCommentsInGeneratedGo {
+		for _, comment := range comments {
+			group.Line().Comment(comment)
+		}
+	}
+	return group
+}
+
+func newStatement() *Statement {
+	return &Statement{}
+}
+
+func generateGoTestBlock(
+	file *File,
+	fn x.FuncInterface,
+	cmdQual *x.FuncQualifier,
+	argsQual *x.FuncQualifier,
+) []Code {
+	childBlocks := make([]Code, 0)
+
+	cmdIndexes := x.MustPosToRelativeParamIndexes(fn, cmdQual.Pos)
+	if len(cmdIndexes) != 1 {
+		Fatalf("cmdIndexes len is not 1: %v", cmdQual)
+	}
+	argsIndexes := make([]int, 0)
+	if argsQual != nil {
+		argsIndexes = x.MustPosToRelativeParamIndexes(fn, argsQual.Pos)
+	}
+
+	childBlock := generate(
+		file,
+		fn,
+		cmdIndexes[0],
+		argsIndexes,
+	)
+	{
+		if childBlock != nil {
+			childBlocks = append(childBlocks, childBlock)
+		} else {
+			Warnf(Sf("NOTHING GENERATED; cmdQual %v, argsQual %v", cmdQual, argsQual))
+		}
+	}
+
+	return childBlocks
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+func generate(file *File, fn x.FuncInterface, cmdIndex int, argsIndexes []int) *Statement {
+
+	cmdParam := fn.GetFunc().Parameters[cmdIndex]
+	cmdParam.VarName = gogentools.NewNameWithPrefix(feparser.NewLowerTitleName("cmd", cmdParam.TypeName))
+
+	for _, index := range argsIndexes {
+		in := fn.GetFunc().Parameters[index]
+
+		in.VarName = gogentools.NewNameWithPrefix(feparser.NewLowerTitleName("arg", in.TypeName))
+	}
+
+	hasReceiver := fn.GetReceiver() != nil
+
+	code := BlockFunc(
+		func(groupCase *Group) {
+
+			ComposeTypeAssertion(file, groupCase, cmdParam.VarName, cmdParam.GetOriginal().GetType(), cmdParam.GetOriginal().IsVariadic())
+
+			for _, index := range argsIndexes {
+				in := fn.GetFunc().Parameters[index]
+
+				ComposeTypeAssertion(file, groupCase, in.VarName, in.GetOriginal().GetType(), in.GetOriginal().IsVariadic())
+			}
+
+			if hasReceiver {
+				Comments(groupCase, "Declare medium object/interface:")
+				groupCase.Var().Id("rece").Qual(fn.GetReceiver().PkgPath, fn.GetReceiver().TypeName)
+			}
+
+			gogentools.ImportPackage(file, fn.GetFunc().PkgPath, fn.GetFunc().PkgName)
+
+			var after *Statement
+			if hasReceiver {
+				after = groupCase.Id("rece").Dot(fn.GetFunc().Name)
+			} else {
+				after = groupCase.Qual(fn.GetFunc().PkgPath, fn.GetFunc().Name)
+			}
+
+			after.CallFunc(
+				func(call *Group) {
+
+					tpFun := fn.GetFunc().GetOriginal().GetType().(*types.Signature)
+
+					zeroVals := gogentools.ScanTupleOfZeroValues(file, tpFun.Params(), fn.GetFunc().GetOriginal().IsVariadic())
+
+					for i, zero := range zeroVals {
+						isConsidered := i == cmdIndex || IntSliceContains(argsIndexes, i)
+						if isConsidered {
+							call.Id(fn.GetFunc().Parameters[i].VarName)
+						} else {
+							call.Add(zero)
+						}
+					}
+
+				},
+			).Add(Tag(cmdParam.VarName))
+
+		})
+	return code
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// declare `name := source(1).(Type)`
+func ComposeTypeAssertion(file *File, group *Group, varName string, typ types.Type, isVariadic bool) {
+	assertContent := newStatement()
+	if isVariadic {
+		if slice, ok := typ.(*types.Slice); ok {
+			gogentools.ComposeTypeDeclaration(file, assertContent, slice.Elem())
+		} else {
+			gogentools.ComposeTypeDeclaration(file, assertContent, typ)
+		}
+	} else {
+		gogentools.ComposeTypeDeclaration(file, assertContent, typ)
+	}
+	group.Id(varName).Op(":=").Id("source").Call().Assert(assertContent)
+}
@@ -0,0 +1,67 @@
+package systemcommandexecution
+
+import (
+	"fmt"
+
+	"github.com/gagliardetto/codemill/x"
+)
+
+// NOTE:
+// - Each func that you add to MethodCommandArgs must also be added to MethodCommandName.
+// - A func added to MethodCommandName without a MethodCommandArgs counterpart
+//   is modeled as a command execution without arguments.
+// - Only the program name is modeled as SystemCommandExecution::getCommandName;
+//   SystemCommandExecution::Range has no member for the arguments, so they are exposed
+//   by the getAnArgumentNode predicate of the generated class, and are not seen by the library queries.
+
+const (
+	Kind x.ModelKind = "SystemCommandExecution"
+)
+
+type Handler struct{}
+
+const (
+	MethodCommandName = "{cmd:Param, args:Param} <- $cmd"  // The program name (or the whole command line).
+	MethodCommandArgs = "{cmd:Param, args:Param} <- $args" // The arguments passed to the program.
+)
+
+//
+func (han *Handler) ScavengeMethods() []*x.XMethod {
+	return x.ScavengeMethods(
+		MethodCommandName, // "Select the parameter that specifies the program name (or the whole command line).",
+
+		// Each func that you add to MethodCommandArgs,
+		// you must also add it to MethodCommandName.
+		MethodCommandArgs, // "Select the parameters that specify the arguments of the program.",
+	)
+}
+func (han *Handler) Validate(mdl *x.XModel) error {
+	defaultMthNum := len(han.ScavengeMethods())
+	if len(mdl.Methods) != defaultMthNum {
+		return fmt.Errorf("wrong number of methods; expected %v, got %v", defaultMthNum, len(mdl.Methods))
+	}
+	{
+		for i, must := range han.ScavengeMethods() {
+			if mdl.Methods[i].Name != must.Name {
+				return fmt.Errorf("#%v method is not called %s", i, must.Name)
+			}
+		}
+	}
+	{
+		// The command name must be exactly one parameter:
+		if err := x.ValidateParams(mdl.Methods.ByName(MethodCommandName), true); err != nil {
+			return err
+		}
+	}
+	{
+		// Each args selector must have a corresponding command name selector:
+		mtdArgs := mdl.Methods.ByName(MethodCommandArgs)
+		if err := x.ValidateParams(mtdArgs, false); err != nil {
+			return err
+		}
+		if err := x.ValidateCoupled(mtdArgs, mdl.Methods.ByName(MethodCommandName)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
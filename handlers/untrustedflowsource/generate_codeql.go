@@ -18,6 +18,12 @@ func (han *Handler) GenerateCodeQL(impAdder x.ImportAdder, mdl *x.XModel, module
 		return err
 	}
 
+	return untrustedFlowSource.GenerateCodeQL(impAdder, mdl, moduleGroup)
+}
+
+// GenerateCodeQL adds to the module group the class that models
+// the sources selected in the first method of the model.
+func (src *Source) GenerateCodeQL(impAdder x.ImportAdder, mdl *x.XModel, moduleGroup *Group) error {
 	// Assuming the validation has already been done:
 	self := mdl.Methods[0]
 
@@ -26,15 +32,15 @@ func (han *Handler) GenerateCodeQL(impAdder x.ImportAdder, mdl *x.XModel, module
 		return nil
 	}
 
-	{
+	if src.Import != "" {
 		// Add imports:
-		//impAdder.Import("DataFlow::PathGraph")
+		impAdder.Import(src.Import)
 	}
 
 	className := mdl.Name
 
-	moduleGroup.Doc("Provides models of untrusted flow sources.")
-	moduleGroup.Private().Class().Id(className).Extends().List(Qual("UntrustedFlowSource", "Range")).
+	moduleGroup.Doc(src.Doc)
+	moduleGroup.Private().Class().Id(className).Extends().List(Id(src.Extends)).
 		BlockFunc(func(classGr *Group) {
 			classGr.Id(className).Call().BlockFunc(func(metGr *Group) {
 				b2fe, b2tm, b2itm, err := x.GroupFuncSelectors(self)
@@ -22,8 +22,9 @@ const (
 	InlineExpectationsTestTag = "$untrustedFlowSource" // Must start with a $ sign.
 )
 
-func Tag() Code {
-	return Comment(InlineExpectationsTestTag)
+// tag returns the inline expectations comment.
+func (src *Source) tag() Code {
+	return Comment(src.TestTag)
 }
 
 const (
@@ -85,6 +86,14 @@ func (han *Handler) GenerateGo(parentDir string, mdl *x.XModel) error {
 		return err
 	}
 
+	src := *untrustedFlowSource
+	src.GenerateBoilerplate = GenerateBoilerplate
+	src.IncludeComments = IncludeCommentsInGeneratedGo
+	return src.GenerateGo(parentDir, mdl)
+}
+
+// GenerateGo generates the Go tests for the sources selected in the first method of the model.
+func (src *Source) GenerateGo(parentDir string, mdl *x.XModel) error {
 	// Check if there are multiple versions of a same package:
 	mods := mdl.ListModules()
 	if x.HasMultiversion(mods) {
@@ -108,12 +117,12 @@ func (han *Handler) GenerateGo(parentDir string, mdl *x.XModel) error {
 
 	allPathVersions := mdl.ListAllPathVersions()
 
-	file := NewTestFile(GenerateBoilerplate)
+	file := NewTestFile(src.GenerateBoilerplate)
 
 	for _, pathVersion := range allPathVersions {
 		if !allInOneFile {
 			// Reset file:
-			file = NewTestFile(GenerateBoilerplate)
+			file = NewTestFile(src.GenerateBoilerplate)
 		}
 		codez := make([]Code, 0)
 
@@ -148,14 +157,14 @@ func (han *Handler) GenerateGo(parentDir string, mdl *x.XModel) error {
 							x.AddImportsFromFunc(file, thing)
 
 							groupCase.Comment(thing.Signature)
-							_, codeElements := GoGetFuncQualifierCodeElements(file, funcQual)
+							_, codeElements := src.GoGetFuncQualifierCodeElements(file, funcQual)
 							groupCase.Add(codeElements...)
 							addedCount++
 						}
 					})
 				if addedCount > 0 {
 					codez = append(codez,
-						Comment("Untrusted flow sources from functions.").
+						Commentf("%s from functions.", src.Comment).
 							Line().
 							Add(code),
 					)
@@ -186,20 +195,20 @@ func (han *Handler) GenerateGo(parentDir string, mdl *x.XModel) error {
 								x.AddImportsFromFunc(file, thing)
 
 								groupCase.Comment(thing.Func.Signature)
-								_, codeElements := GoGetFuncQualifierCodeElements(file, methodQual)
+								_, codeElements := src.GoGetFuncQualifierCodeElements(file, methodQual)
 								groupCase.Add(codeElements...)
 
 							}
 						})
 					codezTypeMethods = append(codezTypeMethods,
-						Commentf("Untrusted flow sources from method calls on %s.", typ.QualifiedName).
+						Commentf("%s from method calls on %s.", src.Comment, typ.QualifiedName).
 							Line().
 							Add(code),
 					)
 				})
 			if len(codezTypeMethods) > 0 {
 				codez = append(codez,
-					Comment("Untrusted flow sources from method calls.").
+					Commentf("%s from method calls.", src.Comment).
 						Line().
 						Block(codezTypeMethods...),
 				)
@@ -229,13 +238,13 @@ func (han *Handler) GenerateGo(parentDir string, mdl *x.XModel) error {
 								x.AddImportsFromFunc(file, thing)
 
 								groupCase.Comment(thing.Func.Signature)
-								_, codeElements := GoGetFuncQualifierCodeElements(file, methodQual)
+								_, codeElements := src.GoGetFuncQualifierCodeElements(file, methodQual)
 								groupCase.Add(codeElements...)
 
 							}
 						})
 					codezIfaceMethods = append(codezIfaceMethods,
-						Commentf("Untrusted flow sources from method calls on %s interface.", typ.QualifiedName).
+						Commentf("%s from method calls on %s interface.", src.Comment, typ.QualifiedName).
 							Line().
 							Add(code),
 					)
@@ -243,7 +252,7 @@ func (han *Handler) GenerateGo(parentDir string, mdl *x.XModel) error {
 
 			if len(codezIfaceMethods) > 0 {
 				codez = append(codez,
-					Comment("Untrusted flow sources from interface method calls.").
+					Commentf("%s from interface method calls.", src.Comment).
 						Line().
 						Block(codezIfaceMethods...),
 				)
@@ -274,7 +283,7 @@ func (han *Handler) GenerateGo(parentDir string, mdl *x.XModel) error {
 								fieldNames = append(fieldNames, fieldName)
 							}
 
-							groupCase.Commentf("Untrusted flow sources from %s struct fields.", str.QualifiedName)
+							groupCase.Commentf("%s from %s struct fields.", src.Comment, str.QualifiedName)
 							groupCase.BlockFunc(
 								func(subGroup *Group) {
 									structVarName := gogentools.NewNameWithPrefix(feparser.NewLowerTitleName("struct", str.TypeName))
@@ -283,11 +292,11 @@ func (han *Handler) GenerateGo(parentDir string, mdl *x.XModel) error {
 									if len(fieldNames) > 0 {
 										if len(fieldNames) == 1 {
 											fieldName := fieldNames[0]
-											subGroup.Id("sink").Call(Id(structVarName).Dot(fieldName)).Add(Tag())
+											subGroup.Id("sink").Call(Id(structVarName).Dot(fieldName)).Add(src.tag())
 										} else {
 											codeParamIDs := make([]Code, 0)
 											for _, fieldName := range fieldNames {
-												codeParamIDs = append(codeParamIDs, Id(structVarName).Dot(fieldName).Op(",").Add(Tag()).Line())
+												codeParamIDs = append(codeParamIDs, Id(structVarName).Dot(fieldName).Op(",").Add(src.tag()).Line())
 											}
 											subGroup.Id("sink").Call(Line().Add(codeParamIDs...))
 										}
@@ -298,7 +307,7 @@ func (han *Handler) GenerateGo(parentDir string, mdl *x.XModel) error {
 					})
 
 				codez = append(codez,
-					Comment("Untrusted flow sources from struct fields.").
+					Commentf("%s from struct fields.", src.Comment).
 						Line().
 						Add(code),
 				)
@@ -323,12 +332,12 @@ func (han *Handler) GenerateGo(parentDir string, mdl *x.XModel) error {
 							groupCase.BlockFunc(
 								func(subGroup *Group) {
 									subGroup.Var().Id(typeVarName).Qual(typ.PkgPath, typ.TypeName)
-									subGroup.Id("sink").Call(Id(typeVarName)).Add(Tag())
+									subGroup.Id("sink").Call(Id(typeVarName)).Add(src.tag())
 								})
 						}
 					})
 				codez = append(codez,
-					Comment("Untrusted flow sources from types.").
+					Commentf("%s from types.", src.Comment).
 						Line().
 						Add(code),
 				)
@@ -352,7 +361,7 @@ func (han *Handler) GenerateGo(parentDir string, mdl *x.XModel) error {
 			if err := x.WriteGoModFile(pkgDstDirpath, pathVersion); err != nil {
 				Fatalf("Error while saving go.mod file: %s", err)
 			}
-			if err := x.WriteCodeQLTestQuery(pkgDstDirpath, mdl.Name, TestQueryContent); err != nil {
+			if err := x.WriteCodeQLTestQuery(pkgDstDirpath, mdl.Name, src.TestQueryContent); err != nil {
 				Fatalf("Error while saving <name>.ql file: %s", err)
 			}
 			if err := x.WriteEmptyCodeQLDotExpectedFile(pkgDstDirpath, mdl.Name); err != nil {
@@ -373,7 +382,7 @@ func (han *Handler) GenerateGo(parentDir string, mdl *x.XModel) error {
 		if err := x.WriteGoModFile(pkgDstDirpath, allPathVersions...); err != nil {
 			Fatalf("Error while saving go.mod file: %s", err)
 		}
-		if err := x.WriteCodeQLTestQuery(pkgDstDirpath, mdl.Name, TestQueryContent); err != nil {
+		if err := x.WriteCodeQLTestQuery(pkgDstDirpath, mdl.Name, src.TestQueryContent); err != nil {
 			Fatalf("Error while saving <name>.ql file: %s", err)
 		}
 		if err := x.WriteEmptyCodeQLDotExpectedFile(pkgDstDirpath, mdl.Name); err != nil {
@@ -384,15 +393,15 @@ func (han *Handler) GenerateGo(parentDir string, mdl *x.XModel) error {
 }
 
 // Comments adds comments to a Group (if enabled), and returns the group.
-func Comments(group *Group, comments ...string) *Group {
-	if IncludeCommentsInGeneratedGo {
+func (src *Source) Comments(group *Group, comments ...string) *Group {
+	if src.IncludeComments {
 		for _, comment := range comments {
 			group.Line().Comment(comment)
 		}
 	}
 	return group
 }
-func GoGetFuncQualifierCodeElements(file *File, qual *x.FuncQualifier) (x.FuncInterface, []Code) {
+func (src *Source) GoGetFuncQualifierCodeElements(file *File, qual *x.FuncQualifier) (x.FuncInterface, []Code) {
 
 	source := x.GetCachedSource(qual.Path, qual.Version)
 	if source == nil {
@@ -543,13 +552,13 @@ PosLoop:
 				//groupCase.Comment("Sink parameters:")
 				if len(parameterIndexes) == 1 {
 					i := parameterIndexes[0]
-					groupCase.Id("sink").Call(Id(fe.Parameters[i].VarName)).Add(Tag())
+					groupCase.Id("sink").Call(Id(fe.Parameters[i].VarName)).Add(src.tag())
 				} else {
 					codeParamIDs := make([]Code, 0)
 					for i := range paramZeroVals {
 						isConsidered := IntSliceContains(parameterIndexes, i)
 						if isConsidered {
-							codeParamIDs = append(codeParamIDs, Id(fe.Parameters[i].VarName).Op(",").Add(Tag()).Line())
+							codeParamIDs = append(codeParamIDs, Id(fe.Parameters[i].VarName).Op(",").Add(src.tag()).Line())
 						}
 					}
 					groupCase.Id("sink").Call(Line().Add(codeParamIDs...))
@@ -560,13 +569,13 @@ PosLoop:
 				//groupCase.Comment("Sink results:")
 				if len(resultIndexes) == 1 {
 					i := resultIndexes[0]
-					groupCase.Id("sink").Call(Id(fe.Results[i].VarName)).Add(Tag())
+					groupCase.Id("sink").Call(Id(fe.Results[i].VarName)).Add(src.tag())
 				} else {
 					codeResultIDs := make([]Code, 0)
 					for i := range resultZeroVals {
 						isConsidered := IntSliceContains(resultIndexes, i)
 						if isConsidered {
-							codeResultIDs = append(codeResultIDs, Id(fe.Results[i].VarName).Op(",").Add(Tag()).Line())
+							codeResultIDs = append(codeResultIDs, Id(fe.Results[i].VarName).Op(",").Add(src.tag()).Line())
 						}
 					}
 					groupCase.Id("sink").Call(Line().Add(codeResultIDs...))
@@ -575,7 +584,7 @@ PosLoop:
 			// Sink the receiver:
 			if considerReceiver {
 				//groupCase.Comment("Sink the receiver:")
-				groupCase.Id("sink").Call(Id(receiver.VarName)).Add(Tag())
+				groupCase.Id("sink").Call(Id(receiver.VarName)).Add(src.tag())
 			}
 		})
 
@@ -594,9 +603,10 @@ type VarNameAndType struct {
 
 // declare:
 // `var (
+//
 //		name1 Type1
 //		name2 Type2
-// 	)`
+//	)`
 func ComposeGroupVarDeclaration(file *File, group *Group, decs []*VarNameAndType) {
 	stat := newStatement()
 
@@ -39,3 +39,12 @@ func (han *Handler) Validate(mdl *x.XModel) error {
 	}
 	return nil
 }
+
+// untrustedFlowSource generates the models and the tests of the model.
+var untrustedFlowSource = &Source{
+	Doc:              "Provides models of untrusted flow sources.",
+	Extends:          "UntrustedFlowSource::Range",
+	Comment:          "Untrusted flow sources",
+	TestTag:          InlineExpectationsTestTag,
+	TestQueryContent: TestQueryContent,
+}
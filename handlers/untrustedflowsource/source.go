@@ -0,0 +1,19 @@
+package untrustedflowsource
+
+// Source generates the models and the tests of the model kinds whose models
+// are sources selected like the ones of this handler (funcs, methods, struct fields, types);
+// other handlers (e.g. local user input, zip slip) reuse it with their own class and tests.
+type Source struct {
+	// CodeQL:
+	Doc     string // Doc of the generated class.
+	Extends string // Class extended by the generated class, e.g. "UntrustedFlowSource::Range".
+	Import  string // Library that defines the Extends class; optional.
+	Comment string // Name of the models (e.g. "Untrusted flow sources"), used in the generated comments.
+
+	// Go tests:
+	TestTag          string // Tag of the inline expectations; must start with a $ sign.
+	TestQueryContent string // Content of the <name>.ql test query.
+
+	GenerateBoilerplate bool
+	IncludeComments     bool
+}
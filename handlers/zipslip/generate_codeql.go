@@ -0,0 +1,17 @@
+package zipslip
+
+import (
+	"github.com/gagliardetto/codemill/x"
+	. "github.com/gagliardetto/cqlgen/jen"
+)
+
+func (han *Handler) GenerateCodeQL(impAdder x.ImportAdder, mdl *x.XModel, moduleGroup *Group) error {
+	if err := mdl.Validate(); err != nil {
+		return err
+	}
+	if err := han.Validate(mdl); err != nil {
+		return err
+	}
+
+	return zipSlipSource.GenerateCodeQL(impAdder, mdl, moduleGroup)
+}
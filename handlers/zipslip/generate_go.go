@@ -0,0 +1,56 @@
+package zipslip
+
+import (
+	"github.com/gagliardetto/codemill/x"
+)
+
+var (
+	IncludeCommentsInGeneratedGo bool
+	GenerateBoilerplate          bool
+)
+
+const (
+	// NOTE: hardcoded inside TestQueryContent const.
+	InlineExpectationsTestTag = "$zipSlipSource" // Must start with a $ sign.
+)
+
+const (
+	TestQueryContent = `
+import go
+import TestUtilities.InlineExpectationsTest
+import semmle.go.security.ZipSlipCustomizations
+
+class ZipSlipSourceTest extends InlineExpectationsTest {
+  ZipSlipSourceTest() { this = "ZipSlipSourceTest" }
+
+  override string getARelevantTag() { result = "zipSlipSource" }
+
+  override predicate hasActualResult(string file, int line, string element, string tag, string value) {
+    tag = "zipSlipSource" and
+    exists(DataFlow::CallNode sinkCall, DataFlow::ArgumentNode arg |
+      sinkCall.getCalleeName() = "sink" and
+      arg = sinkCall.getAnArgument() and
+      (arg.getAPredecessor*() instanceof ZipSlip::Source)
+    |
+      element = arg.toString() and
+      value = "" and
+      arg.hasLocationInfo(file, line, _, _, _)
+    )
+  }
+}
+`
+)
+
+func (han *Handler) GenerateGo(parentDir string, mdl *x.XModel) error {
+	if err := mdl.Validate(); err != nil {
+		return err
+	}
+	if err := han.Validate(mdl); err != nil {
+		return err
+	}
+
+	src := *zipSlipSource
+	src.GenerateBoilerplate = GenerateBoilerplate
+	src.IncludeComments = IncludeCommentsInGeneratedGo
+	return src.GenerateGo(parentDir, mdl)
+}
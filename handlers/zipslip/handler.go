@@ -0,0 +1,66 @@
+package zipslip
+
+import (
+	"fmt"
+
+	"github.com/gagliardetto/codemill/handlers/untrustedflowsource"
+	"github.com/gagliardetto/codemill/x"
+)
+
+const (
+	Kind x.ModelKind = "ZipSlip::Source"
+)
+
+type Handler struct{}
+
+// NOTE:
+// - Only archive-entry names are sources, so only func results
+//   and struct fields can be selected (no parameters, receivers or types).
+// - The models and the tests are generated with the untrustedflowsource generator.
+
+const (
+	MethodSelf = "{source:[](Result|Fields)} <- $source"
+)
+
+//
+func (han *Handler) ScavengeMethods() []*x.XMethod {
+	return []*x.XMethod{
+		{
+			Name:      MethodSelf,
+			Selectors: []*x.XSelector{},
+		},
+	}
+}
+func (han *Handler) Validate(mdl *x.XModel) error {
+	if len(mdl.Methods) != 1 {
+		return fmt.Errorf("wrong number of methods; expected 1, got %v", len(mdl.Methods))
+	}
+	if mdl.Methods[0].Name != MethodSelf {
+		return fmt.Errorf("First method is not called %s", MethodSelf)
+	}
+	for _, sel := range mdl.Methods[0].Selectors {
+		if sel.Kind == x.SelectorKindType {
+			return fmt.Errorf("selector kind %q is not supported", sel.Kind)
+		}
+		qual := sel.GetFuncQualifier()
+		if qual == nil {
+			continue
+		}
+		fn := x.GetFuncByQualifier(qual)
+		receiver, parameterIndexes, _ := x.PosToRelativeIndexes(fn, qual.Pos)
+		if receiver || len(parameterIndexes) > 0 {
+			return fmt.Errorf("%s: only results can be selected", fn.GetFunc().Name)
+		}
+	}
+	return nil
+}
+
+// zipSlipSource generates the models and the tests of the model.
+var zipSlipSource = &untrustedflowsource.Source{
+	Doc:              "Provides models of archive entry names, which are zip slip sources.",
+	Extends:          "ZipSlip::Source",
+	Import:           "semmle.go.security.ZipSlipCustomizations",
+	Comment:          "Zip slip sources",
+	TestTag:          InlineExpectationsTestTag,
+	TestQueryContent: TestQueryContent,
+}
@@ -34,11 +34,33 @@ import (
 	"golang.org/x/mod/modfile"
 	"golang.org/x/tools/go/packages"
 
+	"github.com/gagliardetto/codemill/handlers/barrier"
+	"github.com/gagliardetto/codemill/handlers/cryptographicoperation"
+	"github.com/gagliardetto/codemill/handlers/encoding/marshaling"
+	"github.com/gagliardetto/codemill/handlers/encoding/unmarshaling"
+	"github.com/gagliardetto/codemill/handlers/filesystemaccess"
+	"github.com/gagliardetto/codemill/handlers/http/clientrequest"
+	"github.com/gagliardetto/codemill/handlers/http/cookiewrite"
 	"github.com/gagliardetto/codemill/handlers/http/headerwrite"
 	"github.com/gagliardetto/codemill/handlers/http/redirect"
+	"github.com/gagliardetto/codemill/handlers/http/requesthandler"
 	"github.com/gagliardetto/codemill/handlers/http/responsebody"
+	"github.com/gagliardetto/codemill/handlers/http/responsewriter"
+	"github.com/gagliardetto/codemill/handlers/http/templateexecution"
+	"github.com/gagliardetto/codemill/handlers/insecureconfig"
+	"github.com/gagliardetto/codemill/handlers/localuserinput"
+	"github.com/gagliardetto/codemill/handlers/loggercall"
+	"github.com/gagliardetto/codemill/handlers/nosql/query"
+	"github.com/gagliardetto/codemill/handlers/regex"
+	"github.com/gagliardetto/codemill/handlers/sanitizerguard"
+	"github.com/gagliardetto/codemill/handlers/sensitivedata"
+	"github.com/gagliardetto/codemill/handlers/sql/querystring"
+	"github.com/gagliardetto/codemill/handlers/stringformatter"
+	"github.com/gagliardetto/codemill/handlers/stringops"
+	"github.com/gagliardetto/codemill/handlers/systemcommandexecution"
 	"github.com/gagliardetto/codemill/handlers/tainttracking"
 	"github.com/gagliardetto/codemill/handlers/untrustedflowsource"
+	"github.com/gagliardetto/codemill/handlers/zipslip"
 )
 
 type M map[string]interface{}
@@ -184,6 +206,138 @@ func main() {
 			if err != nil {
 				Fatalf("error while registering handler: %s", err)
 			}
+
+			// sql querystring handler:
+			err = rt.RegisterHandler(querystring.Kind, &querystring.Handler{})
+			if err != nil {
+				Fatalf("error while registering handler: %s", err)
+			}
+
+			// system command execution handler:
+			err = rt.RegisterHandler(systemcommandexecution.Kind, &systemcommandexecution.Handler{})
+			if err != nil {
+				Fatalf("error while registering handler: %s", err)
+			}
+
+			// file system access handler:
+			err = rt.RegisterHandler(filesystemaccess.Kind, &filesystemaccess.Handler{})
+			if err != nil {
+				Fatalf("error while registering handler: %s", err)
+			}
+
+			// http clientrequest handler:
+			err = rt.RegisterHandler(clientrequest.Kind, &clientrequest.Handler{})
+			if err != nil {
+				Fatalf("error while registering handler: %s", err)
+			}
+
+			// logger call handler:
+			err = rt.RegisterHandler(loggercall.Kind, &loggercall.Handler{})
+			if err != nil {
+				Fatalf("error while registering handler: %s", err)
+			}
+
+			// encoding marshaling handler:
+			err = rt.RegisterHandler(marshaling.Kind, &marshaling.Handler{})
+			if err != nil {
+				Fatalf("error while registering handler: %s", err)
+			}
+
+			// encoding unmarshaling handler:
+			err = rt.RegisterHandler(unmarshaling.Kind, &unmarshaling.Handler{})
+			if err != nil {
+				Fatalf("error while registering handler: %s", err)
+			}
+
+			// regexp handler:
+			err = rt.RegisterHandler(regex.Kind, &regex.Handler{})
+			if err != nil {
+				Fatalf("error while registering handler: %s", err)
+			}
+
+			// HTTP::TemplateExecution handler:
+			err = rt.RegisterHandler(templateexecution.Kind, &templateexecution.Handler{})
+			if err != nil {
+				Fatalf("error while registering handler: %s", err)
+			}
+
+			// HTTP::CookieWrite handler:
+			err = rt.RegisterHandler(cookiewrite.Kind, &cookiewrite.Handler{})
+			if err != nil {
+				Fatalf("error while registering handler: %s", err)
+			}
+
+			// CryptographicOperation handler:
+			err = rt.RegisterHandler(cryptographicoperation.Kind, &cryptographicoperation.Handler{})
+			if err != nil {
+				Fatalf("error while registering handler: %s", err)
+			}
+
+			// TaintTracking::SanitizerGuard handler:
+			err = rt.RegisterHandler(sanitizerguard.Kind, &sanitizerguard.Handler{})
+			if err != nil {
+				Fatalf("error while registering handler: %s", err)
+			}
+
+			// barrier handler:
+			err = rt.RegisterHandler(barrier.Kind, &barrier.Handler{})
+			if err != nil {
+				Fatalf("error while registering handler: %s", err)
+			}
+
+			// Insecure configurations via struct field writes handler:
+			err = rt.RegisterHandler(insecureconfig.Kind, &insecureconfig.Handler{})
+			if err != nil {
+				Fatalf("error while registering handler: %s", err)
+			}
+
+			// Sensitive data handler:
+			err = rt.RegisterHandler(sensitivedata.Kind, &sensitivedata.Handler{})
+			if err != nil {
+				Fatalf("error while registering handler: %s", err)
+			}
+
+			// NoSQL query handler:
+			err = rt.RegisterHandler(query.Kind, &query.Handler{})
+			if err != nil {
+				Fatalf("error while registering handler: %s", err)
+			}
+
+			// HTTP request handler registration handler:
+			err = rt.RegisterHandler(requesthandler.Kind, &requesthandler.Handler{})
+			if err != nil {
+				Fatalf("error while registering handler: %s", err)
+			}
+
+			// Local user input sources handler:
+			err = rt.RegisterHandler(localuserinput.Kind, &localuserinput.Handler{})
+			if err != nil {
+				Fatalf("error while registering handler: %s", err)
+			}
+
+			// Zip slip sources handler:
+			err = rt.RegisterHandler(zipslip.Kind, &zipslip.Handler{})
+			if err != nil {
+				Fatalf("error while registering handler: %s", err)
+			}
+
+			// String formatters handler:
+			err = rt.RegisterHandler(stringformatter.Kind, &stringformatter.Handler{})
+			if err != nil {
+				Fatalf("error while registering handler: %s", err)
+			}
+
+			// String operations handler:
+			err = rt.RegisterHandler(stringops.Kind, &stringops.Handler{})
+			if err != nil {
+				Fatalf("error while registering handler: %s", err)
+			}
+
+			// HTTP response writer handler:
+			err = rt.RegisterHandler(responsewriter.Kind, &responsewriter.Handler{})
+			if err != nil {
+				Fatalf("error while registering handler: %s", err)
+			}
 		}
 	}
 
@@ -264,7 +418,6 @@ func main() {
 						mdl.Kind,
 					)
 				}
-
 				{
 					// Validate provided model:
 					err := handler.Validate(mdl)
@@ -427,6 +580,223 @@ func main() {
 		c.IndentedJSON(200, globalSpec)
 	})
 
+	r.PATCH("/api/spec/models/options", func(c *gin.Context) {
+		// Set the value of a model option:
+		var req struct {
+			Where struct {
+				Model string
+			}
+			What struct {
+				Name  string
+				Value string
+			}
+		}
+		err := c.BindJSON(&req)
+		if err != nil {
+			Q(err)
+			Abort400(c, err.Error())
+			return
+		}
+
+		err = globalSpec.ModifyModelByName(
+			req.Where.Model,
+			func(mdl *x.XModel) error {
+				return mdl.SetOption(req.What.Name, req.What.Value)
+			},
+		)
+		if err != nil {
+			Abort400(c, Sf("Error modifying model: %s", err))
+			return
+		}
+
+		c.IndentedJSON(200, globalSpec)
+	})
+
+	r.PATCH("/api/spec/contenttype/rules", func(c *gin.Context) {
+		// Set (or remove, if the content-type is empty) a content-type rule:
+		var req struct {
+			What struct {
+				Substring   string
+				ContentType string
+			}
+		}
+		err := c.BindJSON(&req)
+		if err != nil {
+			Q(err)
+			Abort400(c, err.Error())
+			return
+		}
+
+		err = globalSpec.SetContentTypeRule(req.What.Substring, req.What.ContentType)
+		if err != nil {
+			Abort400(c, Sf("Error modifying content-type rules: %s", err))
+			return
+		}
+
+		c.IndentedJSON(200, globalSpec)
+	})
+
+	r.PATCH("/api/spec/funcs/contenttype", func(c *gin.Context) {
+		// Set (or remove, if empty) the content-type override of a selected func:
+		var req struct {
+			Where struct {
+				Path    string
+				Version string
+				Model   string
+				Method  string
+			}
+			What struct {
+				FuncID      string
+				ContentType string
+			}
+		}
+		err := c.BindJSON(&req)
+		if err != nil {
+			Q(err)
+			Abort400(c, err.Error())
+			return
+		}
+
+		err = globalSpec.ModifyModelByName(
+			req.Where.Model,
+			func(mdl *x.XModel) error {
+				return mdl.ModifyMethodByName(
+					req.Where.Method,
+					func(mt *x.XMethod) error {
+						qual := mt.GetFuncSelector(
+							req.Where.Path,
+							req.Where.Version,
+							req.What.FuncID,
+						)
+						if qual == nil {
+							return fmt.Errorf("Func %q is not selected in method %q", req.What.FuncID, req.Where.Method)
+						}
+						qual.ContentType = strings.TrimSpace(req.What.ContentType)
+						return nil
+					},
+				)
+			},
+		)
+		if err != nil {
+			Abort400(c, Sf("Error modifying model: %s", err))
+			return
+		}
+
+		c.IndentedJSON(200, globalSpec)
+	})
+
+	r.PATCH("/api/spec/funcs/renderer/contenttype", func(c *gin.Context) {
+		// Set (or remove, if empty) the content-type of a renderer type passed to a selected func:
+		var req struct {
+			Where struct {
+				Path    string
+				Version string
+				Model   string
+				Method  string
+			}
+			What struct {
+				FuncID       string
+				RendererType string // e.g. `github.com/gin-gonic/gin/render.JSON`
+				ContentType  string
+			}
+		}
+		err := c.BindJSON(&req)
+		if err != nil {
+			Q(err)
+			Abort400(c, err.Error())
+			return
+		}
+		rendererType := strings.TrimSpace(req.What.RendererType)
+		if rendererType == "" {
+			Abort400(c, "RendererType is empty")
+			return
+		}
+
+		err = globalSpec.ModifyModelByName(
+			req.Where.Model,
+			func(mdl *x.XModel) error {
+				return mdl.ModifyMethodByName(
+					req.Where.Method,
+					func(mt *x.XMethod) error {
+						qual := mt.GetFuncSelector(
+							req.Where.Path,
+							req.Where.Version,
+							req.What.FuncID,
+						)
+						if qual == nil {
+							return fmt.Errorf("Func %q is not selected in method %q", req.What.FuncID, req.Where.Method)
+						}
+						contentType := strings.TrimSpace(req.What.ContentType)
+						if contentType == "" {
+							delete(qual.RendererContentTypes, rendererType)
+							return nil
+						}
+						if qual.RendererContentTypes == nil {
+							qual.RendererContentTypes = make(map[string]string)
+						}
+						qual.RendererContentTypes[rendererType] = contentType
+						return nil
+					},
+				)
+			},
+		)
+		if err != nil {
+			Abort400(c, Sf("Error modifying model: %s", err))
+			return
+		}
+
+		c.IndentedJSON(200, globalSpec)
+	})
+
+	r.PATCH("/api/spec/funcs/algorithm", func(c *gin.Context) {
+		// Set (or remove, if empty) the cryptographic algorithm of a selected func:
+		var req struct {
+			Where struct {
+				Path    string
+				Version string
+				Model   string
+				Method  string
+			}
+			What struct {
+				FuncID    string
+				Algorithm string
+			}
+		}
+		err := c.BindJSON(&req)
+		if err != nil {
+			Q(err)
+			Abort400(c, err.Error())
+			return
+		}
+
+		err = globalSpec.ModifyModelByName(
+			req.Where.Model,
+			func(mdl *x.XModel) error {
+				return mdl.ModifyMethodByName(
+					req.Where.Method,
+					func(mt *x.XMethod) error {
+						qual := mt.GetFuncSelector(
+							req.Where.Path,
+							req.Where.Version,
+							req.What.FuncID,
+						)
+						if qual == nil {
+							return fmt.Errorf("Func %q is not selected in method %q", req.What.FuncID, req.Where.Method)
+						}
+						qual.Algorithm = strings.ToUpper(strings.TrimSpace(req.What.Algorithm))
+						return nil
+					},
+				)
+			},
+		)
+		if err != nil {
+			Abort400(c, Sf("Error modifying model: %s", err))
+			return
+		}
+
+		c.IndentedJSON(200, globalSpec)
+	})
+
 	r.PATCH("/api/spec/structs", func(c *gin.Context) {
 		// Patch a struct, i.e. add/remove a field:
 		var req struct {
@@ -629,8 +999,8 @@ func main() {
 		err = globalSpec.ModifyModelByName(
 			req.Where.Model,
 			func(mdl *x.XModel) error {
-				// Currently, only the tainttracking.Handler is the only handler
-				// that supports flow handling.
+				// Currently, only the tainttracking.Handler and the barrier.Handler
+				// support flow handling.
 				if req.Flow != nil && !ModelSupportsFuncFlow(mdl) {
 					return errors.New("This model does not support func flow qualifiers.")
 				}
@@ -851,8 +1221,8 @@ func main() {
 		err = globalSpec.ModifyModelByName(
 			req.Where.Model,
 			func(mdl *x.XModel) error {
-				// Currently, only the tainttracking.Handler is the only handler
-				// that supports flow handling.
+				// Currently, only the tainttracking.Handler and the barrier.Handler
+				// support flow handling.
 				if !ModelSupportsFuncFlow(mdl) {
 					return errors.New("This model does not support func flow qualifiers.")
 				}
@@ -1195,9 +1565,10 @@ func main() {
 }
 
 func ModelSupportsFuncFlow(mdl *x.XModel) bool {
-	// Currently, only the tainttracking.Handler is the only handler
-	// that supports flow handling.
-	if mdl.Kind == tainttracking.Kind {
+	// Currently, only the tainttracking.Handler and the barrier.Handler
+	// support flow handling.
+	switch mdl.Kind {
+	case tainttracking.Kind, barrier.Kind:
 		return true
 	}
 	return false
@@ -1,6 +1,7 @@
 package x
 
 import (
+	"github.com/gagliardetto/codebox/scanner"
 	. "github.com/gagliardetto/cqlgen/jen"
 	"github.com/gagliardetto/feparser"
 	. "github.com/gagliardetto/utilz"
@@ -143,3 +144,215 @@ func CqlParamQualToCode(cqlReveiverName string, cqlMethodName string, qual *Func
 
 	return fn, code
 }
+
+// CqlCallTargets composes the disjunction (by package) of all the calls selected in the provided method,
+// binding the package path to the `package` variable and the call to the callName variable,
+// and adding the code returned by gen for each func; the comment (e.g. "SQL query string models")
+// precedes the models of each package. Returns the code and the number of packages added.
+func CqlCallTargets(allPathVersions []string, mtd *XMethod, callName string, comment string, gen func(fn FuncInterface, qual *FuncQualifier) Code) (Code, int) {
+	b2fe, b2tm, b2itm, err := GroupFuncSelectors(mtd)
+	if err != nil {
+		Fatalf("Error while GroupFuncSelectors: %s", err)
+	}
+
+	addedCount := 0
+	code := DoGroup(
+		func(groupCase *Group) {
+			for _, pathVersion := range allPathVersions {
+				pathCodez := make([]Code, 0)
+				// Functions:
+				{
+					cont, ok := b2fe[pathVersion]
+					if ok {
+						for _, funcQual := range cont {
+							if AllFalse(funcQual.Pos...) {
+								continue
+							}
+							fn := GetFuncByQualifier(funcQual)
+							thing := fn.(*feparser.FEFunc)
+							pathCodez = append(pathCodez,
+								ParensFunc(
+									func(par *Group) {
+										par.Commentf("signature: %s", thing.Signature)
+										par.Id(callName).
+											Dot("getTarget").Call().
+											Dot("hasQualifiedName").Call(
+											Id("package"),
+											Lit(thing.Name),
+										)
+
+										par.And()
+
+										par.Add(gen(fn, funcQual))
+									},
+								),
+							)
+						}
+
+					}
+				}
+				// Type methods:
+				{
+					b2tm.IterValid(pathVersion,
+						func(receiverTypeID string, methodQualifiers FuncQualifierSlice) {
+							codez := DoGroup(func(mtdGroup *Group) {
+								qual := methodQualifiers[0]
+								source := GetCachedSource(qual.Path, qual.Version)
+								if source == nil {
+									Fatalf("Source not found: %s@%s", qual.Path, qual.Version)
+								}
+								// Find receiver type:
+								typ := FindTypeByID(source, receiverTypeID)
+								if typ == nil {
+									Fatalf("Type not found: %q", receiverTypeID)
+								}
+
+								mtdGroup.Commentf("Receiver type: %s", typ.TypeString)
+
+								methodIndex := 0
+								mtdGroup.ParensFunc(
+									func(parMethods *Group) {
+										for _, methodQual := range methodQualifiers {
+											if AllFalse(methodQual.Pos...) {
+												continue
+											}
+											if methodIndex > 0 {
+												parMethods.Or()
+											}
+											methodIndex++
+
+											fn := GetFuncByQualifier(methodQual)
+											thing := fn.(*feparser.FETypeMethod)
+
+											parMethods.ParensFunc(
+												func(par *Group) {
+													par.Commentf("signature: %s", thing.Func.Signature)
+
+													par.Id(callName).
+														Eq().
+														Any(
+															DoGroup(func(gr *Group) {
+																gr.Id("Method").Id("m")
+															}),
+															DoGroup(func(gr *Group) {
+																gr.Id("m").Dot("hasQualifiedName").Call(
+																	Id("package"),
+																	Lit(thing.Receiver.TypeName),
+																	Lit(thing.Func.Name),
+																)
+															}),
+															nil,
+														).Dot("getACall").Call()
+
+													par.And()
+
+													par.Add(gen(fn, methodQual))
+												},
+											)
+
+										}
+									},
+								)
+
+							})
+							pathCodez = append(pathCodez, codez)
+						})
+				}
+				// Interface methods:
+				{
+					b2itm.IterValid(pathVersion,
+						func(receiverTypeID string, methodQualifiers FuncQualifierSlice) {
+							codez := DoGroup(func(mtdGroup *Group) {
+								qual := methodQualifiers[0]
+								source := GetCachedSource(qual.Path, qual.Version)
+								if source == nil {
+									Fatalf("Source not found: %s@%s", qual.Path, qual.Version)
+								}
+								// Find receiver type:
+								typ := FindTypeByID(source, receiverTypeID)
+								if typ == nil {
+									Fatalf("Type not found: %q", receiverTypeID)
+								}
+								mtdGroup.Commentf("Receiver interface: %s", typ.TypeString)
+
+								methodIndex := 0
+								mtdGroup.ParensFunc(
+									func(parMethods *Group) {
+										for _, methodQual := range methodQualifiers {
+											if AllFalse(methodQual.Pos...) {
+												continue
+											}
+											if methodIndex > 0 {
+												parMethods.Or()
+											}
+											methodIndex++
+
+											fn := GetFuncByQualifier(methodQual)
+											thing := fn.(*feparser.FEInterfaceMethod)
+
+											parMethods.ParensFunc(
+												func(par *Group) {
+													par.Commentf("signature: %s", thing.Func.Signature)
+
+													par.Id(callName).
+														Eq().
+														Any(
+															DoGroup(func(gr *Group) {
+																gr.Id("Method").Id("m")
+															}),
+															DoGroup(func(gr *Group) {
+																gr.Id("m").Dot("implements").Call(
+																	Id("package"),
+																	Lit(thing.Receiver.TypeName),
+																	Lit(thing.Func.Name),
+																)
+															}),
+															nil,
+														).Dot("getACall").Call()
+
+													par.And()
+
+													par.Add(gen(fn, methodQual))
+												},
+											)
+
+										}
+									},
+								)
+
+							})
+							pathCodez = append(pathCodez, codez)
+						})
+				}
+
+				if len(pathCodez) > 0 {
+					if addedCount > 0 {
+						groupCase.Or()
+					}
+					path, _ := scanner.SplitPathVersion(pathVersion)
+					groupCase.Commentf("%s for package: %s", comment, pathVersion)
+					groupCase.Id("package").Eq().Add(CqlFormatPackagePath(path)).And()
+
+					groupCase.Parens(
+						Join(
+							Or(),
+							pathCodez...,
+						),
+					)
+
+					addedCount++
+				}
+			}
+		})
+
+	return code, addedCount
+}
+
+// CqlBindArgument returns a gen func (see CqlCallTargets) that binds
+// the nodeName variable to the arguments selected in the qualifier.
+func CqlBindArgument(callName string, nodeName string) func(fn FuncInterface, qual *FuncQualifier) Code {
+	return func(fn FuncInterface, qual *FuncQualifier) Code {
+		_, code := CqlParamQualToCode(callName, "getArgument", qual)
+		return Id(nodeName).Eq().Add(code)
+	}
+}
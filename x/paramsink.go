@@ -0,0 +1,37 @@
+package x
+
+import (
+	"go/types"
+
+	. "github.com/dave/jennifer/jen"
+)
+
+// ParamSink describes a model kind whose models are the parameters (selected via Pos)
+// of funcs and methods that act as sinks (e.g. SQL query strings, file paths, URLs);
+// it generates both the CodeQL class and the Go tests for the selectors of one method.
+type ParamSink struct {
+	// CodeQL:
+	Doc     string // Doc of the generated class.
+	Extends string // Range class extended by the generated class.
+	Comment string // Name of the models (e.g. "SQL query string"), used in the generated comments.
+	// If NodeName is set, the generated class is the call, and the selected arguments
+	// are bound to NodeName and returned by the Override member predicate;
+	// otherwise, the generated class is the selected argument itself.
+	NodeName string
+	Override string
+
+	// Go tests:
+	TestTag          string // Tag of the inline expectations; must start with a $ sign.
+	TestQueryContent string // Content of the <name>.ql test query.
+	VarPrefix        string // Prefix of the names of the variables passed as the selected arguments.
+	// If VariadicPairs is set, two arguments (e.g. a key and a value)
+	// are passed to a selected variadic parameter.
+	VariadicPairs bool
+	// ComposeArgument declares each variable passed as a selected argument;
+	// if not set, ComposeSourceTypeAssertion is used.
+	ComposeArgument func(file *File, group *Group, varName string, typ types.Type, isVariadic bool)
+
+	// The handlers share one ParamSink per ModelKind; set these on a copy of it.
+	GenerateBoilerplate bool
+	IncludeComments     bool
+}
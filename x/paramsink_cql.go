@@ -0,0 +1,77 @@
+package x
+
+import (
+	. "github.com/gagliardetto/cqlgen/jen"
+	"github.com/gagliardetto/feparser"
+	. "github.com/gagliardetto/utilz"
+)
+
+// GenerateCodeQL adds to the module group the class that models
+// the arguments selected in the provided method.
+func (sink *ParamSink) GenerateCodeQL(mdl *XModel, mtd *XMethod, rootModuleGroup *Group) error {
+	if len(mtd.Selectors) == 0 {
+		Infof("No selectors found for %q method.", mtd.Name)
+		return nil
+	}
+
+	className := feparser.NewCodeQlName(mdl.Name)
+	allPathVersions := mdl.ListAllPathVersions()
+	comment := sink.Comment + " models"
+
+	if sink.NodeName == "" {
+		// The class is the argument:
+		targetsCode, targetsCount := CqlCallTargets(allPathVersions, mtd, "call", comment,
+			func(fn FuncInterface, qual *FuncQualifier) Code {
+				_, code := CqlParamQualToCode("call", "getArgument", qual)
+				return This().Eq().Add(code)
+			},
+		)
+		if targetsCount == 0 {
+			return nil
+		}
+		rootModuleGroup.Doc(sink.Doc)
+		rootModuleGroup.Private().Class().Id(className).Extends().List(
+			Id(sink.Extends),
+		).BlockFunc(
+			func(classGroup *Group) {
+				classGroup.Id(className).Call().BlockFunc(
+					func(selfGroup *Group) {
+						selfGroup.Exists(
+							List(
+								String().Id("package"),
+								Id("DataFlow::CallNode").Id("call"),
+							),
+							targetsCode,
+							nil,
+						)
+					})
+			})
+		return nil
+	}
+
+	// The class is the call:
+	targetsCode, targetsCount := CqlCallTargets(allPathVersions, mtd, "this", comment,
+		CqlBindArgument("this", sink.NodeName),
+	)
+	if targetsCount == 0 {
+		return nil
+	}
+	rootModuleGroup.Doc(sink.Doc)
+	rootModuleGroup.Private().Class().Id(className).Extends().List(
+		Id(sink.Extends),
+		Id("DataFlow::CallNode"),
+	).BlockFunc(
+		func(classGroup *Group) {
+			classGroup.String().Id("package").Semicolon().Line()
+			classGroup.Id("DataFlow::Node").Id(sink.NodeName).Semicolon().Line()
+
+			classGroup.Id(className).Call().Block(
+				targetsCode,
+			)
+
+			classGroup.Override().Id("DataFlow::Node").Id(sink.Override).Call().Block(
+				Id("result").Eq().Id(sink.NodeName),
+			)
+		})
+	return nil
+}
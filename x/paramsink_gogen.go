@@ -0,0 +1,361 @@
+package x
+
+import (
+	"go/types"
+	"os"
+	"path/filepath"
+
+	. "github.com/dave/jennifer/jen"
+	"github.com/gagliardetto/codebox/gogentools"
+	"github.com/gagliardetto/feparser"
+	. "github.com/gagliardetto/utilz"
+)
+
+// tag composes the inline expectations comment for the provided values.
+func (sink *ParamSink) tag(vals ...string) Code {
+	tg := ""
+	for i, v := range vals {
+		if i > 0 {
+			tg += " "
+		}
+		tg += sink.TestTag + "=" + v
+	}
+	return Comment(tg)
+}
+
+func (sink *ParamSink) newTestFile() *File {
+	file := NewFile("main")
+	// Set a prefix to avoid collision between variable names and packages:
+	file.PackagePrefix = "cql"
+	// Add comment to file:
+	file.HeaderComment("Code generated by https://github.com/gagliardetto. DO NOT EDIT.")
+
+	if sink.GenerateBoilerplate {
+		file.PackageComment("//go:generate depstubber --vendor --auto")
+		{
+			// main function:
+			file.Func().Id("main").Params().Block()
+		}
+		{
+			// The `source` function:
+			code := Func().
+				Id("source").
+				Params().
+				Interface().
+				Block(Return(Nil()))
+			file.Add(code.Line())
+		}
+	}
+	return file
+}
+
+// comments adds comments to a Group (if enabled), and returns the group.
+func (sink *ParamSink) comments(group *Group, comments ...string) *Group {
+	if sink.IncludeComments {
+		for _, comment := range comments {
+			group.Line().Comment(comment)
+		}
+	}
+	return group
+}
+
+// GenerateGo generates the Go tests for the selectors of the provided method.
+func (sink *ParamSink) GenerateGo(parentDir string, mdl *XModel, mtd *XMethod) error {
+	// Check if there are multiple versions of a same package:
+	mods := mdl.ListModules()
+	if HasMultiversion(mods) {
+		Ln(RedBG("Has multiversion"))
+	}
+	// If there are no multiple versions of the same module,
+	// that means we can save all the code to one file.
+	allInOneFile := !HasMultiversion(mods)
+
+	// Create the directory for the tests for this model:
+	outDir := filepath.Join(parentDir, feparser.NewCodeQlName(mdl.Name))
+	MustCreateFolderIfNotExists(outDir, os.ModePerm)
+
+	if len(mtd.Selectors) == 0 {
+		Infof("No selectors found for %q method.", mtd.Name)
+		return nil
+	}
+
+	allPathVersions := mdl.ListAllPathVersions()
+
+	file := sink.newTestFile()
+
+	for _, pathVersion := range allPathVersions {
+		if !allInOneFile {
+			// Reset file:
+			file = sink.newTestFile()
+		}
+		codez := make([]Code, 0)
+
+		b2fe, b2tm, b2itm, err := GroupFuncSelectors(mtd)
+		if err != nil {
+			Fatalf("Error while GroupFuncSelectors: %s", err)
+		}
+
+		{
+			cont, ok := b2fe[pathVersion]
+			if ok && HasValidPos(cont...) {
+				addedCount := 0
+				code := BlockFunc(
+					func(groupCase *Group) {
+
+						for _, funcQual := range cont {
+							fn := GetFuncByQualifier(funcQual)
+							thing := fn.(*feparser.FEFunc)
+
+							AddImportsFromFunc(file, thing)
+
+							{
+								if AllFalse(funcQual.Pos...) {
+									continue
+								}
+								groupCase.Comment(thing.Signature)
+
+								groupCase.Add(sink.generateGoTestBlock(file, thing, funcQual))
+								addedCount++
+							}
+
+						}
+					})
+				if addedCount > 0 {
+					codez = append(codez,
+						Commentf("%s via function call.", sink.Comment).
+							Line().
+							Add(code),
+					)
+				}
+			}
+		}
+		{
+			codezTypeMethods := make([]Code, 0)
+			b2tm.IterValid(pathVersion,
+				func(receiverTypeID string, methodQualifiers FuncQualifierSlice) {
+
+					qual := methodQualifiers[0]
+					// Find receiver type:
+					typ := FindType(qual.Path, qual.Version, receiverTypeID)
+					if typ == nil {
+						Fatalf("Type not found: %q", receiverTypeID)
+					}
+
+					gogentools.ImportPackage(file, typ.PkgPath, typ.PkgName)
+
+					code := BlockFunc(
+						func(groupCase *Group) {
+
+							for _, methodQual := range methodQualifiers {
+								fn := GetFuncByQualifier(methodQual)
+								thing := fn.(*feparser.FETypeMethod)
+								AddImportsFromFunc(file, fn)
+
+								{
+									if AllFalse(methodQual.Pos...) {
+										continue
+									}
+									groupCase.Comment(thing.Func.Signature)
+
+									groupCase.Add(sink.generateGoTestBlock(file, thing, methodQual))
+								}
+
+							}
+						})
+					codezTypeMethods = append(codezTypeMethods,
+						Commentf("%s via method calls on %s.", sink.Comment, typ.QualifiedName).
+							Line().
+							Add(code),
+					)
+				})
+			if len(codezTypeMethods) > 0 {
+				codez = append(codez,
+					Commentf("%s via method calls.", sink.Comment).
+						Line().
+						Block(codezTypeMethods...),
+				)
+			}
+		}
+
+		{
+			codezIfaceMethods := make([]Code, 0)
+			b2itm.IterValid(pathVersion,
+				func(receiverTypeID string, methodQualifiers FuncQualifierSlice) {
+					qual := methodQualifiers[0]
+					// Find receiver type:
+					typ := FindType(qual.Path, qual.Version, receiverTypeID)
+					if typ == nil {
+						Fatalf("Type not found: %q", receiverTypeID)
+					}
+
+					gogentools.ImportPackage(file, typ.PkgPath, typ.PkgName)
+
+					code := BlockFunc(
+						func(groupCase *Group) {
+
+							for _, methodQual := range methodQualifiers {
+								fn := GetFuncByQualifier(methodQual)
+								thing := fn.(*feparser.FEInterfaceMethod)
+								AddImportsFromFunc(file, fn)
+
+								{
+									if AllFalse(methodQual.Pos...) {
+										continue
+									}
+									groupCase.Comment(thing.Func.Signature)
+
+									converted := feparser.FEIToFET(thing)
+									groupCase.Add(sink.generateGoTestBlock(file, converted, methodQual))
+								}
+							}
+						})
+					codezIfaceMethods = append(codezIfaceMethods,
+						Commentf("%s via method calls on %s interface.", sink.Comment, typ.QualifiedName).
+							Line().
+							Add(code),
+					)
+				})
+
+			if len(codezIfaceMethods) > 0 {
+				codez = append(codez,
+					Commentf("%s via interface method calls.", sink.Comment).
+						Line().
+						Block(codezIfaceMethods...),
+				)
+			}
+		}
+
+		{
+			file.Commentf("Package %s", pathVersion)
+			file.Func().Id(mdl.Name + "_" + feparser.FormatCodeQlName(pathVersion)).Params().Block(codez...)
+		}
+
+		if !allInOneFile {
+			pkgDstDirpath := filepath.Join(outDir, feparser.FormatID(mdl.Name, "For", feparser.FormatCodeQlName(pathVersion)))
+			MustCreateFolderIfNotExists(pkgDstDirpath, os.ModePerm)
+
+			assetFileName := feparser.FormatID(mdl.Name, "For", feparser.FormatCodeQlName(pathVersion)) + ".go"
+			if err := SaveGoFile(pkgDstDirpath, assetFileName, file); err != nil {
+				Fatalf("Error while saving go file: %s", err)
+			}
+
+			if err := WriteGoModFile(pkgDstDirpath, pathVersion); err != nil {
+				Fatalf("Error while saving go.mod file: %s", err)
+			}
+			if err := WriteCodeQLTestQuery(pkgDstDirpath, mdl.Name, sink.TestQueryContent); err != nil {
+				Fatalf("Error while saving <name>.ql file: %s", err)
+			}
+			if err := WriteEmptyCodeQLDotExpectedFile(pkgDstDirpath, mdl.Name); err != nil {
+				Fatalf("Error while saving <name>.expected file: %s", err)
+			}
+		}
+	}
+
+	if allInOneFile {
+		pkgDstDirpath := outDir
+		MustCreateFolderIfNotExists(pkgDstDirpath, os.ModePerm)
+
+		assetFileName := feparser.FormatID(mdl.Name) + ".go"
+		if err := SaveGoFile(pkgDstDirpath, assetFileName, file); err != nil {
+			Fatalf("Error while saving go file: %s", err)
+		}
+
+		if err := WriteGoModFile(pkgDstDirpath, allPathVersions...); err != nil {
+			Fatalf("Error while saving go.mod file: %s", err)
+		}
+		if err := WriteCodeQLTestQuery(pkgDstDirpath, mdl.Name, sink.TestQueryContent); err != nil {
+			Fatalf("Error while saving <name>.ql file: %s", err)
+		}
+		if err := WriteEmptyCodeQLDotExpectedFile(pkgDstDirpath, mdl.Name); err != nil {
+			Fatalf("Error while saving <name>.expected file: %s", err)
+		}
+	}
+	return nil
+}
+
+// generateGoTestBlock generates a block that passes
+// new sources as the selected arguments of a call to the func.
+func (sink *ParamSink) generateGoTestBlock(file *File, fn FuncInterface, qual *FuncQualifier) *Statement {
+	indexes := MustPosToRelativeParamIndexes(fn, qual.Pos)
+	params := fn.GetFunc().Parameters
+	isVariadic := fn.GetFunc().GetOriginal().IsVariadic()
+
+	// The names of the variables passed for each selected parameter:
+	varNamesByIndex := make(map[int][]string)
+	varNames := make([]string, 0)
+	for _, index := range indexes {
+		count := 1
+		if sink.VariadicPairs && isVariadic && index == len(params)-1 {
+			count = 2
+		}
+		for i := 0; i < count; i++ {
+			varName := gogentools.NewNameWithPrefix(feparser.NewLowerTitleName(sink.VarPrefix, params[index].TypeName))
+			varNamesByIndex[index] = append(varNamesByIndex[index], varName)
+			varNames = append(varNames, varName)
+		}
+	}
+
+	composeArgument := sink.ComposeArgument
+	if composeArgument == nil {
+		composeArgument = ComposeSourceTypeAssertion
+	}
+
+	return BlockFunc(
+		func(groupCase *Group) {
+
+			for _, index := range indexes {
+				in := params[index]
+				for _, varName := range varNamesByIndex[index] {
+					composeArgument(file, groupCase, varName, in.GetOriginal().GetType(), in.GetOriginal().IsVariadic())
+				}
+			}
+
+			var call *Statement
+			if receiver := fn.GetReceiver(); receiver != nil {
+				sink.comments(groupCase, "Declare medium object/interface:")
+				groupCase.Var().Id("rece").Qual(receiver.PkgPath, receiver.TypeName)
+
+				gogentools.ImportPackage(file, fn.GetFunc().PkgPath, fn.GetFunc().PkgName)
+				call = groupCase.Id("rece").Dot(fn.GetFunc().Name)
+			} else {
+				call = groupCase.Qual(fn.GetFunc().PkgPath, fn.GetFunc().Name)
+			}
+
+			call.CallFunc(
+				func(callGroup *Group) {
+
+					tpFun := fn.GetFunc().GetOriginal().GetType().(*types.Signature)
+
+					zeroVals := gogentools.ScanTupleOfZeroValues(file, tpFun.Params(), isVariadic)
+
+					for i, zero := range zeroVals {
+						if names, isConsidered := varNamesByIndex[i]; isConsidered {
+							for _, varName := range names {
+								callGroup.Id(varName)
+							}
+						} else {
+							callGroup.Add(zero)
+						}
+					}
+
+				},
+			).Add(sink.tag(varNames...))
+
+		})
+}
+
+// ComposeSourceTypeAssertion declares `name := source().(Type)`;
+// for variadic parameters, the type of the elements is used.
+func ComposeSourceTypeAssertion(file *File, group *Group, varName string, typ types.Type, isVariadic bool) {
+	assertContent := &Statement{}
+	if isVariadic {
+		if slice, ok := typ.(*types.Slice); ok {
+			gogentools.ComposeTypeDeclaration(file, assertContent, slice.Elem())
+		} else {
+			gogentools.ComposeTypeDeclaration(file, assertContent, typ)
+		}
+	} else {
+		gogentools.ComposeTypeDeclaration(file, assertContent, typ)
+	}
+	group.Id(varName).Op(":=").Id("source").Call().Assert(assertContent)
+}
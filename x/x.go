@@ -57,13 +57,68 @@ func NewScavengeMethods(kind ModelKind) []*XMethod {
 	return handler.ScavengeMethods()
 }
 
+// NewScavengeOptions returns the default options that are specific
+// to the provided kind; returns nil if the kind does not support options.
+func NewScavengeOptions(kind ModelKind) map[string]string {
+	handler := Router().GetHandler(kind)
+	if handler == nil {
+		panic(Sf("No default options scavenging for %q kind", kind))
+	}
+	optionsHandler, ok := handler.(ModelOptionsHandler)
+	if !ok {
+		return nil
+	}
+	return optionsHandler.ScavengeOptions()
+}
+
 type XSpec struct {
 	Name    string   // Name of the module, user-defined.
 	Preload []string // Preload any packages listed here;
 	Models  []*XModel
+
+	ContentTypeRules []*ContentTypeRule `json:",omitempty"` // Rules used to infer the content-type of a func from its name.
 	*sync.RWMutex
 }
 
+// ContentTypeRule infers the ContentType of the funcs
+// whose name contains Substring (case-insensitive).
+type ContentTypeRule struct {
+	Substring   string
+	ContentType string
+}
+
+// SetContentTypeRule sets the content-type for the funcs whose name
+// contains the provided substring; if the content-type is empty, the rule is removed.
+func (spec *XSpec) SetContentTypeRule(substring string, contentType string) error {
+	substring = strings.TrimSpace(substring)
+	contentType = strings.TrimSpace(contentType)
+	if substring == "" {
+		return errors.New("Substring is empty")
+	}
+
+	spec.Lock()
+	defer spec.Unlock()
+
+	for i, rule := range spec.ContentTypeRules {
+		if strings.EqualFold(rule.Substring, substring) {
+			if contentType == "" {
+				spec.ContentTypeRules = append(spec.ContentTypeRules[:i], spec.ContentTypeRules[i+1:]...)
+			} else {
+				rule.ContentType = contentType
+			}
+			return nil
+		}
+	}
+	if contentType == "" {
+		return fmt.Errorf("Content-type rule for %q not found", substring)
+	}
+	spec.ContentTypeRules = append(spec.ContentTypeRules, &ContentTypeRule{
+		Substring:   substring,
+		ContentType: contentType,
+	})
+	return nil
+}
+
 func (spec *XSpec) NormalizeName() error {
 	spec.Name = ToCamel(spec.Name)
 	if spec.Name == "" {
@@ -145,6 +200,82 @@ func (spec *XSpec) Cleanup() error {
 	return nil
 }
 
+// Migrate upgrades the models of a spec saved by a previous version,
+// backfilling the methods (and options) that were added to their ModelKind since then.
+func (spec *XSpec) Migrate() error {
+	for _, mdl := range spec.Models {
+		mdl.Migrate()
+	}
+	return nil
+}
+
+// MethodRenamer is implemented by a ModelKindHandler
+// whose methods have been renamed.
+type MethodRenamer interface {
+	// RenamedMethods maps the old method names to the new ones.
+	RenamedMethods() map[string]string
+}
+
+// ModelMigrator is implemented by a ModelKindHandler
+// that needs to upgrade the models saved by previous versions
+// (e.g. to move a model option to the selectors).
+type ModelMigrator interface {
+	// MigrateModel is called after the methods of the model have been migrated.
+	MigrateModel(mdl *XModel)
+}
+
+// Migrate renames the methods that have been renamed, and sorts the methods
+// in the order declared by the ModelKind, adding the missing ones (with no selectors);
+// unknown methods are kept at the end (and will fail the validation of the ModelKind).
+// The missing options are set to their default value;
+// then, the ModelKind can migrate the model itself (see ModelMigrator).
+func (mdl *XModel) Migrate() {
+	handler := Router().GetHandler(mdl.Kind)
+	if handler == nil {
+		return
+	}
+	if renamer, ok := handler.(MethodRenamer); ok {
+		for oldName, newName := range renamer.RenamedMethods() {
+			mtd := mdl.Methods.ByName(oldName)
+			if mtd != nil && mdl.Methods.ByName(newName) == nil {
+				Infof("Model %q: renaming method %q to %q", mdl.Name, oldName, newName)
+				mtd.Name = newName
+			}
+		}
+	}
+
+	methods := make(XMethodSlice, 0)
+	for _, def := range handler.ScavengeMethods() {
+		mtd := mdl.Methods.ByName(def.Name)
+		if mtd == nil {
+			Infof("Model %q: adding method %q", mdl.Name, def.Name)
+			mtd = def
+		}
+		mtd.Description = def.Description
+		methods = append(methods, mtd)
+	}
+	for _, mtd := range mdl.Methods {
+		if methods.ByName(mtd.Name) == nil {
+			Warnf("Model %q: method %q is not declared by %s", mdl.Name, mtd.Name, mdl.Kind)
+			methods = append(methods, mtd)
+		}
+	}
+	mdl.Methods = methods
+
+	for name, value := range NewScavengeOptions(mdl.Kind) {
+		if mdl.Options == nil {
+			mdl.Options = make(map[string]string)
+		}
+		if _, ok := mdl.Options[name]; !ok {
+			mdl.Options[name] = value
+		}
+	}
+
+	if migrator, ok := handler.(ModelMigrator); ok {
+		migrator.MigrateModel(mdl)
+	}
+}
+
 // AddMeta populates a spec with meta.
 func (spec *XSpec) AddMeta() error {
 	for _, mdl := range spec.Models {
@@ -444,6 +575,12 @@ func (spec *XSpec) Validate() error {
 			names = append(names, mdl.Name)
 		}
 	}
+	// Validate content-type rules:
+	for i, rule := range spec.ContentTypeRules {
+		if strings.TrimSpace(rule.Substring) == "" || strings.TrimSpace(rule.ContentType) == "" {
+			return fmt.Errorf("content-type rule #%v is not valid: substring and content-type must not be empty", i)
+		}
+	}
 
 	// Validate models:
 	for _, mdl := range spec.Models {
@@ -621,6 +758,19 @@ func (mdl *XModel) ModifyMethodByName(
 	return fmt.Errorf("Method %q (on model %q) not found", name, mdl.Name)
 }
 
+// SetOption sets the value of an option declared by the ModelKind.
+func (mdl *XModel) SetOption(name string, value string) error {
+	if _, ok := mdl.Options[name]; !ok {
+		return fmt.Errorf("Option %q (on model %q) not found", name, mdl.Name)
+	}
+	mdl.Options[name] = value
+	return nil
+}
+
+func (mdl *XModel) GetOption(name string) string {
+	return mdl.Options[name]
+}
+
 //
 func (mt *XMethod) GetStructSelector(
 	path string,
@@ -741,6 +891,8 @@ func (spec *XSpec) PushModel(model *XModel) error {
 	}
 
 	model.Methods = NewScavengeMethods(model.Kind)
+	model.Options = NewScavengeOptions(model.Kind)
+	model.spec = spec
 
 	{
 		spec.Lock()
@@ -766,6 +918,17 @@ type XModel struct {
 	Name    string // Name is user-defined.
 	Kind    ModelKind
 	Methods XMethodSlice
+	Options map[string]string `json:",omitempty"` // Options are user-defined values for the options declared by the ModelKind.
+
+	spec *XSpec // The spec that contains the model (see ContentTypeRules).
+}
+
+// ContentTypeRules returns the content-type rules of the spec that contains the model.
+func (mdl *XModel) ContentTypeRules() []*ContentTypeRule {
+	if mdl.spec == nil {
+		return nil
+	}
+	return mdl.spec.ContentTypeRules
 }
 
 type XMethod struct {
@@ -798,6 +961,13 @@ type FuncQualifier struct {
 
 	Name     string                     // Name of the func.
 	Elements *FuncQualifierElementsMeta `json:",omitempty"`
+
+	ContentType string `json:",omitempty"` // ContentType overrides the content-type inferred for the func; used depending on the ModelKind.
+	Algorithm   string `json:",omitempty"` // Algorithm is the cryptographic algorithm of the func; used depending on the ModelKind.
+
+	// RendererContentTypes overrides the content-type inferred for the renderer types
+	// (by `<pkgPath>.<TypeName>`) passed to the func; used depending on the ModelKind.
+	RendererContentTypes map[string]string `json:",omitempty"`
 }
 type TypeQualifier struct {
 	BasicQualifier
@@ -1256,6 +1426,14 @@ type ModelKindHandler interface {
 	Validate(mdl *XModel) error
 }
 
+// ModelOptionsHandler is implemented by a ModelKindHandler
+// that supports user-defined options on its models.
+type ModelOptionsHandler interface {
+	// ScavengeOptions returns the options unique to the ModelKind,
+	// mapped to their default values.
+	ScavengeOptions() map[string]string
+}
+
 type PackageLoader func(path string, version string) (*feparser.FEPackage, error)
 
 func TryLoadSpecFromFile(path string, loader PackageLoader) (*XSpec, error) {
@@ -1264,6 +1442,9 @@ func TryLoadSpecFromFile(path string, loader PackageLoader) (*XSpec, error) {
 	if err != nil {
 		return nil, fmt.Errorf("error while loading spec file: %s", err)
 	}
+	for _, mdl := range spec.Models {
+		mdl.spec = spec
+	}
 	// TODO:
 	// - validate names
 	// - validate classes
@@ -1276,6 +1457,9 @@ func TryLoadSpecFromFile(path string, loader PackageLoader) (*XSpec, error) {
 	if err := spec.Validate(); err != nil {
 		return nil, err
 	}
+	if err := spec.Migrate(); err != nil {
+		return nil, err
+	}
 	if err := spec.Cleanup(); err != nil {
 		return nil, err
 	}
@@ -1471,6 +1655,100 @@ func FindType(path string, version string, id string) *feparser.FEType {
 	return FindTypeByID(source, id)
 }
 
+// ImplementingType is a named (non-interface) type that implements an interface.
+type ImplementingType struct {
+	*feparser.FEType
+	IsPtr bool // Only the pointer to the type implements the interface.
+}
+
+// FindImplementingTypes returns the named (non-interface) types of all the cached
+// sources that implement the provided interface, sorted by package path and name.
+// NOTE: the sources are loaded separately (i.e. they don't share go/types objects),
+// so the methods are compared by name and signature string.
+func FindImplementingTypes(iface *types.Interface) []*ImplementingType {
+	res := make([]*ImplementingType, 0)
+	seen := make(map[string]bool)
+
+	for _, pv := range GetListCachedSources() {
+		source := GetCachedSource(pv.Path, pv.Version)
+		if source == nil {
+			continue
+		}
+		for _, typ := range source.Types {
+			named, ok := typ.GetOriginal().GetType().(*types.Named)
+			if !ok {
+				continue
+			}
+			if _, isInterface := named.Underlying().(*types.Interface); isInterface {
+				continue
+			}
+			key := typ.PkgPath + "." + typ.TypeName
+			if seen[key] {
+				continue
+			}
+
+			if hasMethodsOf(types.NewMethodSet(named), iface) {
+				res = append(res, &ImplementingType{FEType: typ})
+				seen[key] = true
+			} else if hasMethodsOf(types.NewMethodSet(types.NewPointer(named)), iface) {
+				res = append(res, &ImplementingType{FEType: typ, IsPtr: true})
+				seen[key] = true
+			}
+		}
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].PkgPath == res[j].PkgPath {
+			return res[i].TypeName < res[j].TypeName
+		}
+		return res[i].PkgPath < res[j].PkgPath
+	})
+	return res
+}
+
+// hasMethodsOf returns true if the method set contains all the methods of the interface.
+func hasMethodsOf(ms *types.MethodSet, iface *types.Interface) bool {
+	for i := 0; i < iface.NumMethods(); i++ {
+		want := iface.Method(i)
+		found := false
+		for j := 0; j < ms.Len(); j++ {
+			got := ms.At(j).Obj()
+			if got.Name() == want.Name() && signatureKey(got.Type()) == signatureKey(want.Type()) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// signatureKey formats the parameter and result types of a signature
+// (ignoring the receiver and the names of the parameters).
+func signatureKey(typ types.Type) string {
+	sig, ok := typ.(*types.Signature)
+	if !ok {
+		return ""
+	}
+	qualifier := func(pkg *types.Package) string {
+		return pkg.Path()
+	}
+	formatTuple := func(tuple *types.Tuple) string {
+		parts := make([]string, tuple.Len())
+		for i := range parts {
+			parts[i] = types.TypeString(tuple.At(i).Type(), qualifier)
+		}
+		return "(" + strings.Join(parts, ", ") + ")"
+	}
+	key := formatTuple(sig.Params()) + formatTuple(sig.Results())
+	if sig.Variadic() {
+		key += "..."
+	}
+	return key
+}
+
 type FuncQualifierSlice []*FuncQualifier
 
 //
@@ -2227,6 +2505,96 @@ func MustPosToRelativeParamIndexes(fe FuncInterface, positions []bool) []int {
 	return indexes
 }
 
+// ValidateParams checks that each selector of the method is a func selector
+// with only parameters selected (exactly one, if single is true).
+func ValidateParams(mtd *XMethod, single bool) error {
+	for _, sel := range mtd.Selectors {
+		qual := sel.GetFuncQualifier()
+		if qual == nil {
+			return fmt.Errorf("method %s supports only func selectors", mtd.Name)
+		}
+		fn := GetFuncByQualifier(qual)
+		receiver, parameterIndexes, resultIndexes := PosToRelativeIndexes(fn, qual.Pos)
+		if receiver || len(resultIndexes) > 0 {
+			return fmt.Errorf("%s: only parameters can be selected in %s", qual.ID, mtd.Name)
+		}
+		if single && len(parameterIndexes) != 1 {
+			return fmt.Errorf("%s: expected 1 parameter selected in %s, got %v", qual.ID, mtd.Name, len(parameterIndexes))
+		}
+	}
+	return nil
+}
+
+// ValidateSingleElement checks that each selector of the method is a func selector
+// with exactly one element selected, of one of the provided element types.
+func ValidateSingleElement(mtd *XMethod, allowed ...feparser.Element) error {
+	for _, sel := range mtd.Selectors {
+		qual := sel.GetFuncQualifier()
+		if qual == nil {
+			return fmt.Errorf("method %s supports only func selectors", mtd.Name)
+		}
+		fn := GetFuncByQualifier(qual)
+		selected := make([]feparser.Element, 0)
+		for index, pos := range qual.Pos {
+			if !pos {
+				continue
+			}
+			elTyp, _, _, err := fn.GetRelativeElement(index)
+			if err != nil {
+				return fmt.Errorf("%s: %s", qual.ID, err)
+			}
+			selected = append(selected, elTyp)
+		}
+		if len(selected) != 1 {
+			return fmt.Errorf("%s: expected 1 element selected in %s, got %v", qual.ID, mtd.Name, len(selected))
+		}
+		isAllowed := false
+		for _, elTyp := range allowed {
+			if selected[0] == elTyp {
+				isAllowed = true
+			}
+		}
+		if !isAllowed {
+			return fmt.Errorf("%s: a %s cannot be selected in %s", qual.ID, selected[0], mtd.Name)
+		}
+	}
+	return nil
+}
+
+// ValidateCoupled checks that each func selected in the method
+// is also selected in the coupled method.
+func ValidateCoupled(mtd *XMethod, coupled *XMethod) error {
+	for _, sel := range mtd.Selectors {
+		qual := sel.GetFuncQualifier()
+		if qual == nil {
+			return fmt.Errorf("method %s supports only func selectors", mtd.Name)
+		}
+		if coupled.GetFuncSelector(qual.Path, qual.Version, qual.ID) == nil {
+			return fmt.Errorf("%s: selected in %s but not in %s", qual.ID, mtd.Name, coupled.Name)
+		}
+	}
+	return nil
+}
+
+// ValidateDistinctParams checks that each func selected in the method
+// is also selected in the coupled method, and that the two methods
+// select different parameters of it (e.g. a header key and value).
+func ValidateDistinctParams(mtd *XMethod, coupled *XMethod) error {
+	if err := ValidateCoupled(mtd, coupled); err != nil {
+		return err
+	}
+	for _, sel := range mtd.Selectors {
+		qual := sel.GetFuncQualifier()
+		coupledQual := coupled.GetFuncSelector(qual.Path, qual.Version, qual.ID)
+		for i := range qual.Pos {
+			if i < len(coupledQual.Pos) && qual.Pos[i] && coupledQual.Pos[i] {
+				return fmt.Errorf("%s: %s and %s must select different parameters", qual.ID, mtd.Name, coupled.Name)
+			}
+		}
+	}
+	return nil
+}
+
 func ScavengeMethods(methodNames ...string) []*XMethod {
 	methods := make([]*XMethod, 0)
 
@@ -2359,7 +2727,125 @@ func GuessContentTypeFromName(name string) string {
 		// NOTE: this might be not correct.
 		return "text/plain"
 	}
-	return "TODO"
+	// Unknown:
+	return ""
+}
+
+// InferContentType returns the content-type of the func selected by the qualifier:
+// the ContentType override of the qualifier is used if set; otherwise, the content-type
+// of the first of the provided rules (usually the ones of the spec) matching the func name;
+// otherwise, the one guessed by GuessContentTypeFromName.
+// An empty string is returned if the content-type cannot be inferred.
+func InferContentType(rules []*ContentTypeRule, qual *FuncQualifier, funcName string) string {
+	if qual != nil && qual.ContentType != "" {
+		return qual.ContentType
+	}
+
+	lowerName := strings.ToLower(funcName)
+	for _, rule := range rules {
+		if rule.Substring != "" && strings.Contains(lowerName, strings.ToLower(rule.Substring)) {
+			return rule.ContentType
+		}
+	}
+
+	return GuessContentTypeFromName(funcName)
+}
+
+// GuessCryptoAlgorithmFromName returns the name of the cryptographic algorithm
+// (as known by CodeQL's CryptographicAlgorithm) used by a func with the provided name;
+// an empty string is returned if the algorithm cannot be inferred.
+// The name is matched word by word (see splitCamelCase), instead of by substring.
+func GuessCryptoAlgorithmFromName(name string) string {
+	words := splitCamelCase(name)
+	for i, word := range words {
+		word = strings.ToLower(word)
+		switch word {
+		case "md4":
+			return "MD4"
+		case "md5":
+			return "MD5"
+		case "sha512":
+			return "SHA512"
+		case "sha384":
+			return "SHA384"
+		case "sha256":
+			return "SHA256"
+		case "sha224":
+			return "SHA224"
+		case "sha3":
+			return "SHA3"
+		case "sha1", "sha":
+			return "SHA1"
+		case "rc4":
+			return "RC4"
+		case "tripledes", "3des":
+			return "TRIPLEDES"
+		case "triple", "3":
+			// e.g. `TripleDES`, `3DES`:
+			if i+1 < len(words) && strings.ToLower(words[i+1]) == "des" {
+				return "TRIPLEDES"
+			}
+		case "des":
+			return "DES"
+		case "blowfish":
+			return "BLOWFISH"
+		case "bcrypt":
+			return "BCRYPT"
+		case "scrypt":
+			return "SCRYPT"
+		case "argon2", "argon2i", "argon2id":
+			return "ARGON2"
+		case "pbkdf2":
+			return "PBKDF2"
+		case "ecdsa":
+			return "ECDSA"
+		case "ed25519":
+			return "ED25519"
+		}
+		// Key sizes can follow the name (e.g. `AES256`, `RSA2048`):
+		switch strings.TrimRight(word, "0123456789") {
+		case "aes":
+			return "AES"
+		case "rsa":
+			return "RSA"
+		}
+	}
+	return ""
+}
+
+// IsKnownCryptoAlgorithm returns true if the provided name is one of the
+// algorithm names returned by GuessCryptoAlgorithmFromName (e.g. `SHA256`).
+func IsKnownCryptoAlgorithm(name string) bool {
+	return name != "" && GuessCryptoAlgorithmFromName(name) == name
+}
+
+// splitCamelCase splits a name like `TripleDESEncrypt_v2` into `Triple`, `DES`, `Encrypt`, `v2`.
+func splitCamelCase(name string) []string {
+	words := make([]string, 0)
+	runes := []rune(name)
+	start := 0
+	for i := 0; i < len(runes); i++ {
+		if runes[i] == '_' {
+			if i > start {
+				words = append(words, string(runes[start:i]))
+			}
+			start = i + 1
+			continue
+		}
+		if i == start || !unicode.IsUpper(runes[i]) {
+			continue
+		}
+		prevIsUpper := unicode.IsUpper(runes[i-1])
+		nextIsLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+		if !prevIsUpper || nextIsLower {
+			words = append(words, string(runes[start:i]))
+			start = i
+		}
+	}
+	if start < len(runes) {
+		words = append(words, string(runes[start:]))
+	}
+	return words
 }
 
 func (spec *XSpec) AppearsIn(path string, version string, id string) []string {